@@ -0,0 +1,187 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+// ExternalSignerNamespace is the RPC namespace of the signing protocol served
+// by external signers. The protocol has three methods:
+//
+//	account_list()                      the addresses of the signer's accounts
+//	account_signTransaction(SignTxArgs) the signed transaction as SignTxResult
+//	account_signHash(address, hash)     the 65 byte [R || S || V] signature
+//
+// The signer decides on each request, typically by asking its user, and
+// returns an error if it is rejected.
+const ExternalSignerNamespace = "account"
+
+var ErrExternalSigner = errors.New("accounts are managed by an external signer")
+
+// SignTxArgs is a transaction to be signed by an external signer. ChainID is
+// set if the transaction must be protected against replay on other chains
+// (EIP-155).
+type SignTxArgs struct {
+	From     common.Address  `json:"from"`
+	To       *common.Address `json:"to"`
+	Nonce    *rpc.HexNumber  `json:"nonce"`
+	Gas      *rpc.HexNumber  `json:"gas"`
+	GasPrice *rpc.HexNumber  `json:"gasPrice"`
+	Value    *rpc.HexNumber  `json:"value"`
+	Data     string          `json:"data"`
+	ChainID  *rpc.HexNumber  `json:"chainId"`
+}
+
+// NewSignTxArgs returns the signing request of a transaction from the given
+// account. The chain id is taken from the signer, which is nil for transactions
+// without replay protection.
+func NewSignTxArgs(from common.Address, tx *types.Transaction, signer types.Signer) SignTxArgs {
+	args := SignTxArgs{
+		From:     from,
+		To:       tx.To(),
+		Nonce:    rpc.NewHexNumber(tx.Nonce()),
+		Gas:      rpc.NewHexNumber(tx.Gas()),
+		GasPrice: rpc.NewHexNumber(tx.GasPrice()),
+		Value:    rpc.NewHexNumber(tx.Value()),
+		Data:     "0x" + hex.EncodeToString(tx.Data()),
+	}
+	if s, ok := signer.(types.ChainIdSigner); ok {
+		args.ChainID = rpc.NewHexNumber(s.ChainId())
+	}
+	return args
+}
+
+// Transaction returns the unsigned transaction of the request and the signer
+// it must be signed with.
+func (args *SignTxArgs) Transaction() (*types.Transaction, types.Signer, error) {
+	if args.Nonce == nil || args.Gas == nil || args.GasPrice == nil || args.Value == nil {
+		return nil, nil, errors.New("nonce, gas, gasPrice and value are required")
+	}
+	var tx *types.Transaction
+	if args.To == nil {
+		tx = types.NewContractCreation(args.Nonce.Uint64(), args.Value.BigInt(), args.Gas.BigInt(), args.GasPrice.BigInt(), common.FromHex(args.Data))
+	} else {
+		tx = types.NewTransaction(args.Nonce.Uint64(), *args.To, args.Value.BigInt(), args.Gas.BigInt(), args.GasPrice.BigInt(), common.FromHex(args.Data))
+	}
+	var signer types.Signer = types.BasicSigner{}
+	if args.ChainID != nil {
+		signer = types.NewChainIdSigner(args.ChainID.BigInt())
+	}
+	tx.SetSigner(signer)
+	return tx, signer, nil
+}
+
+// SignTxResult is a transaction signed by an external signer.
+type SignTxResult struct {
+	Raw  string      `json:"raw"`
+	Hash common.Hash `json:"hash"`
+}
+
+// ExternalSigner is a client of an external signer process, which holds the
+// keys of the accounts and approves every signature.
+type ExternalSigner struct {
+	endpoint string
+	client   *rpc.ClientConn
+}
+
+// NewExternalSigner connects to the external signer at the given endpoint, an
+// IPC socket path or a http:// URL.
+func NewExternalSigner(endpoint string) (*ExternalSigner, error) {
+	client, err := rpc.Dial(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return &ExternalSigner{endpoint: endpoint, client: client}, nil
+}
+
+// Endpoint returns the endpoint of the signer.
+func (s *ExternalSigner) Endpoint() string {
+	return s.endpoint
+}
+
+// Close closes the connection to the signer.
+func (s *ExternalSigner) Close() {
+	s.client.Close()
+}
+
+// Accounts returns the addresses of the accounts of the signer.
+func (s *ExternalSigner) Accounts() ([]common.Address, error) {
+	var addrs []common.Address
+	if err := s.client.Call(&addrs, ExternalSignerNamespace+"_list"); err != nil {
+		return nil, err
+	}
+	return addrs, nil
+}
+
+// SignHash asks the signer to sign the given hash with the key of an account.
+func (s *ExternalSigner) SignHash(addr common.Address, hash []byte) ([]byte, error) {
+	if len(hash) != common.HashLength {
+		return nil, fmt.Errorf("hash is required to be exactly %d bytes (%d)", common.HashLength, len(hash))
+	}
+	var sig string
+	if err := s.client.Call(&sig, ExternalSignerNamespace+"_signHash", addr, common.BytesToHash(hash)); err != nil {
+		return nil, err
+	}
+	signature := common.FromHex(sig)
+	if len(signature) != 65 {
+		return nil, fmt.Errorf("external signer returned a signature of %d bytes", len(signature))
+	}
+	return signature, nil
+}
+
+// SignTx asks the signer to sign a transaction from an account. The returned
+// transaction is checked to be the requested one, signed by the account.
+func (s *ExternalSigner) SignTx(addr common.Address, tx *types.Transaction, signer types.Signer) (*types.Transaction, error) {
+	var res SignTxResult
+	if err := s.client.Call(&res, ExternalSignerNamespace+"_signTransaction", NewSignTxArgs(addr, tx, signer)); err != nil {
+		return nil, err
+	}
+	signed := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(res.Raw), signed); err != nil {
+		return nil, fmt.Errorf("external signer returned an invalid transaction: %v", err)
+	}
+	signed.SetSigner(signer)
+	if from, err := signed.From(); err != nil || from != addr {
+		return nil, fmt.Errorf("external signer returned a transaction not signed by %x", addr)
+	}
+	if signer.Hash(signed) != signer.Hash(tx) {
+		return nil, errors.New("external signer returned a different transaction")
+	}
+	return signed, nil
+}
+
+// hasAddress reports whether the signer holds the given account.
+func (s *ExternalSigner) hasAddress(addr common.Address) bool {
+	addrs, err := s.Accounts()
+	if err != nil {
+		return false
+	}
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,145 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"crypto/ecdsa"
+	"crypto/hmac"
+	"crypto/sha512"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/crypto/secp256k1"
+)
+
+// hardenedOffset is the first index of the hardened BIP-32 child keys.
+const hardenedOffset = 0x80000000
+
+var (
+	// DefaultRootDerivationPath is the BIP-44 root path of the accounts of HD
+	// wallets, using the Ethereum Classic coin type 61. The accounts are derived
+	// by appending their index to the root path.
+	DefaultRootDerivationPath = DerivationPath{hardenedOffset + 44, hardenedOffset + 61, hardenedOffset + 0, 0}
+
+	// EthereumRootDerivationPath is the BIP-44 root path using the Ethereum coin
+	// type 60, to access the accounts of wallets created for Ethereum.
+	EthereumRootDerivationPath = DerivationPath{hardenedOffset + 44, hardenedOffset + 60, hardenedOffset + 0, 0}
+)
+
+var errInvalidChildKey = errors.New("invalid BIP-32 child key, use the next index")
+
+// DerivationPath is a BIP-32 path to a key derived from the seed of a HD
+// wallet. Indexes from 0x80000000 up denote hardened child keys.
+type DerivationPath []uint32
+
+// ParseDerivationPath parses a derivation path like m/44'/61'/0'/0. Hardened
+// indexes are marked with an apostrophe.
+func ParseDerivationPath(path string) (DerivationPath, error) {
+	components := strings.Split(strings.TrimSpace(path), "/")
+	if len(components) < 2 || components[0] != "m" {
+		return nil, fmt.Errorf("derivation path %q doesn't start with m/", path)
+	}
+	var result DerivationPath
+	for _, component := range components[1:] {
+		offset := uint64(0)
+		if strings.HasSuffix(component, "'") {
+			offset = hardenedOffset
+			component = strings.TrimSuffix(component, "'")
+		}
+		index, err := strconv.ParseUint(component, 10, 32)
+		if err != nil || index >= hardenedOffset {
+			return nil, fmt.Errorf("invalid component %q in derivation path %q", component, path)
+		}
+		result = append(result, uint32(index+offset))
+	}
+	return result, nil
+}
+
+// String returns the derivation path in the m/44'/61'/0'/0 notation.
+func (path DerivationPath) String() string {
+	result := "m"
+	for _, index := range path {
+		if index >= hardenedOffset {
+			result += fmt.Sprintf("/%d'", index-hardenedOffset)
+		} else {
+			result += fmt.Sprintf("/%d", index)
+		}
+	}
+	return result
+}
+
+// Child returns the path of the given child of the path.
+func (path DerivationPath) Child(index uint32) DerivationPath {
+	child := make(DerivationPath, len(path), len(path)+1)
+	copy(child, path)
+	return append(child, index)
+}
+
+// deriveKey derives the private key at the given path from a BIP-32 seed.
+func deriveKey(seed []byte, path DerivationPath) (*ecdsa.PrivateKey, error) {
+	mac := hmac.New(sha512.New, []byte("Bitcoin seed"))
+	mac.Write(seed)
+	sum := mac.Sum(nil)
+
+	key, chainCode := new(big.Int).SetBytes(sum[:32]), sum[32:]
+	n := secp256k1.S256().Params().N
+	if key.Sign() == 0 || key.Cmp(n) >= 0 {
+		return nil, errors.New("invalid BIP-32 master key")
+	}
+	for _, index := range path {
+		var data []byte
+		if index >= hardenedOffset {
+			data = append([]byte{0}, common.LeftPadBytes(key.Bytes(), 32)...)
+		} else {
+			data = compressPubkey(crypto.ToECDSA(common.LeftPadBytes(key.Bytes(), 32)))
+		}
+		var enc [4]byte
+		binary.BigEndian.PutUint32(enc[:], index)
+
+		mac := hmac.New(sha512.New, chainCode)
+		mac.Write(data)
+		mac.Write(enc[:])
+		sum := mac.Sum(nil)
+
+		tweak := new(big.Int).SetBytes(sum[:32])
+		if tweak.Cmp(n) >= 0 {
+			return nil, errInvalidChildKey
+		}
+		key = tweak.Add(tweak, key).Mod(tweak, n)
+		if key.Sign() == 0 {
+			return nil, errInvalidChildKey
+		}
+		chainCode = sum[32:]
+	}
+	return crypto.ToECDSA(common.LeftPadBytes(key.Bytes(), 32)), nil
+}
+
+// compressPubkey encodes the public key of the given key in the 33 byte
+// compressed form.
+func compressPubkey(key *ecdsa.PrivateKey) []byte {
+	format := byte(0x02)
+	if key.PublicKey.Y.Bit(0) == 1 {
+		format = 0x03
+	}
+	return append([]byte{format}, common.LeftPadBytes(key.PublicKey.X.Bytes(), 32)...)
+}
@@ -0,0 +1,185 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"encoding/hex"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/crypto"
+)
+
+const testMnemonic = "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about"
+
+func TestParseDerivationPath(t *testing.T) {
+	tests := []struct {
+		input string
+		want  DerivationPath
+	}{
+		{"m/44'/61'/0'/0", DefaultRootDerivationPath},
+		{"m/44'/60'/0'/0", EthereumRootDerivationPath},
+		{"m/0", DerivationPath{0}},
+		{"m/2147483647'/1", DerivationPath{0xffffffff, 1}},
+	}
+	for _, test := range tests {
+		path, err := ParseDerivationPath(test.input)
+		if err != nil {
+			t.Errorf("%s: %v", test.input, err)
+			continue
+		}
+		if !reflect.DeepEqual(path, test.want) {
+			t.Errorf("%s: have %v, want %v", test.input, path, test.want)
+		}
+		if path.String() != test.input {
+			t.Errorf("%s: formatted as %s", test.input, path)
+		}
+	}
+	for _, invalid := range []string{"", "m", "44'/61'", "m/", "m/x", "m/-1", "m/2147483648", "m/1''"} {
+		if _, err := ParseDerivationPath(invalid); err == nil {
+			t.Errorf("%q: expected error", invalid)
+		}
+	}
+}
+
+// Tests the derivation against test vector 1 of BIP-32.
+func TestDeriveKey(t *testing.T) {
+	seed, _ := hex.DecodeString("000102030405060708090a0b0c0d0e0f")
+	tests := []struct {
+		path string
+		key  string
+	}{
+		{"m/0'", "edb2e14f9ee77d26dd93b4ecede8d16ed408ce149b6cd80b0715a2d911a0afea"},
+		{"m/0'/1", "3c6cb8d0f6a264c91ea8b5030fadaa8e538b020f0a387421a12de9319dc93368"},
+		{"m/0'/1/2'", "cbce0d719ecf7431d88e6a89fa1483e02e35092af60c042b1df2ff59fa424dca"},
+		{"m/0'/1/2'/2", "0f479245fb19a38a1954c5c7c0ebab2f9bdfd96a17563ef28a6a4b1a2a764ef4"},
+		{"m/0'/1/2'/2/1000000000", "471b76e389e528d6de6d816857e012c5455051cad6660850e58372a6c3e6e7c8"},
+	}
+	for _, test := range tests {
+		path, _ := ParseDerivationPath(test.path)
+		key, err := deriveKey(seed, path)
+		if err != nil {
+			t.Errorf("%s: %v", test.path, err)
+			continue
+		}
+		if have := hex.EncodeToString(common.LeftPadBytes(key.D.Bytes(), 32)); have != test.key {
+			t.Errorf("%s: have key %s, want %s", test.path, have, test.key)
+		}
+	}
+}
+
+func TestHDWallet(t *testing.T) {
+	dir, am := tmpManager(t)
+	defer os.RemoveAll(dir)
+
+	// The first account of the mnemonic along the Ethereum path is well known
+	a, err := am.ImportHDWallet(" "+strings.Replace(testMnemonic, " ", "  ", -1), "foo", EthereumRootDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToAddress("0x9858EfFD232B4033E47d90003D41EC34EcaEda94"); a.Address != want {
+		t.Fatalf("account mismatch: have %x, want %x", a.Address, want)
+	}
+	if _, err := am.ImportHDWallet(testMnemonic, "bar", EthereumRootDerivationPath); err != ErrHDWalletExists {
+		t.Errorf("duplicate wallet: have error %v, want %v", err, ErrHDWalletExists)
+	}
+	if _, err := am.ImportHDWallet("abandon abandon abandon", "foo", EthereumRootDerivationPath); err == nil {
+		t.Error("invalid mnemonic accepted")
+	}
+
+	if _, err := am.DeriveHDAccount(a, "bar"); err == nil {
+		t.Fatal("derived account with wrong passphrase")
+	}
+	b, err := am.DeriveHDAccount(a, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Address == a.Address || b.File != a.File {
+		t.Fatalf("derived account mismatch: %v, first %v", b, a)
+	}
+	if !am.HasAddress(b.Address) {
+		t.Error("derived account missing")
+	}
+	if accounts := am.Accounts(); !reflect.DeepEqual(accounts, []Account{a, b}) {
+		t.Errorf("accounts mismatch: have %v, want %v", accounts, []Account{a, b})
+	}
+
+	hash := crypto.Keccak256([]byte("hello"))
+	sig, err := am.SignWithPassphrase(b.Address, "foo", hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := crypto.Ecrecover(hash, sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := common.BytesToAddress(crypto.Keccak256(pub[1:])[12:]); addr != b.Address {
+		t.Errorf("signer mismatch: have %x, want %x", addr, b.Address)
+	}
+
+	if err := am.Update(a, "foo", "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if err := am.DeleteAccount(b, "bar"); err != errHDAccount {
+		t.Errorf("delete: have error %v, want %v", err, errHDAccount)
+	}
+
+	// The wallet is loaded from its file with the new passphrase
+	am2, err := NewManager(dir, veryLightScryptN, veryLightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wallets := am2.HDWallets()
+	if len(wallets) != 1 || wallets[0].Path.String() != "m/44'/60'/0'/0" || !reflect.DeepEqual(wallets[0].Accounts, []Account{a, b}) {
+		t.Fatalf("wallets mismatch: %+v", wallets)
+	}
+	if err := am2.Unlock(b, "foo"); err == nil {
+		t.Error("unlocked with old passphrase")
+	}
+	if err := am2.Unlock(b, "bar"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := am2.Sign(b.Address, hash); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNewHDWallet(t *testing.T) {
+	dir, am := tmpManager(t)
+	defer os.RemoveAll(dir)
+
+	mnemonic, a, err := am.NewHDWallet("foo", DefaultRootDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if words := strings.Fields(mnemonic); len(words) != 24 {
+		t.Errorf("mnemonic has %d words, want 24", len(words))
+	}
+	// Importing the mnemonic restores the same account
+	dir2, am2 := tmpManager(t)
+	defer os.RemoveAll(dir2)
+	b, err := am2.ImportHDWallet(mnemonic, "bar", DefaultRootDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Address != a.Address {
+		t.Errorf("restored account mismatch: have %x, want %x", b.Address, a.Address)
+	}
+}
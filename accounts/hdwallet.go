@@ -0,0 +1,419 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// hdWalletDir is the directory of the HD wallet files in the key directory.
+const hdWalletDir = "hd"
+
+// hdMnemonicBits is the entropy of the mnemonics of new HD wallets, resulting
+// in 24 words.
+const hdMnemonicBits = 256
+
+var (
+	ErrHDWalletExists = errors.New("HD wallet already exists")
+
+	errHDAccount = errors.New("account belongs to a HD wallet, delete the wallet file instead")
+)
+
+// HDWallet is a hierarchical deterministic wallet. Its accounts are derived
+// from the seed of a BIP-39 mnemonic along a BIP-44 root derivation path.
+type HDWallet struct {
+	ID       string
+	File     string
+	Path     DerivationPath
+	Accounts []Account
+}
+
+// hdWallet is a HD wallet loaded from its file.
+type hdWallet struct {
+	id       string
+	file     string
+	path     DerivationPath
+	crypto   cryptoJSON // encrypted mnemonic
+	accounts []hdAccount
+}
+
+// hdAccount is a derived account of a HD wallet.
+type hdAccount struct {
+	address common.Address
+	index   uint32
+}
+
+// hdWalletJSON is the file format of a HD wallet. The mnemonic is encrypted
+// like the private keys of the key files, the addresses are stored in plain
+// text so that the accounts can be listed without the passphrase.
+type hdWalletJSON struct {
+	ID       string          `json:"id"`
+	Version  int             `json:"version"`
+	Path     string          `json:"path"`
+	Accounts []hdAccountJSON `json:"accounts"`
+	Crypto   cryptoJSON      `json:"crypto"`
+}
+
+type hdAccountJSON struct {
+	Address string `json:"address"`
+	Index   uint32 `json:"index"`
+}
+
+// hdStore holds the HD wallets of a key directory.
+type hdStore struct {
+	dir     string
+	mu      sync.RWMutex
+	wallets []*hdWallet
+}
+
+// newHDStore loads the HD wallets stored in the given directory.
+func newHDStore(dir string) *hdStore {
+	store := &hdStore{dir: dir}
+
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			glog.V(logger.Warn).Infof("can't read HD wallets: %v", err)
+		}
+		return store
+	}
+	for _, fi := range files {
+		if skipKeyFile(fi) {
+			continue
+		}
+		path := filepath.Join(dir, fi.Name())
+		w, err := loadHDWallet(path)
+		if err != nil {
+			glog.V(logger.Debug).Infof("can't decode HD wallet %s: %v", path, err)
+			continue
+		}
+		store.wallets = append(store.wallets, w)
+	}
+	return store
+}
+
+func loadHDWallet(file string) (*hdWallet, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	var enc hdWalletJSON
+	if err := json.Unmarshal(data, &enc); err != nil {
+		return nil, err
+	}
+	if enc.Version != 1 {
+		return nil, fmt.Errorf("unsupported HD wallet version %d", enc.Version)
+	}
+	path, err := ParseDerivationPath(enc.Path)
+	if err != nil {
+		return nil, err
+	}
+	w := &hdWallet{id: enc.ID, file: file, path: path, crypto: enc.Crypto}
+	for _, account := range enc.Accounts {
+		if !common.IsHexAddress(account.Address) {
+			return nil, fmt.Errorf("invalid account address %q", account.Address)
+		}
+		w.accounts = append(w.accounts, hdAccount{common.HexToAddress(account.Address), account.Index})
+	}
+	return w, nil
+}
+
+// store writes the wallet into its file.
+func (w *hdWallet) store() error {
+	enc := hdWalletJSON{
+		ID:      w.id,
+		Version: 1,
+		Path:    w.path.String(),
+		Crypto:  w.crypto,
+	}
+	for _, account := range w.accounts {
+		enc.Accounts = append(enc.Accounts, hdAccountJSON{hex.EncodeToString(account.address[:]), account.index})
+	}
+	data, err := json.Marshal(enc)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(w.file), 0700); err != nil {
+		return err
+	}
+	return writeKeyFile(w.file, data)
+}
+
+// mnemonic decrypts the mnemonic of the wallet.
+func (w *hdWallet) mnemonic(passphrase string) (string, error) {
+	data, err := decryptData(w.crypto, passphrase)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// deriveKey derives the key of the account with the given index.
+func (w *hdWallet) deriveKey(mnemonic string, index uint32) (*key, error) {
+	seed, err := bip39.NewSeedWithErrorChecking(mnemonic, "")
+	if err != nil {
+		return nil, err
+	}
+	privateKey, err := deriveKey(seed, w.path.Child(index))
+	if err != nil {
+		return nil, err
+	}
+	return &key{
+		UUID:       w.id,
+		Address:    crypto.PubkeyToAddress(privateKey.PublicKey),
+		PrivateKey: privateKey,
+	}, nil
+}
+
+// deriveNext derives the key of the account following the last one of the
+// wallet, skipping the indexes without a valid BIP-32 child key.
+func (w *hdWallet) deriveNext(mnemonic string) (*key, uint32, error) {
+	var index uint32
+	for _, account := range w.accounts {
+		if account.index >= index {
+			index = account.index + 1
+		}
+	}
+	for ; index < hardenedOffset; index++ {
+		key, err := w.deriveKey(mnemonic, index)
+		if err == errInvalidChildKey {
+			continue
+		}
+		return key, index, err
+	}
+	return nil, 0, errors.New("no account index left in HD wallet")
+}
+
+func (w *hdWallet) info() HDWallet {
+	info := HDWallet{ID: w.id, File: w.file, Path: w.path}
+	for _, account := range w.accounts {
+		info.Accounts = append(info.Accounts, Account{Address: account.address, File: w.file})
+	}
+	return info
+}
+
+// find returns the wallet holding the given address and the account index.
+func (store *hdStore) find(addr common.Address) (*hdWallet, uint32, bool) {
+	for _, w := range store.wallets {
+		for _, account := range w.accounts {
+			if account.address == addr {
+				return w, account.index, true
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+// hasAddress reports whether a wallet holds the given address.
+func (store *hdStore) hasAddress(addr common.Address) bool {
+	store.mu.RLock()
+	defer store.mu.RUnlock()
+
+	_, _, ok := store.find(addr)
+	return ok
+}
+
+// create stores a new wallet for the mnemonic holding its first account,
+// encrypted like the keys of the given key store.
+func (store *hdStore) create(mnemonic, passphrase string, path DerivationPath, keys *keyStore) (Account, error) {
+	id, err := newKeyUUID()
+	if err != nil {
+		return Account{}, err
+	}
+	w := &hdWallet{id: id, path: path}
+	key, index, err := w.deriveNext(mnemonic)
+	if err != nil {
+		return Account{}, err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	store.mu.Lock()
+	defer store.mu.Unlock()
+
+	if _, _, exists := store.find(key.Address); exists {
+		return Account{}, ErrHDWalletExists
+	}
+	if w.crypto, err = keys.encryptData([]byte(mnemonic), passphrase); err != nil {
+		return Account{}, err
+	}
+	w.accounts = []hdAccount{{key.Address, index}}
+
+	timestamp := time.Now().UTC().Format("2006-01-02T15-04-05.999999999")
+	w.file = filepath.Join(store.dir, fmt.Sprintf("UTC--%sZ--hd--%x", timestamp, key.Address[:]))
+	if err := w.store(); err != nil {
+		return Account{}, err
+	}
+	store.wallets = append(store.wallets, w)
+	return Account{Address: key.Address, File: w.file}, nil
+}
+
+// NewHDWallet creates a HD wallet from a new random mnemonic, encrypting it
+// with the passphrase, and derives its first account along the given root path.
+// The mnemonic is returned to be written down as a backup of the wallet.
+func (am *Manager) NewHDWallet(passphrase string, path DerivationPath) (string, Account, error) {
+	if am.external != nil {
+		return "", Account{}, ErrExternalSigner
+	}
+	entropy, err := bip39.NewEntropy(hdMnemonicBits)
+	if err != nil {
+		return "", Account{}, err
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return "", Account{}, err
+	}
+	account, err := am.hd.create(mnemonic, passphrase, path, &am.keyStore)
+	if err != nil {
+		return "", Account{}, err
+	}
+	return mnemonic, account, nil
+}
+
+// ImportHDWallet stores a HD wallet for the given BIP-39 mnemonic, encrypting
+// it with the passphrase, and derives its first account along the given root path.
+func (am *Manager) ImportHDWallet(mnemonic, passphrase string, path DerivationPath) (Account, error) {
+	if am.external != nil {
+		return Account{}, ErrExternalSigner
+	}
+	mnemonic = strings.Join(strings.Fields(mnemonic), " ")
+	if _, err := bip39.EntropyFromMnemonic(mnemonic); err != nil {
+		return Account{}, fmt.Errorf("invalid mnemonic: %v", err)
+	}
+	return am.hd.create(mnemonic, passphrase, path, &am.keyStore)
+}
+
+// DeriveHDAccount derives the next account of the HD wallet holding the given
+// account. The passphrase of the wallet is required to decrypt its mnemonic.
+func (am *Manager) DeriveHDAccount(a Account, passphrase string) (Account, error) {
+	if am.external != nil {
+		return Account{}, ErrExternalSigner
+	}
+	am.hd.mu.Lock()
+	defer am.hd.mu.Unlock()
+
+	w, _, ok := am.hd.find(a.Address)
+	if !ok {
+		return Account{}, ErrNoMatch
+	}
+	mnemonic, err := w.mnemonic(passphrase)
+	if err != nil {
+		return Account{}, err
+	}
+	key, index, err := w.deriveNext(mnemonic)
+	if err != nil {
+		return Account{}, err
+	}
+	defer zeroKey(key.PrivateKey)
+
+	w.accounts = append(w.accounts, hdAccount{key.Address, index})
+	if err := w.store(); err != nil {
+		w.accounts = w.accounts[:len(w.accounts)-1]
+		return Account{}, err
+	}
+	return Account{Address: key.Address, File: w.file}, nil
+}
+
+// HDWallets returns the HD wallets of the key directory, ordered by file name.
+func (am *Manager) HDWallets() []HDWallet {
+	am.hd.mu.RLock()
+	defer am.hd.mu.RUnlock()
+
+	wallets := make([]HDWallet, len(am.hd.wallets))
+	for i, w := range am.hd.wallets {
+		wallets[i] = w.info()
+	}
+	sort.Slice(wallets, func(i, j int) bool { return wallets[i].File < wallets[j].File })
+	return wallets
+}
+
+// hdAccounts returns the accounts of all HD wallets.
+func (am *Manager) hdAccounts() []Account {
+	var accounts []Account
+	for _, w := range am.HDWallets() {
+		accounts = append(accounts, w.Accounts...)
+	}
+	return accounts
+}
+
+// getDecryptedHDKey derives the key of an account of a HD wallet, decrypting
+// the wallet with the passphrase. It reports false if no HD wallet holds the
+// account.
+func (am *Manager) getDecryptedHDKey(a Account, passphrase string) (Account, *key, bool, error) {
+	am.hd.mu.RLock()
+	defer am.hd.mu.RUnlock()
+
+	w, index, ok := am.hd.find(a.Address)
+	if !ok || (a.File != "" && a.File != w.file) {
+		return Account{}, nil, false, nil
+	}
+	mnemonic, err := w.mnemonic(passphrase)
+	if err != nil {
+		return Account{}, nil, true, err
+	}
+	key, err := w.deriveKey(mnemonic, index)
+	if err != nil {
+		return Account{}, nil, true, err
+	}
+	if key.Address != a.Address {
+		zeroKey(key.PrivateKey)
+		return Account{}, nil, true, errAddrMismatch
+	}
+	return Account{Address: a.Address, File: w.file}, key, true, nil
+}
+
+// updateHDWallet re-encrypts the mnemonic of the HD wallet holding the given
+// account with a new passphrase.
+func (am *Manager) updateHDWallet(a Account, passphrase, newPassphrase string) (bool, error) {
+	am.hd.mu.Lock()
+	defer am.hd.mu.Unlock()
+
+	w, _, ok := am.hd.find(a.Address)
+	if !ok {
+		return false, nil
+	}
+	mnemonic, err := w.mnemonic(passphrase)
+	if err != nil {
+		return true, err
+	}
+	encrypted, err := am.keyStore.encryptData([]byte(mnemonic), newPassphrase)
+	if err != nil {
+		return true, err
+	}
+	prev := w.crypto
+	w.crypto = encrypted
+	if err := w.store(); err != nil {
+		w.crypto = prev
+		return true, err
+	}
+	return true, nil
+}
@@ -0,0 +1,127 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+)
+
+// KeyFileInfo describes the encryption of a key file or HD wallet file.
+type KeyFileInfo struct {
+	// Account is the account of a key file, or the first account of a HD wallet.
+	Account Account
+	HD      bool
+	// Version is the format version of the file, 3 or 1 for key files.
+	Version   string
+	Cipher    string
+	KDF       string
+	KDFParams map[string]interface{}
+	// Current reports whether the file is encrypted with the current parameters
+	// of the manager. Other files are re-encrypted by Upgrade.
+	Current bool
+	// Duplicates lists the other key files of the same address.
+	Duplicates []Account
+	// Err is set if the file can't be read.
+	Err error
+}
+
+// SetKDF selects the key derivation function of the keys encrypted from now on,
+// KDFScrypt or KDFArgon2id. The manager uses scrypt by default. Argon2id uses
+// the memory and parallelism scrypt would use with the parameters of the manager.
+func (am *Manager) SetKDF(kdf string) error {
+	if kdf != KDFScrypt && kdf != KDFArgon2id {
+		return fmt.Errorf("unsupported KDF %q", kdf)
+	}
+	am.keyStore.kdf = kdf
+	return nil
+}
+
+// InspectKeys describes the encryption of all key files and HD wallet files,
+// without decrypting them.
+func (am *Manager) InspectKeys() ([]KeyFileInfo, error) {
+	if am.external != nil {
+		return nil, ErrExternalSigner
+	}
+	accounts := am.ac.accounts()
+	am.ac.muLock()
+	duplicates := make(map[Account][]Account)
+	for _, a := range accounts {
+		if _, err := am.ac.find(Account{Address: a.Address}); err != nil {
+			if err, ok := err.(*AmbiguousAddrError); ok {
+				for _, match := range err.Matches {
+					if match.File != a.File {
+						duplicates[a] = append(duplicates[a], match)
+					}
+				}
+			}
+		}
+	}
+	am.ac.muUnlock()
+
+	var infos []KeyFileInfo
+	for _, a := range accounts {
+		info := KeyFileInfo{Account: a, Duplicates: duplicates[a]}
+		data := []byte(a.EncryptedKey)
+		if len(data) == 0 {
+			data, info.Err = ioutil.ReadFile(a.File)
+		}
+		if info.Err == nil {
+			var file struct {
+				Version interface{} `json:"version"`
+				Crypto  cryptoJSON  `json:"crypto"`
+			}
+			if info.Err = json.Unmarshal(data, &file); info.Err == nil {
+				info.Version = fmt.Sprint(file.Version)
+				am.describe(&info, file.Crypto)
+				info.Current = info.Current && info.Version == "3"
+			}
+		}
+		infos = append(infos, info)
+	}
+
+	am.hd.mu.RLock()
+	for _, w := range am.hd.wallets {
+		info := KeyFileInfo{
+			Account: Account{Address: w.accounts[0].address, File: w.file},
+			HD:      true,
+			Version: "1",
+		}
+		am.describe(&info, w.crypto)
+		infos = append(infos, info)
+	}
+	am.hd.mu.RUnlock()
+
+	sort.Slice(infos, func(i, j int) bool { return infos[i].Account.File < infos[j].Account.File })
+	return infos, nil
+}
+
+func (am *Manager) describe(info *KeyFileInfo, c cryptoJSON) {
+	info.Cipher = c.Cipher
+	info.KDF = c.KDF
+	info.KDFParams = c.KDFParams
+	info.Current = am.keyStore.isCurrent(c)
+}
+
+// Upgrade re-encrypts the key file of an account, or the HD wallet holding it,
+// with the current encryption parameters of the manager, keeping its passphrase.
+// Key files of an older version are rewritten in version 3.
+func (am *Manager) Upgrade(a Account, passphrase string) error {
+	return am.Update(a, passphrase, passphrase)
+}
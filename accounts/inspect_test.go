@@ -0,0 +1,112 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestInspectUpgrade(t *testing.T) {
+	dir, am := tmpManager(t)
+	defer os.RemoveAll(dir)
+
+	a, err := am.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	hd, err := am.ImportHDWallet(testMnemonic, "foo", EthereumRootDerivationPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	infos, err := am.InspectKeys()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 2 {
+		t.Fatalf("have %d key files, want 2", len(infos))
+	}
+	for _, info := range infos {
+		if !info.Current || info.Err != nil || info.KDF != KDFScrypt || info.Cipher != "aes-128-ctr" {
+			t.Errorf("new key file not current: %+v", info)
+		}
+		if info.HD && (info.Account.Address != hd.Address || info.Version != "1") || !info.HD && (info.Account != a || info.Version != "3") {
+			t.Errorf("key file mismatch: %+v", info)
+		}
+	}
+
+	// Duplicate the key file and switch to stronger argon2id parameters
+	data, err := ioutil.ReadFile(a.File)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dup := filepath.Join(dir, "dup")
+	if err := ioutil.WriteFile(dup, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	am2, err := NewManager(dir, veryLightScryptN*2, veryLightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := am2.SetKDF("bcrypt"); err == nil {
+		t.Error("unsupported KDF accepted")
+	}
+	if err := am2.SetKDF(KDFArgon2id); err != nil {
+		t.Fatal(err)
+	}
+	if infos, err = am2.InspectKeys(); err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 3 {
+		t.Fatalf("have %d key files, want 3", len(infos))
+	}
+	for _, info := range infos {
+		if info.Current {
+			t.Errorf("key file current after parameter change: %+v", info)
+		}
+		if !info.HD && len(info.Duplicates) != 1 {
+			t.Errorf("%s: have %d duplicates, want 1", info.Account.File, len(info.Duplicates))
+		}
+		if err := am2.Upgrade(info.Account, "bar"); err != ErrDecrypt {
+			t.Errorf("upgrade with wrong passphrase: have error %v, want %v", err, ErrDecrypt)
+		}
+		if err := am2.Upgrade(info.Account, "foo"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if infos, err = am2.InspectKeys(); err != nil {
+		t.Fatal(err)
+	}
+	for _, info := range infos {
+		if !info.Current || info.KDF != KDFArgon2id {
+			t.Errorf("upgraded key file not current: %+v", info)
+		}
+	}
+	// The argon2id keys are read back from their files
+	am3, err := NewManager(dir, veryLightScryptN, veryLightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, acc := range []Account{{Address: a.Address, File: dup}, hd} {
+		if err := am3.Unlock(acc, "foo"); err != nil {
+			t.Errorf("unlock %x: %v", acc.Address, err)
+		}
+	}
+}
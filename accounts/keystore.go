@@ -30,6 +30,7 @@ import (
 	"path/filepath"
 	"time"
 
+	"golang.org/x/crypto/argon2"
 	"golang.org/x/crypto/pbkdf2"
 	"golang.org/x/crypto/scrypt"
 
@@ -135,6 +136,7 @@ func storeNewKey(store *keyStore, secret string) (*key, Account, error) {
 
 type keyStore struct {
 	baseDir string // absolute filepath to default/flagged value for eg datadir/mainnet/keystore
+	kdf     string // KDFScrypt or KDFArgon2id
 	scryptN int
 	scryptP int
 }
@@ -153,6 +155,7 @@ func newKeyStore(dir string, scryptN, scryptP int) (*keyStore, error) {
 
 	return &keyStore{
 		baseDir: dir,
+		kdf:     KDFScrypt,
 		scryptN: scryptN,
 		scryptP: scryptP,
 	}, nil
@@ -185,7 +188,7 @@ func (store *keyStore) Lookup(file string, secret string) (*key, error) {
 }
 
 func (store *keyStore) Insert(key *key, secret string) (file string, err error) {
-	data, err := encryptKey(key, secret, store.scryptN, store.scryptP)
+	data, err := store.encryptKey(key, secret)
 	if err != nil {
 		return "", err
 	}
@@ -201,7 +204,7 @@ func (store *keyStore) Insert(key *key, secret string) (file string, err error)
 }
 
 func (store keyStore) Update(file string, key *key, secret string) error {
-	data, err := encryptKey(key, secret, store.scryptN, store.scryptP)
+	data, err := store.encryptKey(key, secret)
 	if err != nil {
 		return err
 	}
@@ -243,44 +246,146 @@ type cipherparamsJSON struct {
 	IV string `json:"iv"`
 }
 
+// encryptKey encrypts key as version 3 with the KDF of the store.
+func (store *keyStore) encryptKey(key *key, secret string) ([]byte, error) {
+	cryptoStruct, err := store.encryptData(crypto.FromECDSA(key.PrivateKey), secret)
+	if err != nil {
+		return nil, err
+	}
+	return marshalKey(key, cryptoStruct)
+}
+
+// encryptData encrypts data with the version 3 scheme and the KDF of the store.
+func (store *keyStore) encryptData(data []byte, secret string) (cryptoJSON, error) {
+	if store.kdf == KDFArgon2id {
+		return encryptDataArgon2id(data, secret, argon2Memory(store.scryptN), argon2Threads(store.scryptP))
+	}
+	return encryptData(data, secret, store.scryptN, store.scryptP)
+}
+
+// isCurrent reports whether data was encrypted with the version 3 scheme and
+// the KDF parameters of the store.
+func (store *keyStore) isCurrent(c cryptoJSON) bool {
+	if c.Cipher != "aes-128-ctr" || c.KDF != store.kdf {
+		return false
+	}
+	var want map[string]int
+	switch store.kdf {
+	case KDFScrypt:
+		want = map[string]int{"n": store.scryptN, "r": scryptR, "p": store.scryptP, "dklen": scryptDKLen}
+	case KDFArgon2id:
+		want = map[string]int{"t": argon2Time, "m": int(argon2Memory(store.scryptN)), "p": int(argon2Threads(store.scryptP)), "dklen": scryptDKLen}
+	}
+	for name, v := range want {
+		if have, ok := kdfParamInt(c.KDFParams[name]); !ok || have != v {
+			return false
+		}
+	}
+	return true
+}
+
+// kdfParamInt returns an integer KDF parameter, decoded from JSON or set by
+// the encryption.
+func kdfParamInt(x interface{}) (int, bool) {
+	switch x := x.(type) {
+	case float64:
+		return int(x), true
+	case int:
+		return x, true
+	case uint32:
+		return int(x), true
+	case uint8:
+		return int(x), true
+	}
+	return 0, false
+}
+
 // encryptKey encrypts key as version 3.
 func encryptKey(key *key, secret string, scryptN, scryptP int) ([]byte, error) {
+	cryptoStruct, err := encryptData(crypto.FromECDSA(key.PrivateKey), secret, scryptN, scryptP)
+	if err != nil {
+		return nil, err
+	}
+	return marshalKey(key, cryptoStruct)
+}
+
+func marshalKey(key *key, cryptoStruct cryptoJSON) ([]byte, error) {
+	return json.Marshal(web3v3{
+		ID:      key.UUID,
+		Address: hex.EncodeToString(key.Address[:]),
+		Crypto:  cryptoStruct,
+		Version: 3,
+	})
+}
+
+// encryptData encrypts data with the version 3 scheme, AES-128-CTR under a key
+// derived from the secret by scrypt.
+func encryptData(data []byte, secret string, scryptN, scryptP int) (cryptoJSON, error) {
 	salt := randentropy.GetEntropyCSPRNG(32)
 	derivedKey, err := scrypt.Key([]byte(secret), salt, scryptN, scryptR, scryptP, scryptDKLen)
 	if err != nil {
-		return nil, err
+		return cryptoJSON{}, err
+	}
+	return sealData(data, derivedKey, KDFScrypt, map[string]interface{}{
+		"n":     scryptN,
+		"r":     scryptR,
+		"p":     scryptP,
+		"dklen": scryptDKLen,
+		"salt":  hex.EncodeToString(salt),
+	})
+}
+
+// encryptDataArgon2id encrypts data like encryptData, deriving the key by
+// argon2id with the given memory in KiB and degree of parallelism. The
+// parameters are stored as in the PHC string format, t for the number of
+// passes, m for the memory and p for the parallelism.
+func encryptDataArgon2id(data []byte, secret string, memory uint32, threads uint8) (cryptoJSON, error) {
+	salt := randentropy.GetEntropyCSPRNG(32)
+	derivedKey := argon2.IDKey([]byte(secret), salt, argon2Time, memory, threads, scryptDKLen)
+	return sealData(data, derivedKey, KDFArgon2id, map[string]interface{}{
+		"t":     argon2Time,
+		"m":     memory,
+		"p":     threads,
+		"dklen": scryptDKLen,
+		"salt":  hex.EncodeToString(salt),
+	})
+}
+
+// argon2Memory returns the argon2id memory in KiB that scrypt uses with the
+// given N, so that the light KDF setting applies to both.
+func argon2Memory(scryptN int) uint32 {
+	return uint32(128 * scryptR * scryptN / 1024)
+}
+
+func argon2Threads(scryptP int) uint8 {
+	if scryptP > 255 {
+		return 255
 	}
+	return uint8(scryptP)
+}
+
+// sealData encrypts data with AES-128-CTR under the first half of the derived
+// key, authenticated by a MAC over the second half.
+func sealData(data, derivedKey []byte, kdf string, kdfParams map[string]interface{}) (cryptoJSON, error) {
 	encryptKey := derivedKey[:16]
-	keyBytes := crypto.FromECDSA(key.PrivateKey)
 
 	iv := randentropy.GetEntropyCSPRNG(aes.BlockSize) // 16
-	cipherText, err := aesCTRXOR(encryptKey, keyBytes, iv)
+	cipherText, err := aesCTRXOR(encryptKey, data, iv)
 	if err != nil {
-		return nil, err
+		return cryptoJSON{}, err
 	}
 	mac := crypto.Keccak256(derivedKey[16:32], cipherText)
 
-	return json.Marshal(web3v3{
-		ID:      key.UUID,
-		Address: hex.EncodeToString(key.Address[:]),
-		Crypto: cryptoJSON{
-			Cipher:     "aes-128-ctr",
-			CipherText: hex.EncodeToString(cipherText),
-			CipherParams: cipherparamsJSON{
-				IV: hex.EncodeToString(iv),
-			},
-			KDF: "scrypt",
-			KDFParams: map[string]interface{}{
-				"n":     scryptN,
-				"r":     scryptR,
-				"p":     scryptP,
-				"dklen": scryptDKLen,
-				"salt":  hex.EncodeToString(salt),
-			},
-			MAC: hex.EncodeToString(mac),
+	return cryptoJSON{
+		Cipher:     "aes-128-ctr",
+		CipherText: hex.EncodeToString(cipherText),
+		CipherParams: cipherparamsJSON{
+			IV: hex.EncodeToString(iv),
 		},
-		Version: 3,
-	})
+		KDF:       kdf,
+		KDFParams: kdfParams,
+		MAC:       hex.EncodeToString(mac),
+	}, nil
 }
 
 // Web3PrivateKey decrypts the record with secret and returns the private key.
@@ -345,26 +450,31 @@ func decryptKey(web3JSON []byte, secret string) (*key, error) {
 }
 
 func decryptKeyV3(keyProtected *web3v3, secret string) (keyBytes []byte, err error) {
-	if keyProtected.Crypto.Cipher != "aes-128-ctr" {
-		return nil, fmt.Errorf("Cipher not supported: %v", keyProtected.Crypto.Cipher)
+	return decryptData(keyProtected.Crypto, secret)
+}
+
+// decryptData decrypts data encrypted with the version 3 scheme.
+func decryptData(cryptoStruct cryptoJSON, secret string) ([]byte, error) {
+	if cryptoStruct.Cipher != "aes-128-ctr" {
+		return nil, fmt.Errorf("Cipher not supported: %v", cryptoStruct.Cipher)
 	}
 
-	mac, err := hex.DecodeString(keyProtected.Crypto.MAC)
+	mac, err := hex.DecodeString(cryptoStruct.MAC)
 	if err != nil {
 		return nil, err
 	}
 
-	iv, err := hex.DecodeString(keyProtected.Crypto.CipherParams.IV)
+	iv, err := hex.DecodeString(cryptoStruct.CipherParams.IV)
 	if err != nil {
 		return nil, err
 	}
 
-	cipherText, err := hex.DecodeString(keyProtected.Crypto.CipherText)
+	cipherText, err := hex.DecodeString(cryptoStruct.CipherText)
 	if err != nil {
 		return nil, err
 	}
 
-	derivedKey, err := getKDFKey(keyProtected.Crypto, secret)
+	derivedKey, err := getKDFKey(cryptoStruct, secret)
 	if err != nil {
 		return nil, err
 	}
@@ -435,6 +545,15 @@ func getKDFKey(cryptoJSON cryptoJSON, secret string) ([]byte, error) {
 		}
 		key := pbkdf2.Key([]byte(secret), salt, c, dkLen, sha256.New)
 		return key, nil
+
+	} else if cryptoJSON.KDF == KDFArgon2id {
+		t := ensureInt(cryptoJSON.KDFParams["t"])
+		m := ensureInt(cryptoJSON.KDFParams["m"])
+		p := ensureInt(cryptoJSON.KDFParams["p"])
+		if t < 1 || t > argon2MaxTime || m < 1 || m > argon2MaxMemory || p < 1 || p > 255 {
+			return nil, fmt.Errorf("Invalid argon2id parameters: t=%d, m=%d, p=%d", t, m, p)
+		}
+		return argon2.IDKey([]byte(secret), salt, uint32(t), uint32(m), uint8(p), uint32(dkLen)), nil
 	}
 
 	return nil, fmt.Errorf("Unsupported KDF: %s", cryptoJSON.KDF)
@@ -297,3 +297,37 @@ func TestKeyEncryptDecrypt(t *testing.T) {
 		}
 	}
 }
+
+// Tests that argon2id parameters out of bounds are rejected before deriving
+// the key.
+func TestArgon2idParamsBounds(t *testing.T) {
+	params := func(t, m, p int) cryptoJSON {
+		return cryptoJSON{KDF: KDFArgon2id, KDFParams: map[string]interface{}{
+			"t": float64(t), "m": float64(m), "p": float64(p), "dklen": float64(scryptDKLen), "salt": "00",
+		}}
+	}
+	for _, test := range []cryptoJSON{
+		params(0, 1024, 1),
+		params(argon2MaxTime+1, 1024, 1),
+		params(10, 1024, 1),
+		params(1, 0, 1),
+		params(1, argon2MaxMemory+1, 1),
+		params(1, 512<<10, 1),
+		params(1, 1<<31, 1),
+		params(1, 1024, 0),
+		params(1, 1024, 256),
+	} {
+		if _, err := getKDFKey(test, ""); err == nil {
+			t.Errorf("no error for parameters %v", test.KDFParams)
+		}
+	}
+	if argon2Memory(StandardScryptN) > argon2MaxMemory {
+		t.Errorf("standard memory %d KiB above the maximum %d KiB", argon2Memory(StandardScryptN), argon2MaxMemory)
+	}
+	if argon2Time > argon2MaxTime {
+		t.Errorf("passes %d above the maximum %d", argon2Time, argon2MaxTime)
+	}
+	if _, err := getKDFKey(params(1, 1024, 1), ""); err != nil {
+		t.Error(err)
+	}
+}
@@ -31,7 +31,10 @@ import (
 
 	"encoding/json"
 	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
 	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
 	"path/filepath"
 )
 
@@ -76,6 +79,9 @@ func (acc *Account) UnmarshalJSON(raw []byte) error {
 type Manager struct {
 	ac       caching
 	keyStore keyStore
+	hd       *hdStore
+	external *ExternalSigner
+	rules    *SigningRules
 	mu       sync.RWMutex
 	unlocked map[common.Address]*unlocked
 }
@@ -96,6 +102,21 @@ const (
 
 	scryptR     = 8
 	scryptDKLen = 32
+
+	// argon2id makes 3 passes over the memory scrypt would use.
+	argon2Time = 3
+
+	// Key files with argon2id parameters above these bounds are rejected, so a
+	// crafted key can't exhaust the memory or CPU of the node. The memory in KiB
+	// leaves a margin of a quarter over what StandardScryptN uses.
+	argon2MaxMemory = 320 << 10
+	argon2MaxTime   = 4
+)
+
+// The key derivation functions of newly encrypted keys.
+const (
+	KDFScrypt   = "scrypt"
+	KDFArgon2id = "argon2id"
 )
 
 // NewManager creates a manager for the given directory.
@@ -108,6 +129,7 @@ func NewManager(keydir string, scryptN, scryptP int, wantCacheDB bool) (*Manager
 
 	am := &Manager{
 		keyStore: *store,
+		hd:       newHDStore(filepath.Join(store.baseDir, hdWalletDir)),
 		unlocked: make(map[common.Address]*unlocked),
 	}
 	if wantCacheDB {
@@ -133,23 +155,65 @@ func NewManager(keydir string, scryptN, scryptP int, wantCacheDB bool) (*Manager
 	return am, nil
 }
 
+// UseExternalSigner delegates the accounts and all signing to an external
+// signer. The keys of the key directory are no longer used and can't be
+// unlocked, so no key is held in memory. It must be called before the
+// manager is used.
+func (am *Manager) UseExternalSigner(signer *ExternalSigner) {
+	am.external = signer
+}
+
+// SetSigningRules restricts signing to what the rules allow, even for unlocked
+// accounts. It must be called before the manager is used.
+func (am *Manager) SetSigningRules(rules *SigningRules) {
+	am.rules = rules
+}
+
+// ExternalSigner returns the external signer used by the manager, or nil.
+func (am *Manager) ExternalSigner() *ExternalSigner {
+	return am.external
+}
+
 func (am *Manager) BuildIndexDB() []error {
 	return am.ac.Syncfs2db(time.Now().Add(-60 * 24 * 7 * 30 * 120 * time.Minute)) // arbitrarily long "last updated"
 }
 
 // HasAddress reports whether a key with the given address is present.
 func (am *Manager) HasAddress(addr common.Address) bool {
-	return am.ac.hasAddress(addr)
+	if am.external != nil {
+		return am.external.hasAddress(addr)
+	}
+	return am.ac.hasAddress(addr) || am.hd.hasAddress(addr)
 }
 
-// Accounts returns all key files present in the directory.
+// Accounts returns all key files present in the directory, followed by the
+// accounts of the HD wallets. With an external signer, its accounts are
+// returned instead.
 func (am *Manager) Accounts() []Account {
-	return am.ac.accounts()
+	if am.external != nil {
+		addrs, err := am.external.Accounts()
+		if err != nil {
+			glog.V(logger.Error).Infof("can't list accounts of external signer %s: %v", am.external.Endpoint(), err)
+			return nil
+		}
+		accounts := make([]Account, len(addrs))
+		for i, addr := range addrs {
+			accounts[i] = Account{Address: addr}
+		}
+		return accounts
+	}
+	return append(am.ac.accounts(), am.hdAccounts()...)
 }
 
 // DeleteAccount deletes the key matched by account if the passphrase is correct.
 // If a contains no filename, the address must match a unique key.
 func (am *Manager) DeleteAccount(a Account, passphrase string) error {
+	if am.external != nil {
+		return ErrExternalSigner
+	}
+	if am.hd.hasAddress(a.Address) {
+		return errHDAccount
+	}
 	// Decrypting the key isn't really necessary, but we do
 	// it anyway to check the password and zero out the key
 	// immediately afterwards.
@@ -178,6 +242,18 @@ func (am *Manager) DeleteAccount(a Account, passphrase string) error {
 
 // Sign signs hash with an unlocked private key matching the given address.
 func (am *Manager) Sign(addr common.Address, hash []byte) (signature []byte, err error) {
+	if am.rules != nil {
+		if err := am.rules.approveHash(); err != nil {
+			return nil, err
+		}
+	}
+	return am.sign(addr, hash)
+}
+
+func (am *Manager) sign(addr common.Address, hash []byte) ([]byte, error) {
+	if am.external != nil {
+		return am.external.SignHash(addr, hash)
+	}
 	am.mu.RLock()
 	defer am.mu.RUnlock()
 
@@ -189,8 +265,21 @@ func (am *Manager) Sign(addr common.Address, hash []byte) (signature []byte, err
 }
 
 // SignWithPassphrase signs hash if the private key matching the given address can be
-// decrypted with the given passphrase.
+// decrypted with the given passphrase. An external signer approves the signature
+// itself, the passphrase isn't passed on.
 func (am *Manager) SignWithPassphrase(addr common.Address, passphrase string, hash []byte) (signature []byte, err error) {
+	if am.rules != nil {
+		if err := am.rules.approveHash(); err != nil {
+			return nil, err
+		}
+	}
+	return am.signWithPassphrase(addr, passphrase, hash)
+}
+
+func (am *Manager) signWithPassphrase(addr common.Address, passphrase string, hash []byte) ([]byte, error) {
+	if am.external != nil {
+		return am.external.SignHash(addr, hash)
+	}
 	_, key, err := am.getDecryptedKey(Account{Address: addr}, passphrase)
 	if err != nil {
 		return nil, err
@@ -200,6 +289,50 @@ func (am *Manager) SignWithPassphrase(addr common.Address, passphrase string, ha
 	return crypto.Sign(hash, key.PrivateKey)
 }
 
+// SignTx signs a transaction with an unlocked private key matching the given
+// address. An external signer is sent the whole transaction for approval.
+func (am *Manager) SignTx(addr common.Address, tx *types.Transaction, signer types.Signer) (*types.Transaction, error) {
+	return am.signTx(addr, tx, signer, func(hash []byte) ([]byte, error) {
+		return am.sign(addr, hash)
+	})
+}
+
+// SignTxWithPassphrase signs a transaction if the private key matching the given
+// address can be decrypted with the given passphrase.
+func (am *Manager) SignTxWithPassphrase(addr common.Address, passphrase string, tx *types.Transaction, signer types.Signer) (*types.Transaction, error) {
+	return am.signTx(addr, tx, signer, func(hash []byte) ([]byte, error) {
+		return am.signWithPassphrase(addr, passphrase, hash)
+	})
+}
+
+// signTx signs a transaction with the given hash signing function, if the
+// signing rules allow it.
+func (am *Manager) signTx(addr common.Address, tx *types.Transaction, signer types.Signer, sign func([]byte) ([]byte, error)) (*types.Transaction, error) {
+	var signed *types.Transaction
+	signTx := func() (err error) {
+		if am.external != nil {
+			signed, err = am.external.SignTx(addr, tx, signer)
+			return err
+		}
+		signature, err := sign(signer.Hash(tx).Bytes())
+		if err != nil {
+			return err
+		}
+		signed, err = tx.WithSigner(signer).WithSignature(signature)
+		return err
+	}
+	var err error
+	if am.rules != nil {
+		err = am.rules.approveTx(addr, tx, signTx)
+	} else {
+		err = signTx()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return signed, nil
+}
+
 // Unlock unlocks the given account indefinitely.
 func (am *Manager) Unlock(a Account, passphrase string) error {
 	return am.TimedUnlock(a, passphrase, 0)
@@ -255,6 +388,13 @@ func (am *Manager) TimedUnlock(a Account, passphrase string, timeout time.Durati
 }
 
 func (am *Manager) getDecryptedKey(a Account, auth string) (Account, *key, error) {
+	if am.external != nil {
+		return Account{}, nil, ErrExternalSigner
+	}
+	if a, key, ok, err := am.getDecryptedHDKey(a, auth); ok {
+		return a, key, err
+	}
+
 	am.ac.maybeReload()
 	am.ac.muLock()
 	a, err := am.ac.find(a)
@@ -303,6 +443,9 @@ func (am *Manager) expire(addr common.Address, u *unlocked, timeout time.Duratio
 // NewAccount generates a new key and stores it into the key directory,
 // encrypting it with the passphrase.
 func (am *Manager) NewAccount(passphrase string) (Account, error) {
+	if am.external != nil {
+		return Account{}, ErrExternalSigner
+	}
 	_, account, err := storeNewKey(&am.keyStore, passphrase)
 	if err != nil {
 		return Account{}, err
@@ -328,7 +471,7 @@ func (am *Manager) Export(a Account, passphrase, newPassphrase string) (keyJSON
 	if err != nil {
 		return nil, err
 	}
-	return encryptKey(key, newPassphrase, am.keyStore.scryptN, am.keyStore.scryptP)
+	return am.keyStore.encryptKey(key, newPassphrase)
 }
 
 // Import stores the given encrypted JSON key into the key directory.
@@ -350,7 +493,7 @@ func (am *Manager) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (Accou
 		return Account{}, err
 	}
 
-	if am.ac.hasAddress(key.Address) {
+	if am.HasAddress(key.Address) {
 		return Account{}, fmt.Errorf("account already exists")
 	}
 
@@ -358,6 +501,9 @@ func (am *Manager) ImportECDSA(priv *ecdsa.PrivateKey, passphrase string) (Accou
 }
 
 func (am *Manager) importKey(key *key, passphrase string) (Account, error) {
+	if am.external != nil {
+		return Account{}, ErrExternalSigner
+	}
 	file, err := am.keyStore.Insert(key, passphrase)
 	if err != nil {
 		return Account{}, err
@@ -368,8 +514,15 @@ func (am *Manager) importKey(key *key, passphrase string) (Account, error) {
 	return a, nil
 }
 
-// Update changes the passphrase of an existing account.
+// Update changes the passphrase of an existing account. For an account of a
+// HD wallet the passphrase of the whole wallet is changed.
 func (am *Manager) Update(a Account, passphrase, newPassphrase string) error {
+	if am.external != nil {
+		return ErrExternalSigner
+	}
+	if ok, err := am.updateHDWallet(a, passphrase, newPassphrase); ok {
+		return err
+	}
 	a, key, err := am.getDecryptedKey(a, passphrase)
 	if err != nil {
 		return err
@@ -380,6 +533,9 @@ func (am *Manager) Update(a Account, passphrase, newPassphrase string) error {
 // ImportPreSaleKey decrypts the given Ethereum presale wallet and stores
 // a key file in the key directory. The key file is encrypted with the same passphrase.
 func (am *Manager) ImportPreSaleKey(keyJSON []byte, passphrase string) (Account, error) {
+	if am.external != nil {
+		return Account{}, ErrExternalSigner
+	}
 	a, _, err := importPreSaleKey(&am.keyStore, keyJSON, passphrase)
 	if err != nil {
 		return a, err
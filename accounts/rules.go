@@ -0,0 +1,318 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+var (
+	ErrRuleRejected = errors.New("signing rejected by rules")
+
+	errNoRuleMatch = errors.New("no signing rule allows the transaction")
+)
+
+// SigningRules restrict what a manager signs, even with unlocked accounts. A
+// transaction is signed only if a rule allows it, hashes of unknown data only if
+// SignHash is set. A rules file looks like:
+//
+//	{
+//	  "signHash": false,
+//	  "transactions": [{
+//	    "name": "payouts",
+//	    "from": ["0x..."],
+//	    "to": ["0x...", "0x..."],
+//	    "selectors": ["0x", "0xa9059cbb"],
+//	    "maxValue": "1000000000000000000",
+//	    "dailyValue": "5000000000000000000",
+//	    "dailyCount": 20
+//	  }]
+//	}
+//
+// The daily limits count the transactions signed under a rule during the current
+// UTC day. The counters are stored in a file, so that a restart doesn't reset them.
+type SigningRules struct {
+	SignHash     bool     `json:"signHash"`
+	Transactions []TxRule `json:"transactions"`
+
+	mu       sync.Mutex
+	file     string // counters file, none if empty
+	counters ruleCounters
+	now      func() time.Time
+}
+
+// TxRule allows the transactions matching all of its set fields.
+type TxRule struct {
+	// Name identifies the counters of the rule, it is required for daily limits.
+	Name string `json:"name"`
+	// From and To list the allowed senders and recipients, all are allowed if
+	// empty. Contract creations are only allowed with AllowCreate.
+	From        []common.Address `json:"from"`
+	To          []common.Address `json:"to"`
+	AllowCreate bool             `json:"allowCreate"`
+	// Selectors lists the allowed 4 byte function selectors of the calldata,
+	// "0x" allowing transactions without calldata. Any calldata is allowed if empty.
+	Selectors []string `json:"selectors"`
+	// MaxValue limits the value of a transaction, DailyValue and DailyCount the
+	// total value and number of transactions signed under the rule per day.
+	MaxValue   *rpc.HexNumber `json:"maxValue"`
+	DailyValue *rpc.HexNumber `json:"dailyValue"`
+	DailyCount uint64         `json:"dailyCount"`
+}
+
+// ruleCounters are the daily totals of the rules, stored in the counters file.
+type ruleCounters struct {
+	Day   string                  `json:"day"`
+	Rules map[string]*ruleCounter `json:"rules"`
+}
+
+type ruleCounter struct {
+	Value *rpc.HexNumber `json:"value"`
+	Count uint64         `json:"count"`
+}
+
+// LoadSigningRules loads the rules file and the counters of the daily limits
+// from the counters file, which is created if it doesn't exist.
+func LoadSigningRules(file, countersFile string) (*SigningRules, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	rules := new(SigningRules)
+	if err := json.Unmarshal(data, rules); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %v", file, err)
+	}
+	if err := rules.validate(); err != nil {
+		return nil, fmt.Errorf("invalid rules file %s: %v", file, err)
+	}
+	rules.file = countersFile
+	if data, err := ioutil.ReadFile(countersFile); err == nil {
+		if err := json.Unmarshal(data, &rules.counters); err != nil {
+			return nil, fmt.Errorf("invalid counters file %s: %v", countersFile, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, err
+	}
+	return rules, nil
+}
+
+// validate checks the rules are complete and well formed.
+func (r *SigningRules) validate() error {
+	names := make(map[string]bool)
+	for i, rule := range r.Transactions {
+		if rule.DailyValue != nil || rule.DailyCount > 0 {
+			if rule.Name == "" {
+				return fmt.Errorf("rule %d has daily limits but no name", i)
+			}
+		}
+		if rule.Name != "" {
+			if names[rule.Name] {
+				return fmt.Errorf("duplicate rule name %q", rule.Name)
+			}
+			names[rule.Name] = true
+		}
+		for _, selector := range rule.Selectors {
+			sel, err := hex.DecodeString(strings.TrimPrefix(selector, "0x"))
+			if !strings.HasPrefix(selector, "0x") || err != nil || len(sel) != 0 && len(sel) != 4 {
+				return fmt.Errorf("rule %d has invalid selector %q", i, selector)
+			}
+		}
+	}
+	return nil
+}
+
+// approveHash checks that hashes of unknown data may be signed.
+func (r *SigningRules) approveHash() error {
+	if !r.SignHash {
+		return ErrRuleRejected
+	}
+	return nil
+}
+
+// Allows checks that a rule allows the transaction within its daily limits,
+// without counting it. The limits are checked again when the transaction is
+// signed by a manager using the rules.
+func (r *SigningRules) Allows(from common.Address, tx *types.Transaction) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollDay()
+	_, err := r.match(from, tx)
+	return err
+}
+
+// approveTx signs a transaction with the sign function if a rule allows it,
+// counting it against the daily limits of the rule once it is signed.
+func (r *SigningRules) approveTx(from common.Address, tx *types.Transaction, sign func() error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.rollDay()
+	rule, err := r.match(from, tx)
+	if err != nil {
+		return err
+	}
+	if err := sign(); err != nil {
+		return err
+	}
+	return r.count(rule, tx)
+}
+
+// match returns the first rule allowing the transaction within its daily
+// limits. The caller must hold the lock.
+func (r *SigningRules) match(from common.Address, tx *types.Transaction) (*TxRule, error) {
+	var rejection error = errNoRuleMatch
+	for i := range r.Transactions {
+		rule := &r.Transactions[i]
+		if !rule.matches(from, tx) {
+			continue
+		}
+		if err := rule.checkLimits(r.counters.Rules[rule.Name], tx); err != nil {
+			rejection = err
+			continue
+		}
+		return rule, nil
+	}
+	return nil, fmt.Errorf("%v: %v", ErrRuleRejected, rejection)
+}
+
+// rollDay resets the counters when a new day starts.
+func (r *SigningRules) rollDay() {
+	now := time.Now
+	if r.now != nil {
+		now = r.now
+	}
+	if day := now().UTC().Format("2006-01-02"); r.counters.Day != day {
+		r.counters = ruleCounters{Day: day}
+	}
+}
+
+// count adds a signed transaction to the counters of its rule and stores them.
+// If they can't be stored the transaction is dropped, so it is taken off the
+// counters again.
+func (r *SigningRules) count(rule *TxRule, tx *types.Transaction) error {
+	if rule.Name == "" {
+		return nil
+	}
+	if r.counters.Rules == nil {
+		r.counters.Rules = make(map[string]*ruleCounter)
+	}
+	prev := r.counters.Rules[rule.Name]
+	counter := &ruleCounter{Value: rpc.NewHexNumber(0)}
+	if prev != nil {
+		*counter = *prev
+	}
+	counter.Value = rpc.NewHexNumber(new(big.Int).Add(counter.Value.BigInt(), tx.Value()))
+	counter.Count++
+	r.counters.Rules[rule.Name] = counter
+
+	if err := r.store(); err != nil {
+		if prev == nil {
+			delete(r.counters.Rules, rule.Name)
+		} else {
+			r.counters.Rules[rule.Name] = prev
+		}
+		return err
+	}
+	return nil
+}
+
+// store writes the counters to the counters file, if any.
+func (r *SigningRules) store() error {
+	if r.file == "" {
+		return nil
+	}
+	data, err := json.Marshal(r.counters)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.file), 0700); err != nil {
+		return err
+	}
+	return writeKeyFile(r.file, data)
+}
+
+// matches reports whether the rule allows the transaction, regardless of its
+// daily limits.
+func (rule *TxRule) matches(from common.Address, tx *types.Transaction) bool {
+	if len(rule.From) > 0 && !containsAddress(rule.From, from) {
+		return false
+	}
+	if to := tx.To(); to == nil {
+		if !rule.AllowCreate {
+			return false
+		}
+	} else if len(rule.To) > 0 && !containsAddress(rule.To, *to) {
+		return false
+	}
+	if len(rule.Selectors) > 0 {
+		data, allowed := tx.Data(), false
+		for _, selector := range rule.Selectors {
+			sel := common.FromHex(selector)
+			if len(sel) == 0 && len(data) == 0 || len(sel) > 0 && bytes.HasPrefix(data, sel) {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return false
+		}
+	}
+	if rule.MaxValue != nil && tx.Value().Cmp(rule.MaxValue.BigInt()) > 0 {
+		return false
+	}
+	return true
+}
+
+// checkLimits checks the transaction doesn't exceed the daily limits of the rule.
+func (rule *TxRule) checkLimits(counter *ruleCounter, tx *types.Transaction) error {
+	spent, count := new(big.Int), uint64(0)
+	if counter != nil {
+		spent, count = counter.Value.BigInt(), counter.Count
+	}
+	if rule.DailyCount > 0 && count >= rule.DailyCount {
+		return fmt.Errorf("daily count limit of rule %q reached", rule.Name)
+	}
+	if rule.DailyValue != nil && new(big.Int).Add(spent, tx.Value()).Cmp(rule.DailyValue.BigInt()) > 0 {
+		return fmt.Errorf("daily value limit of rule %q exceeded", rule.Name)
+	}
+	return nil
+}
+
+func containsAddress(addrs []common.Address, addr common.Address) bool {
+	for _, a := range addrs {
+		if a == addr {
+			return true
+		}
+	}
+	return false
+}
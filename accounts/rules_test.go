@@ -0,0 +1,209 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+)
+
+const testRules = `{
+	"transactions": [{
+		"name": "payouts",
+		"to": ["0x0100000000000000000000000000000000000000", "0x0200000000000000000000000000000000000000"],
+		"selectors": ["0x", "0xa9059cbb"],
+		"maxValue": "1000",
+		"dailyValue": "2500",
+		"dailyCount": 4
+	}]
+}`
+
+func newRulesManager(t *testing.T, dir string, now time.Time) (*Manager, Account) {
+	file := filepath.Join(dir, "rules.json")
+	if err := ioutil.WriteFile(file, []byte(testRules), 0600); err != nil {
+		t.Fatal(err)
+	}
+	rules, err := LoadSigningRules(file, filepath.Join(dir, "counters.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.now = func() time.Time { return now }
+
+	am, err := NewManager(filepath.Join(dir, "keystore"), veryLightScryptN, veryLightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, err := am.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := am.Unlock(a, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	am.SetSigningRules(rules)
+	return am, a
+}
+
+func TestSigningRules(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eth-rules-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	day := time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC)
+	am, a := newRulesManager(t, dir, day)
+	signer := types.NewChainIdSigner(big.NewInt(61))
+	sign := func(to *common.Address, value int64, data []byte) error {
+		var tx *types.Transaction
+		if to == nil {
+			tx = types.NewContractCreation(0, big.NewInt(value), big.NewInt(100000), big.NewInt(1), data)
+		} else {
+			tx = types.NewTransaction(0, *to, big.NewInt(value), big.NewInt(100000), big.NewInt(1), data)
+		}
+		signed, err := am.SignTx(a.Address, tx, signer)
+		if err == nil {
+			if from, _ := signed.From(); from != a.Address {
+				t.Fatalf("sender mismatch: have %x, want %x", from, a.Address)
+			}
+		}
+		return err
+	}
+	payee, other := common.Address{1}, common.Address{3}
+
+	if _, err := am.Sign(a.Address, make([]byte, 32)); err != ErrRuleRejected {
+		t.Errorf("hash signing: have error %v, want %v", err, ErrRuleRejected)
+	}
+	rejected := []struct {
+		name  string
+		to    *common.Address
+		value int64
+		data  []byte
+	}{
+		{"unknown recipient", &other, 1, nil},
+		{"contract creation", nil, 0, nil},
+		{"value above maximum", &payee, 1001, nil},
+		{"unknown selector", &payee, 0, []byte{1, 2, 3, 4}},
+	}
+	for _, test := range rejected {
+		if err := sign(test.to, test.value, test.data); err == nil || !strings.HasPrefix(err.Error(), ErrRuleRejected.Error()) {
+			t.Errorf("%s: have error %v, want %v", test.name, err, ErrRuleRejected)
+		}
+	}
+
+	// The daily value limit allows 1000 + 1000 + 500
+	if err := sign(&payee, 1000, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := sign(&payee, 1000, []byte{0xa9, 0x05, 0x9c, 0xbb, 0}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sign(&payee, 501, nil); err == nil || !strings.Contains(err.Error(), "daily value limit") {
+		t.Errorf("daily value: have error %v", err)
+	}
+	if err := sign(&payee, 500, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	// The counters survive a restart, the daily count allows one more
+	am, a = newRulesManager(t, dir, day.Add(time.Hour))
+	if err := sign(&payee, 1, nil); err == nil || !strings.Contains(err.Error(), "daily value limit") {
+		t.Errorf("daily value after restart: have error %v", err)
+	}
+	if err := sign(&payee, 0, nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := sign(&payee, 0, nil); err == nil || !strings.Contains(err.Error(), "daily count limit") {
+		t.Errorf("daily count: have error %v", err)
+	}
+
+	// The limits reset the next day
+	am, a = newRulesManager(t, dir, day.Add(24*time.Hour))
+	if err := sign(&payee, 1000, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// Tests that a transaction dropped as its counters can't be stored doesn't
+// count against the daily limits.
+func TestSigningRulesStoreFailure(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eth-rules-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	am, a := newRulesManager(t, dir, time.Date(2017, 1, 1, 12, 0, 0, 0, time.UTC))
+	signer := types.NewChainIdSigner(big.NewInt(61))
+	sign := func(value int64) error {
+		_, err := am.SignTx(a.Address, types.NewTransaction(0, common.Address{1}, big.NewInt(value), big.NewInt(21000), big.NewInt(1), nil), signer)
+		return err
+	}
+	if err := sign(1000); err != nil {
+		t.Fatal(err)
+	}
+	// The rules file isn't a directory, the counters file can't be written
+	file := am.rules.file
+	am.rules.file = filepath.Join(dir, "rules.json", "counters.json")
+	if err := sign(1000); err == nil {
+		t.Fatal("signed transaction without storing the counters")
+	}
+	am.rules.file = file
+
+	// The daily value limit still allows 1000 + 1000 + 500
+	if err := sign(1000); err != nil {
+		t.Fatal(err)
+	}
+	if err := sign(500); err != nil {
+		t.Fatal(err)
+	}
+	if err := sign(1); err == nil || !strings.Contains(err.Error(), "daily value limit") {
+		t.Errorf("daily value: have error %v", err)
+	}
+}
+
+func TestSigningRulesInvalid(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eth-rules-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, rules := range []string{
+		`{"transactions": [{"dailyCount": 1}]}`,
+		`{"transactions": [{"name": "a"}, {"name": "a"}]}`,
+		`{"transactions": [{"selectors": ["0xa9059c"]}]}`,
+		`{"transactions": [{"selectors": ["a9059cbb"]}]}`,
+		`{"transactions": [{"maxValue": "x"}]}`,
+	} {
+		file := filepath.Join(dir, "rules.json")
+		if err := ioutil.WriteFile(file, []byte(rules), 0600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := LoadSigningRules(file, filepath.Join(dir, "counters.json")); err == nil {
+			t.Errorf("%s: expected error", rules)
+		}
+	}
+}
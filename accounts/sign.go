@@ -0,0 +1,349 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/crypto"
+)
+
+// TextHash returns the hash of a message signed with the EIP-191 version 0x45
+// scheme, as used by personal_sign:
+//
+//	keccak256("\x19Ethereum Signed Message:\n" + len(message) + message)
+//
+// The prefix makes sure the signed message can't be a valid transaction.
+func TextHash(data []byte) []byte {
+	msg := fmt.Sprintf("\x19Ethereum Signed Message:\n%d%s", len(data), data)
+	return crypto.Keccak256([]byte(msg))
+}
+
+// TypedDataDomainType is the name of the type of the domain of typed data.
+const TypedDataDomainType = "EIP712Domain"
+
+// TypedDataField is a member of a struct type of typed data.
+type TypedDataField struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// TypedData is EIP-712 structured data. Types defines the struct types used by
+// the domain and the message, including the EIP712Domain type of the domain.
+type TypedData struct {
+	Types       map[string][]TypedDataField `json:"types"`
+	PrimaryType string                      `json:"primaryType"`
+	Domain      map[string]interface{}      `json:"domain"`
+	Message     map[string]interface{}      `json:"message"`
+}
+
+// UnmarshalJSON decodes typed data, keeping the JSON numbers of the domain and
+// the message exact instead of converting them to floating point.
+func (t *TypedData) UnmarshalJSON(input []byte) error {
+	type typedData TypedData
+	dec := json.NewDecoder(bytes.NewReader(input))
+	dec.UseNumber()
+	return dec.Decode((*typedData)(t))
+}
+
+// ChainID returns the chain id of the domain, or nil if the domain has none.
+func (t *TypedData) ChainID() (*big.Int, error) {
+	value, ok := t.Domain["chainId"]
+	if !ok {
+		return nil, nil
+	}
+	return parseTypedInteger(value)
+}
+
+// Hash returns the hash signed for the typed data:
+//
+//	keccak256("\x19\x01" || hashStruct(domain) || hashStruct(message))
+func (t *TypedData) Hash() ([]byte, error) {
+	if _, ok := t.Types[TypedDataDomainType]; !ok {
+		return nil, fmt.Errorf("missing %s type", TypedDataDomainType)
+	}
+	domain, err := t.HashStruct(TypedDataDomainType, t.Domain)
+	if err != nil {
+		return nil, fmt.Errorf("domain: %v", err)
+	}
+	message, err := t.HashStruct(t.PrimaryType, t.Message)
+	if err != nil {
+		return nil, fmt.Errorf("message: %v", err)
+	}
+	return crypto.Keccak256([]byte{0x19, 0x01}, domain, message), nil
+}
+
+// HashStruct returns the EIP-712 hash of the given value of a struct type.
+func (t *TypedData) HashStruct(typ string, value map[string]interface{}) ([]byte, error) {
+	enc, err := t.encodeData(typ, value, 1)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(enc), nil
+}
+
+// EncodeType returns the EIP-712 encoding of a struct type, followed by the
+// struct types it references in alphabetical order.
+func (t *TypedData) EncodeType(typ string) ([]byte, error) {
+	deps := make(map[string]bool)
+	if err := t.dependencies(typ, deps); err != nil {
+		return nil, err
+	}
+	delete(deps, typ)
+	names := make([]string, 0, len(deps))
+	for dep := range deps {
+		names = append(names, dep)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	for _, name := range append([]string{typ}, names...) {
+		fields := make([]string, len(t.Types[name]))
+		for i, field := range t.Types[name] {
+			fields[i] = field.Type + " " + field.Name
+		}
+		buf.WriteString(name + "(" + strings.Join(fields, ",") + ")")
+	}
+	return buf.Bytes(), nil
+}
+
+// dependencies collects the struct types referenced by the given type,
+// including itself.
+func (t *TypedData) dependencies(typ string, deps map[string]bool) error {
+	typ = baseType(typ)
+	if deps[typ] {
+		return nil
+	}
+	fields, ok := t.Types[typ]
+	if !ok {
+		return fmt.Errorf("unknown type %q", typ)
+	}
+	deps[typ] = true
+	for _, field := range fields {
+		if _, ok := t.Types[baseType(field.Type)]; ok {
+			if err := t.dependencies(field.Type, deps); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// maxTypedDataDepth limits the nesting of structs and arrays in typed data.
+const maxTypedDataDepth = 32
+
+// encodeData returns the EIP-712 encoding of a value of a struct type, its
+// type hash followed by the encodings of its members.
+func (t *TypedData) encodeData(typ string, value map[string]interface{}, depth int) ([]byte, error) {
+	if depth > maxTypedDataDepth {
+		return nil, errors.New("typed data nested too deep")
+	}
+	fields, ok := t.Types[typ]
+	if !ok {
+		return nil, fmt.Errorf("unknown type %q", typ)
+	}
+	if len(value) > len(fields) {
+		return nil, fmt.Errorf("%s has more members than its type", typ)
+	}
+	encType, err := t.EncodeType(typ)
+	if err != nil {
+		return nil, err
+	}
+	buf := bytes.NewBuffer(crypto.Keccak256(encType))
+	for _, field := range fields {
+		member, ok := value[field.Name]
+		if !ok {
+			return nil, fmt.Errorf("%s is missing member %q", typ, field.Name)
+		}
+		enc, err := t.encodeValue(field.Type, member, depth)
+		if err != nil {
+			return nil, fmt.Errorf("%s.%s: %v", typ, field.Name, err)
+		}
+		buf.Write(enc)
+	}
+	return buf.Bytes(), nil
+}
+
+var (
+	arrayTypeRegexp = regexp.MustCompile(`^(.+)\[([0-9]*)\]$`)
+	intTypeRegexp   = regexp.MustCompile(`^(u?)int([0-9]*)$`)
+	bytesTypeRegexp = regexp.MustCompile(`^bytes([0-9]+)$`)
+)
+
+// encodeValue returns the 32 byte EIP-712 encoding of a member value.
+func (t *TypedData) encodeValue(typ string, value interface{}, depth int) ([]byte, error) {
+	// Arrays and structs are hashed
+	if m := arrayTypeRegexp.FindStringSubmatch(typ); m != nil {
+		items, ok := value.([]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %v", typ, value)
+		}
+		if m[2] != "" {
+			if n, err := strconv.Atoi(m[2]); err != nil || n != len(items) {
+				return nil, fmt.Errorf("%s has %d items", typ, len(items))
+			}
+		}
+		var buf bytes.Buffer
+		for _, item := range items {
+			enc, err := t.encodeValue(m[1], item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			buf.Write(enc)
+		}
+		return crypto.Keccak256(buf.Bytes()), nil
+	}
+	if _, ok := t.Types[typ]; ok {
+		fields, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, fmt.Errorf("invalid %s value %v", typ, value)
+		}
+		enc, err := t.encodeData(typ, fields, depth+1)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(enc), nil
+	}
+
+	// Dynamic atomic values are hashed, the others are padded to a word
+	switch typ {
+	case "string":
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid string value %v", value)
+		}
+		return crypto.Keccak256([]byte(s)), nil
+	case "bytes":
+		b, err := parseTypedBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		return crypto.Keccak256(b), nil
+	case "bool":
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("invalid bool value %v", value)
+		}
+		if b {
+			return common.LeftPadBytes([]byte{1}, 32), nil
+		}
+		return make([]byte, 32), nil
+	case "address":
+		s, ok := value.(string)
+		if !ok || !common.IsHexAddress(s) {
+			return nil, fmt.Errorf("invalid address value %v", value)
+		}
+		return common.LeftPadBytes(common.HexToAddress(s).Bytes(), 32), nil
+	}
+	if m := bytesTypeRegexp.FindStringSubmatch(typ); m != nil {
+		size, _ := strconv.Atoi(m[1])
+		b, err := parseTypedBytes(value)
+		if err != nil {
+			return nil, err
+		}
+		if size < 1 || size > 32 || len(b) != size {
+			return nil, fmt.Errorf("invalid %s value %v", typ, value)
+		}
+		return common.RightPadBytes(b, 32), nil
+	}
+	if m := intTypeRegexp.FindStringSubmatch(typ); m != nil {
+		bits := 256
+		if m[2] != "" {
+			bits, _ = strconv.Atoi(m[2])
+		}
+		if bits < 8 || bits > 256 || bits%8 != 0 {
+			return nil, fmt.Errorf("invalid type %s", typ)
+		}
+		n, err := parseTypedInteger(value)
+		if err != nil {
+			return nil, err
+		}
+		return encodeTypedInteger(n, bits, m[1] == "u")
+	}
+	return nil, fmt.Errorf("unknown type %q", typ)
+}
+
+// encodeTypedInteger returns the two's complement 256 bit encoding of n after
+// checking it fits into the given integer type.
+func encodeTypedInteger(n *big.Int, bits int, unsigned bool) ([]byte, error) {
+	if unsigned {
+		if n.Sign() < 0 || n.BitLen() > bits {
+			return nil, fmt.Errorf("%v overflows uint%d", n, bits)
+		}
+		return common.LeftPadBytes(n.Bytes(), 32), nil
+	}
+	limit := new(big.Int).Lsh(common.Big1, uint(bits-1))
+	if n.Cmp(limit) >= 0 || n.Cmp(new(big.Int).Neg(limit)) < 0 {
+		return nil, fmt.Errorf("%v overflows int%d", n, bits)
+	}
+	if n.Sign() < 0 {
+		n = new(big.Int).Add(n, new(big.Int).Lsh(common.Big1, 256))
+	}
+	return common.LeftPadBytes(n.Bytes(), 32), nil
+}
+
+// parseTypedInteger parses an integer given as a JSON number or as a decimal
+// or 0x-prefixed hex string.
+func parseTypedInteger(value interface{}) (*big.Int, error) {
+	var s string
+	switch value := value.(type) {
+	case json.Number:
+		s = value.String()
+	case string:
+		s = value
+	case float64:
+		s = strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return nil, fmt.Errorf("invalid integer value %v", value)
+	}
+	n, ok := new(big.Int).SetString(s, 0)
+	if !ok {
+		return nil, fmt.Errorf("invalid integer value %q", s)
+	}
+	return n, nil
+}
+
+// parseTypedBytes parses a 0x-prefixed hex string.
+func parseTypedBytes(value interface{}) ([]byte, error) {
+	s, ok := value.(string)
+	if !ok || !strings.HasPrefix(s, "0x") {
+		return nil, fmt.Errorf("invalid bytes value %v", value)
+	}
+	b, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid bytes value %q", s)
+	}
+	return b, nil
+}
+
+// baseType strips the array suffixes of a type.
+func baseType(typ string) string {
+	if i := strings.Index(typ, "["); i >= 0 {
+		return typ[:i]
+	}
+	return typ
+}
@@ -0,0 +1,145 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package accounts
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/crypto"
+)
+
+func TestTextHash(t *testing.T) {
+	want := common.FromHex("0x50b2c43fd39106bafbba0da34fc430e1f91e3c96ea2acee2bc34119f92b37750")
+	if hash := TextHash([]byte("hello")); common.BytesToHash(hash) != common.BytesToHash(want) {
+		t.Errorf("hash mismatch: have %x, want %x", hash, want)
+	}
+}
+
+// mailTypedData is the example of the EIP-712 specification.
+const mailTypedData = `{
+	"types": {
+		"EIP712Domain": [
+			{"name": "name", "type": "string"},
+			{"name": "version", "type": "string"},
+			{"name": "chainId", "type": "uint256"},
+			{"name": "verifyingContract", "type": "address"}
+		],
+		"Person": [
+			{"name": "name", "type": "string"},
+			{"name": "wallet", "type": "address"}
+		],
+		"Mail": [
+			{"name": "from", "type": "Person"},
+			{"name": "to", "type": "Person"},
+			{"name": "contents", "type": "string"}
+		]
+	},
+	"primaryType": "Mail",
+	"domain": {
+		"name": "Ether Mail",
+		"version": "1",
+		"chainId": 1,
+		"verifyingContract": "0xCcCCccccCCCCcCCCCCCcCcCccCcCCCcCcccccccC"
+	},
+	"message": {
+		"from": {"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826"},
+		"to": {"name": "Bob", "wallet": "0xbBbBBBBbbBBBbbbBbbBbbbbBBbBbbbbBbBbbBBbB"},
+		"contents": "Hello, Bob!"
+	}
+}`
+
+func TestTypedDataHash(t *testing.T) {
+	var data TypedData
+	if err := json.Unmarshal([]byte(mailTypedData), &data); err != nil {
+		t.Fatal(err)
+	}
+	if enc, _ := data.EncodeType("Mail"); string(enc) != "Mail(Person from,Person to,string contents)Person(string name,address wallet)" {
+		t.Errorf("type encoding mismatch: %s", enc)
+	}
+	domain, err := data.HashStruct(TypedDataDomainType, data.Domain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "f2cee375fa42b42143804025fc449deafd50cc031ca257e0b194a650a912090f"; common.Bytes2Hex(domain) != want {
+		t.Errorf("domain hash mismatch: have %x, want %s", domain, want)
+	}
+	message, err := data.HashStruct(data.PrimaryType, data.Message)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "c52c0ee5d84264471806290a3f2c4cecfc5490626bf912d01f240d7a274b371e"; common.Bytes2Hex(message) != want {
+		t.Errorf("message hash mismatch: have %x, want %s", message, want)
+	}
+	hash, err := data.Hash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "be609aee343fb3c4b28e1df9e632fca64fcfaede20f02e86244efddf30957bd2"; common.Bytes2Hex(hash) != want {
+		t.Errorf("signing hash mismatch: have %x, want %s", hash, want)
+	}
+
+	if chainID, err := data.ChainID(); err != nil || chainID.Int64() != 1 {
+		t.Errorf("chain id mismatch: have %v, %v, want 1", chainID, err)
+	}
+}
+
+func TestTypedDataInvalid(t *testing.T) {
+	tests := []struct{ field, value string }{
+		{"contents", `42`},
+		{"from", `{"name": "Cow"}`},
+		{"from", `{"name": "Cow", "wallet": "0x1234"}`},
+		{"from", `{"name": "Cow", "wallet": "0xCD2a3d9F938E13CD947Ec05AbC7FE734Df8DD826", "age": 3}`},
+	}
+	for i, test := range tests {
+		var data TypedData
+		if err := json.Unmarshal([]byte(mailTypedData), &data); err != nil {
+			t.Fatal(err)
+		}
+		var value interface{}
+		if err := json.Unmarshal([]byte(test.value), &value); err != nil {
+			t.Fatal(err)
+		}
+		data.Message[test.field] = value
+		if _, err := data.Hash(); err == nil {
+			t.Errorf("test %d: expected error for %s %s", i, test.field, test.value)
+		}
+	}
+}
+
+func TestSignText(t *testing.T) {
+	dir, am := tmpManager(t)
+	defer os.RemoveAll(dir)
+
+	a, err := am.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := am.SignWithPassphrase(a.Address, "foo", TextHash([]byte("hello")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	pub, err := crypto.SigToPub(TextHash([]byte("hello")), sig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if addr := crypto.PubkeyToAddress(*pub); addr != a.Address {
+		t.Errorf("recovered address mismatch: have %x, want %x", addr, a.Address)
+	}
+}
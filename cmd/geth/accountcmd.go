@@ -20,8 +20,10 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"sort"
 
 	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
 	"github.com/ethereumproject/go-ethereum/console"
 	"github.com/ethereumproject/go-ethereum/crypto"
 	"github.com/ethereumproject/go-ethereum/logger"
@@ -34,6 +36,15 @@ var (
 		Name:  "index-accounts,indexaccounts",
 		Usage: "Enable key-value db store for indexing large amounts of key files",
 	}
+	HDPathFlag = cli.StringFlag{
+		Name:  "hd-path",
+		Usage: "BIP-44 root derivation path of HD wallet accounts (m/44'/60'/0'/0 for Ethereum wallets)",
+		Value: accounts.DefaultRootDerivationPath.String(),
+	}
+	UpgradeAllFlag = cli.BoolFlag{
+		Name:  "all",
+		Usage: "Upgrade all key files not encrypted with the current parameters",
+	}
 	walletCommand = cli.Command{
 		Name:  "wallet",
 		Usage: "Ethereum presale wallet",
@@ -146,6 +157,48 @@ geth account update <address>
 	changing your password is only possible interactively.
 				`,
 			},
+			{
+				Action: accountInspect,
+				Name:   "inspect",
+				Usage:  "Print the encryption of the key files",
+				Description: `
+
+geth account inspect
+
+	Lists the key files and HD wallet files with their format version, cipher and
+	key-derivation function, without decrypting them. Files not encrypted with
+	the current parameters, as set by --keystore-kdf and --light-kdf, are marked
+	as outdated. Addresses with more than one key file are reported, such keys
+	can't be unlocked by address.
+				`,
+			},
+			{
+				Action: accountUpgrade,
+				Name:   "upgrade",
+				Usage:  "Re-encrypt key files with the current parameters",
+				Flags:  []cli.Flag{UpgradeAllFlag},
+				Description: `
+
+geth account upgrade <address> [<address>...]
+geth account upgrade --all
+
+	Re-encrypts the outdated key files of the given accounts, or of all accounts
+	with --all, with the current key-derivation parameters, keeping their passphrases.
+	Files of an older format version are rewritten in the current version.
+	You are prompted for the passphrase of each file.
+
+	To move the keys to argon2id:
+
+		geth --keystore-kdf argon2id account upgrade --all
+
+	Note that with --light-kdf the keys are re-encrypted with weaker parameters.
+
+	For non-interactive use the passphrases can be given with the --password flag,
+	one per line in the order of the outdated files in 'geth account inspect':
+
+		geth --password <passwordfile> account upgrade --all
+				`,
+			},
 			{
 				Action: accountImport,
 				Name:   "import",
@@ -172,6 +225,55 @@ geth account import <keyfile>
 	nodes.
 				`,
 			},
+			{
+				Action: accountHDNew,
+				Name:   "hd-new",
+				Usage:  "Create a new HD wallet",
+				Flags:  []cli.Flag{HDPathFlag},
+				Description: `
+
+geth account hd-new
+
+	Creates a new hierarchical deterministic (BIP-32/39/44) wallet from a random
+	24 word mnemonic and derives its first account. Prints the mnemonic and the address.
+	The mnemonic is saved in encrypted format, you are prompted for a passphrase.
+
+	Write the mnemonic down, it restores all the accounts of the wallet.
+
+	The accounts are derived along the Ethereum Classic path m/44'/61'/0'/0 unless
+	another root path is given:
+
+		geth account hd-new --hd-path "m/44'/60'/0'/0"
+				`,
+			},
+			{
+				Action: accountHDImport,
+				Name:   "hd-import",
+				Usage:  "Import a BIP-39 mnemonic into a new HD wallet",
+				Flags:  []cli.Flag{HDPathFlag},
+				Description: `
+
+geth account hd-import <mnemonicfile>
+
+	Imports the BIP-39 mnemonic in <mnemonicfile> into a new HD wallet and derives
+	its first account. Prints the address.
+	The mnemonic is saved in encrypted format, you are prompted for a passphrase.
+
+	Use --hd-path "m/44'/60'/0'/0" to access the accounts of a wallet created for Ethereum.
+				`,
+			},
+			{
+				Action: accountHDDerive,
+				Name:   "hd-derive",
+				Usage:  "Derive the next account of a HD wallet",
+				Description: `
+
+geth account hd-derive <address>
+
+	Derives the next account of the HD wallet holding <address>. Prints the address.
+	You are prompted for the passphrase of the wallet.
+				`,
+			},
 			{
 				Action: accountIndex,
 				Name:   "index",
@@ -337,6 +439,186 @@ func accountUpdate(ctx *cli.Context) error {
 	return nil
 }
 
+func accountInspect(ctx *cli.Context) error {
+	accman := MakeAccountManager(ctx)
+	infos, err := accman.InspectKeys()
+	if err != nil {
+		log.Fatal("Could not inspect the key files: ", err)
+	}
+	var outdated, duplicated int
+	for _, info := range infos {
+		fmt.Printf("{%x} %s\n", info.Account.Address, info.Account.File)
+		if info.Err != nil {
+			fmt.Printf("\tunreadable: %v\n", info.Err)
+			continue
+		}
+		kind := "key file"
+		if info.HD {
+			kind = "HD wallet"
+		}
+		state := "current"
+		if !info.Current {
+			state = "outdated"
+			outdated++
+		}
+		fmt.Printf("\t%s version %s, %s, %s%s, %s\n", kind, info.Version, info.Cipher, info.KDF, kdfParamsString(info.KDFParams), state)
+		if len(info.Duplicates) > 0 {
+			duplicated++
+			fmt.Println("\tduplicate key files of the address:")
+			for _, a := range info.Duplicates {
+				fmt.Println("\t  ", a.File)
+			}
+		}
+	}
+	fmt.Printf("%d files, %d outdated, %d with duplicates\n", len(infos), outdated, duplicated)
+	return nil
+}
+
+// kdfParamsString formats the cost parameters of a KDF, leaving out its salt.
+func kdfParamsString(params map[string]interface{}) string {
+	var names []string
+	for name := range params {
+		if name != "salt" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	s := ""
+	for _, name := range names {
+		s += fmt.Sprintf(" %s=%v", name, params[name])
+	}
+	return s
+}
+
+// accountUpgrade re-encrypts outdated key files with the current parameters.
+func accountUpgrade(ctx *cli.Context) error {
+	all := ctx.Bool(UpgradeAllFlag.Name)
+	if !all && len(ctx.Args()) == 0 {
+		log.Fatal("No accounts specified to upgrade, use --all to upgrade all key files")
+	}
+	accman := MakeAccountManager(ctx)
+	infos, err := accman.InspectKeys()
+	if err != nil {
+		log.Fatal("Could not inspect the key files: ", err)
+	}
+
+	// Select the files of the given accounts, all accounts of a HD wallet
+	// selecting the wallet file
+	selected := make(map[string]bool)
+	if !all {
+		files := make(map[common.Address][]string)
+		for _, info := range infos {
+			files[info.Account.Address] = append(files[info.Account.Address], info.Account.File)
+		}
+		for _, w := range accman.HDWallets() {
+			for _, a := range w.Accounts[1:] {
+				files[a.Address] = append(files[a.Address], w.File)
+			}
+		}
+		for _, arg := range ctx.Args() {
+			account, err := MakeAddress(accman, arg)
+			if err != nil {
+				log.Fatal(err)
+			}
+			if len(files[account.Address]) == 0 {
+				log.Fatalf("No key file for account %s", arg)
+			}
+			for _, file := range files[account.Address] {
+				selected[file] = true
+			}
+		}
+	}
+
+	passwords := MakePasswordList(ctx)
+	var upgraded, failed int
+	for _, info := range infos {
+		if !all && !selected[info.Account.File] || info.Err != nil {
+			continue
+		}
+		if info.Current {
+			fmt.Printf("Up to date: {%x} %s\n", info.Account.Address, info.Account.File)
+			continue
+		}
+		password := getPassPhrase(fmt.Sprintf("Upgrading {%x} %s", info.Account.Address, info.Account.File), false, upgraded+failed, passwords)
+		if err := accman.Upgrade(info.Account, password); err != nil {
+			fmt.Printf("Could not upgrade %s: %v\n", info.Account.File, err)
+			failed++
+			continue
+		}
+		fmt.Printf("Upgraded {%x} %s\n", info.Account.Address, info.Account.File)
+		upgraded++
+	}
+	fmt.Printf("%d files upgraded\n", upgraded)
+	if failed > 0 {
+		log.Fatalf("%d files could not be upgraded", failed)
+	}
+	return nil
+}
+
+// hdPath returns the root derivation path of HD wallet accounts given by the CLI flags.
+func hdPath(ctx *cli.Context) accounts.DerivationPath {
+	path, err := accounts.ParseDerivationPath(ctx.String(HDPathFlag.Name))
+	if err != nil {
+		log.Fatal(err)
+	}
+	return path
+}
+
+// accountHDNew creates a new HD wallet into the keystore defined by the CLI flags.
+func accountHDNew(ctx *cli.Context) error {
+	path := hdPath(ctx)
+	accman := MakeAccountManager(ctx)
+	password := getPassPhrase("Your new HD wallet is locked with a password. Please give a password. Do not forget this password.", true, 0, MakePasswordList(ctx))
+
+	mnemonic, account, err := accman.NewHDWallet(password, path)
+	if err != nil {
+		log.Fatal("Failed to create HD wallet: ", err)
+	}
+	fmt.Printf("Mnemonic: %s\n", mnemonic)
+	fmt.Printf("Address: {%x}\n", account.Address)
+	return nil
+}
+
+func accountHDImport(ctx *cli.Context) error {
+	file := ctx.Args().First()
+	if len(file) == 0 {
+		log.Fatal("mnemonic file must be given as argument")
+	}
+	mnemonic, err := ioutil.ReadFile(file)
+	if err != nil {
+		log.Fatal("Could not read mnemonic file: ", err)
+	}
+	path := hdPath(ctx)
+	accman := MakeAccountManager(ctx)
+	password := getPassPhrase("Your new HD wallet is locked with a password. Please give a password. Do not forget this password.", true, 0, MakePasswordList(ctx))
+
+	account, err := accman.ImportHDWallet(string(mnemonic), password, path)
+	if err != nil {
+		log.Fatal("Could not import the mnemonic: ", err)
+	}
+	fmt.Printf("Address: {%x}\n", account.Address)
+	return nil
+}
+
+func accountHDDerive(ctx *cli.Context) error {
+	if len(ctx.Args()) == 0 {
+		log.Fatal("No HD wallet account specified")
+	}
+	accman := MakeAccountManager(ctx)
+	account, err := MakeAddress(accman, ctx.Args().First())
+	if err != nil {
+		log.Fatal(err)
+	}
+	password := getPassPhrase("", false, 0, MakePasswordList(ctx))
+
+	derived, err := accman.DeriveHDAccount(account, password)
+	if err != nil {
+		log.Fatal("Could not derive an account: ", err)
+	}
+	fmt.Printf("Address: {%x}\n", derived.Address)
+	return nil
+}
+
 func importWallet(ctx *cli.Context) error {
 	keyfile := ctx.Args().First()
 	if len(keyfile) == 0 {
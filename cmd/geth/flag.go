@@ -42,13 +42,16 @@ import (
 	"github.com/ethereumproject/go-ethereum/eth"
 	"github.com/ethereumproject/go-ethereum/ethdb"
 	"github.com/ethereumproject/go-ethereum/event"
+	"github.com/ethereumproject/go-ethereum/graphql"
 	"github.com/ethereumproject/go-ethereum/logger"
 	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/ethereumproject/go-ethereum/metrics"
 	"github.com/ethereumproject/go-ethereum/miner"
 	"github.com/ethereumproject/go-ethereum/node"
 	"github.com/ethereumproject/go-ethereum/p2p/discover"
 	"github.com/ethereumproject/go-ethereum/p2p/nat"
 	"github.com/ethereumproject/go-ethereum/pow"
+	"github.com/ethereumproject/go-ethereum/rpc"
 	"github.com/ethereumproject/go-ethereum/whisper"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -104,6 +107,7 @@ var (
 	}
 
 	devModeDataDirPath = filepath.Join(os.TempDir(), "/ethereum_dev_mode")
+	devAccountBalance  = new(big.Int).Mul(big.NewInt(1000000000), common.Ether)
 
 	cacheChainIdentity string
 	cacheChainConfig   *core.SufficientChainConfig
@@ -335,6 +339,87 @@ func MakeRPCModules(input string) []string {
 	return result
 }
 
+// mustMakeInfluxDBConfig creates the configuration of the InfluxDB metrics
+// reporter from the set command line flags. Every point is tagged with the
+// host, the chain identity and the node name.
+func mustMakeInfluxDBConfig(ctx *cli.Context, name string, config *core.SufficientChainConfig) metrics.InfluxDBConfig {
+	tags := map[string]string{
+		"chain": config.Identity,
+		"node":  name,
+	}
+	if host, err := os.Hostname(); err == nil {
+		tags["host"] = host
+	}
+	tagsFlag := aliasableName(MetricsInfluxDBTagsFlag.Name, ctx)
+	if s := ctx.GlobalString(tagsFlag); s != "" {
+		for _, tag := range strings.Split(s, ",") {
+			kv := strings.SplitN(strings.TrimSpace(tag), "=", 2)
+			if len(kv) != 2 || kv[0] == "" {
+				glog.Fatalf("%v: --%s: malformed tag %q, expected key=value", ErrInvalidFlag, tagsFlag, tag)
+			}
+			tags[kv[0]] = kv[1]
+		}
+	}
+	intervalFlag := aliasableName(MetricsInfluxDBIntervalFlag.Name, ctx)
+	interval := ctx.GlobalDuration(intervalFlag)
+	if interval <= 0 {
+		glog.Fatalf("%v: --%s: interval must be positive", ErrInvalidFlag, intervalFlag)
+	}
+	return metrics.InfluxDBConfig{
+		Endpoint: ctx.GlobalString(aliasableName(MetricsInfluxDBFlag.Name, ctx)),
+		Database: ctx.GlobalString(aliasableName(MetricsInfluxDBDatabaseFlag.Name, ctx)),
+		Username: ctx.GlobalString(aliasableName(MetricsInfluxDBUsernameFlag.Name, ctx)),
+		Password: ctx.GlobalString(aliasableName(MetricsInfluxDBPasswordFlag.Name, ctx)),
+		Interval: interval,
+		Tags:     tags,
+	}
+}
+
+// MakeRPCLimits creates the resource limits of the HTTP and WebSocket RPC
+// servers from the set command line flags.
+func MakeRPCLimits(ctx *cli.Context) *rpc.Limits {
+	return &rpc.Limits{
+		ReadTimeout:     ctx.GlobalDuration(aliasableName(RPCReadTimeoutFlag.Name, ctx)),
+		WriteTimeout:    ctx.GlobalDuration(aliasableName(RPCWriteTimeoutFlag.Name, ctx)),
+		IdleTimeout:     ctx.GlobalDuration(aliasableName(RPCIdleTimeoutFlag.Name, ctx)),
+		HandlerTimeout:  ctx.GlobalDuration(aliasableName(RPCCallTimeoutFlag.Name, ctx)),
+		MaxRequestSize:  int64(ctx.GlobalInt(aliasableName(RPCMaxRequestSizeFlag.Name, ctx))),
+		MaxInFlight:     ctx.GlobalInt(aliasableName(RPCMaxInFlightFlag.Name, ctx)),
+		MaxConnInFlight: ctx.GlobalInt(aliasableName(RPCMaxConnInFlightFlag.Name, ctx)),
+	}
+}
+
+// MustMakeRPCAccessPolicy creates the access policy of an RPC interface from
+// the named allow, deny and bearer tokens file flags. An empty tokens flag name
+// disables authentication.
+func MustMakeRPCAccessPolicy(ctx *cli.Context, allowFlag, denyFlag, tokensFlag string) rpc.AccessPolicy {
+	var policy rpc.AccessPolicy
+	if allow := ctx.GlobalString(aliasableName(allowFlag, ctx)); allow != "" {
+		policy.Allow = MakeRPCModules(allow)
+	}
+	if deny := ctx.GlobalString(aliasableName(denyFlag, ctx)); deny != "" {
+		policy.Deny = MakeRPCModules(deny)
+	}
+	if tokensFlag == "" {
+		return policy
+	}
+	if path := ctx.GlobalString(aliasableName(tokensFlag, ctx)); path != "" {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			glog.Fatalf("Failed to read bearer tokens file %s: %v", path, err)
+		}
+		for _, token := range strings.Split(string(data), "\n") {
+			if token = strings.TrimSpace(token); token != "" {
+				policy.Tokens = append(policy.Tokens, token)
+			}
+		}
+		if len(policy.Tokens) == 0 {
+			glog.Fatalf("Bearer tokens file %s contains no tokens", path)
+		}
+	}
+	return policy
+}
+
 // MakeHTTPRpcHost creates the HTTP RPC listener interface string from the set
 // command line flags, returning empty if the HTTP endpoint is disabled.
 func MakeHTTPRpcHost(ctx *cli.Context) string {
@@ -395,6 +480,16 @@ func MakeAccountManager(ctx *cli.Context) *accounts.Manager {
 	if err != nil {
 		glog.Fatalf("init account manager at %q: %s", keydir, err)
 	}
+	if err := m.SetKDF(ctx.GlobalString(aliasableName(KeyStoreKDFFlag.Name, ctx))); err != nil {
+		glog.Fatalf("%v: --%s: %v", ErrInvalidFlag, KeyStoreKDFFlag.Name, err)
+	}
+	if file := ctx.GlobalString(aliasableName(SigningRulesFlag.Name, ctx)); file != "" {
+		rules, err := accounts.LoadSigningRules(file, filepath.Join(datadir, "signing-counters.json"))
+		if err != nil {
+			glog.Fatalf("load signing rules: %v", err)
+		}
+		m.SetSigningRules(rules)
+	}
 	return m
 }
 
@@ -433,6 +528,21 @@ func MakeEtherbase(accman *accounts.Manager, ctx *cli.Context) common.Address {
 	return account.Address
 }
 
+// MakeTxWhitelist parses the sender addresses given by --miner-tx-whitelist.
+func MakeTxWhitelist(ctx *cli.Context) []common.Address {
+	var addrs []common.Address
+	for _, s := range strings.Split(ctx.GlobalString(aliasableName(TxWhitelistFlag.Name, ctx)), ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		if !common.IsHexAddress(s) {
+			log.Fatalf("Option %q: invalid address %q", aliasableName(TxWhitelistFlag.Name, ctx), s)
+		}
+		addrs = append(addrs, common.HexToAddress(s))
+	}
+	return addrs
+}
+
 // MakePasswordList reads password lines from the file specified by --password.
 func MakePasswordList(ctx *cli.Context) []string {
 	path := ctx.GlobalString(aliasableName(PasswordFileFlag.Name, ctx))
@@ -581,6 +691,24 @@ func MakeSystemNode(version string, ctx *cli.Context) *node.Node {
 			glog.Fatalf("%v: failed to register the Whisper service: ", ErrStackFail, err)
 		}
 	}
+	if ctx.GlobalBool(aliasableName(GraphQLEnabledFlag.Name, ctx)) {
+		if stackConf.HTTPHost == "" {
+			glog.Fatalf("%v: --%s requires the HTTP-RPC server (--%s)", ErrInvalidFlag, GraphQLEnabledFlag.Name, RPCEnabledFlag.Name)
+		}
+		if err := stack.Register(func(ctx *node.ServiceContext) (node.Service, error) {
+			var ethereum *eth.Ethereum
+			if err := ctx.Service(&ethereum); err != nil {
+				return nil, err
+			}
+			return graphql.New(ethereum.BlockChain(), ethereum.TxPool(), ethereum.Miner(), ethereum.ChainDb())
+		}); err != nil {
+			glog.Fatalf("%v: failed to register the GraphQL service: %v", ErrStackFail, err)
+		}
+	}
+
+	if ctx.GlobalString(aliasableName(MetricsInfluxDBFlag.Name, ctx)) != "" {
+		go metrics.ReportToInfluxDB(mustMakeInfluxDBConfig(ctx, name, config))
+	}
 
 	// If --mlog enabled, configure and create mlog dir and file
 	if ctx.GlobalString(MLogFlag.Name) != "off" {
@@ -613,24 +741,37 @@ func shouldAttemptDirMigration(ctx *cli.Context) bool {
 func mustMakeStackConf(ctx *cli.Context, name string, config *core.SufficientChainConfig) (stackConf *node.Config, shhEnable bool) {
 	// Configure the node's service container
 	stackConf = &node.Config{
-		DataDir:         MustMakeChainDataDir(ctx),
-		PrivateKey:      MakeNodeKey(ctx),
-		Name:            name,
-		NoDiscovery:     ctx.GlobalBool(aliasableName(NoDiscoverFlag.Name, ctx)),
-		BootstrapNodes:  config.ParsedBootstrap,
-		ListenAddr:      MakeListenAddress(ctx),
-		NAT:             MakeNAT(ctx),
-		MaxPeers:        ctx.GlobalInt(aliasableName(MaxPeersFlag.Name, ctx)),
-		MaxPendingPeers: ctx.GlobalInt(aliasableName(MaxPendingPeersFlag.Name, ctx)),
-		IPCPath:         MakeIPCPath(ctx),
-		HTTPHost:        MakeHTTPRpcHost(ctx),
-		HTTPPort:        ctx.GlobalInt(aliasableName(RPCPortFlag.Name, ctx)),
-		HTTPCors:        ctx.GlobalString(aliasableName(RPCCORSDomainFlag.Name, ctx)),
-		HTTPModules:     MakeRPCModules(ctx.GlobalString(aliasableName(RPCApiFlag.Name, ctx))),
-		WSHost:          MakeWSRpcHost(ctx),
-		WSPort:          ctx.GlobalInt(aliasableName(WSPortFlag.Name, ctx)),
-		WSOrigins:       ctx.GlobalString(aliasableName(WSAllowedOriginsFlag.Name, ctx)),
-		WSModules:       MakeRPCModules(ctx.GlobalString(aliasableName(WSApiFlag.Name, ctx))),
+		DataDir:                 MustMakeChainDataDir(ctx),
+		PrivateKey:              MakeNodeKey(ctx),
+		Name:                    name,
+		NoDiscovery:             ctx.GlobalBool(aliasableName(NoDiscoverFlag.Name, ctx)),
+		BootstrapNodes:          config.ParsedBootstrap,
+		ListenAddr:              MakeListenAddress(ctx),
+		NAT:                     MakeNAT(ctx),
+		MaxPeers:                ctx.GlobalInt(aliasableName(MaxPeersFlag.Name, ctx)),
+		MaxPendingPeers:         ctx.GlobalInt(aliasableName(MaxPendingPeersFlag.Name, ctx)),
+		IPCPath:                 MakeIPCPath(ctx),
+		HTTPHost:                MakeHTTPRpcHost(ctx),
+		HTTPPort:                ctx.GlobalInt(aliasableName(RPCPortFlag.Name, ctx)),
+		HTTPCors:                ctx.GlobalString(aliasableName(RPCCORSDomainFlag.Name, ctx)),
+		HTTPModules:             MakeRPCModules(ctx.GlobalString(aliasableName(RPCApiFlag.Name, ctx))),
+		HTTPVirtualHosts:        MakeRPCModules(ctx.GlobalString(aliasableName(RPCVirtualHostsFlag.Name, ctx))),
+		HTTPTLSCert:             ctx.GlobalString(aliasableName(RPCTLSCertFlag.Name, ctx)),
+		HTTPTLSKey:              ctx.GlobalString(aliasableName(RPCTLSKeyFlag.Name, ctx)),
+		HTTPPathPrefix:          ctx.GlobalString(aliasableName(RPCPathPrefixFlag.Name, ctx)),
+		WSHost:                  MakeWSRpcHost(ctx),
+		WSPort:                  ctx.GlobalInt(aliasableName(WSPortFlag.Name, ctx)),
+		WSOrigins:               ctx.GlobalString(aliasableName(WSAllowedOriginsFlag.Name, ctx)),
+		WSModules:               MakeRPCModules(ctx.GlobalString(aliasableName(WSApiFlag.Name, ctx))),
+		WSVirtualHosts:          MakeRPCModules(ctx.GlobalString(aliasableName(WSVirtualHostsFlag.Name, ctx))),
+		WSTLSCert:               ctx.GlobalString(aliasableName(WSTLSCertFlag.Name, ctx)),
+		WSTLSKey:                ctx.GlobalString(aliasableName(WSTLSKeyFlag.Name, ctx)),
+		WSPathPrefix:            ctx.GlobalString(aliasableName(WSPathPrefixFlag.Name, ctx)),
+		RPCLimits:               MakeRPCLimits(ctx),
+		RPCSlowRequestThreshold: ctx.GlobalDuration(aliasableName(RPCSlowCallFlag.Name, ctx)),
+		IPCAccess:               MustMakeRPCAccessPolicy(ctx, IPCAllowFlag.Name, IPCDenyFlag.Name, ""),
+		HTTPAccess:              MustMakeRPCAccessPolicy(ctx, RPCAllowFlag.Name, RPCDenyFlag.Name, RPCTokensFileFlag.Name),
+		WSAccess:                MustMakeRPCAccessPolicy(ctx, WSAllowFlag.Name, WSDenyFlag.Name, WSTokensFileFlag.Name),
 	}
 
 	// Configure the Whisper service
@@ -641,6 +782,9 @@ func mustMakeStackConf(ctx *cli.Context, name string, config *core.SufficientCha
 		if !ctx.GlobalIsSet(aliasableName(MaxPeersFlag.Name, ctx)) {
 			stackConf.MaxPeers = 0
 		}
+		if !ctx.GlobalIsSet(aliasableName(NoDiscoverFlag.Name, ctx)) {
+			stackConf.NoDiscovery = true
+		}
 		// From p2p/server.go:
 		// If the port is zero, the operating system will pick a port. The
 		// ListenAddr field will be updated with the actual address when
@@ -659,7 +803,20 @@ func mustMakeStackConf(ctx *cli.Context, name string, config *core.SufficientCha
 func mustMakeEthConf(ctx *cli.Context, sconf *core.SufficientChainConfig) *eth.Config {
 
 	accman := MakeAccountManager(ctx)
+	if endpoint := ctx.GlobalString(aliasableName(SignerFlag.Name, ctx)); endpoint != "" {
+		if ctx.GlobalString(aliasableName(UnlockedAccountFlag.Name, ctx)) != "" {
+			glog.Fatalf("--%s can't be used with an external signer", aliasableName(UnlockedAccountFlag.Name, ctx))
+		}
+		signer, err := accounts.NewExternalSigner(endpoint)
+		if err != nil {
+			glog.Fatalf("can't connect to external signer %s: %v", endpoint, err)
+		}
+		accman.UseExternalSigner(signer)
+		glog.V(logger.Info).Infof("Using external signer %s", endpoint)
+	}
 	passwords := MakePasswordList(ctx)
+	devChain := sconf.Identity == core.DevChainIdentity && ctx.GlobalBool(aliasableName(DevModeFlag.Name, ctx))
+	var devAccount accounts.Account
 
 	accounts := strings.Split(ctx.GlobalString(aliasableName(UnlockedAccountFlag.Name, ctx)), ",")
 	for i, account := range accounts {
@@ -668,6 +825,12 @@ func mustMakeEthConf(ctx *cli.Context, sconf *core.SufficientChainConfig) *eth.C
 		}
 	}
 
+	// The developer chain funds a developer account in its genesis
+	if devChain {
+		devAccount = mustMakeDeveloperAccount(ctx, accman)
+		sconf.Genesis = core.MakeDevGenesisDump(core.GenesisAccount{Address: devAccount.Address, Balance: devAccountBalance})
+	}
+
 	ethConf := &eth.Config{
 		ChainConfig:             sconf.ChainConfig,
 		Genesis:                 sconf.Genesis,
@@ -679,6 +842,8 @@ func mustMakeEthConf(ctx *cli.Context, sconf *core.SufficientChainConfig) *eth.C
 		AccountManager:          accman,
 		Etherbase:               MakeEtherbase(accman, ctx),
 		MinerThreads:            ctx.GlobalInt(aliasableName(MinerThreadsFlag.Name, ctx)),
+		TxPolicy:                ctx.GlobalString(aliasableName(TxPolicyFlag.Name, ctx)),
+		TxWhitelist:             MakeTxWhitelist(ctx),
 		NatSpec:                 ctx.GlobalBool(aliasableName(NatspecEnabledFlag.Name, ctx)),
 		DocRoot:                 ctx.GlobalString(aliasableName(DocRootFlag.Name, ctx)),
 		GasPrice:                new(big.Int),
@@ -713,11 +878,52 @@ func mustMakeEthConf(ctx *cli.Context, sconf *core.SufficientChainConfig) *eth.C
 		if !ctx.GlobalIsSet(aliasableName(GasPriceFlag.Name, ctx)) {
 			ethConf.GasPrice = new(big.Int)
 		}
+		// Seal blocks instantly on the developer chain
+		if devChain {
+			if !ctx.GlobalIsSet(aliasableName(EtherbaseFlag.Name, ctx)) {
+				ethConf.Etherbase = devAccount.Address
+			}
+			ethConf.InstantSeal = true
+			ethConf.InstantSealPeriod = time.Duration(ctx.GlobalInt(aliasableName(DevPeriodFlag.Name, ctx))) * time.Second
+			ethConf.AutoDAG = false
+		}
 	}
 
 	return ethConf
 }
 
+// mustMakeDeveloperAccount returns the account funded by the developer chain genesis:
+// the first account of the keystore, or a new account with an empty password if there is none.
+// Accounts with an empty password are unlocked.
+func mustMakeDeveloperAccount(ctx *cli.Context, accman *accounts.Manager) accounts.Account {
+	var dev accounts.Account
+	if accman.ExternalSigner() != nil {
+		accs := accman.Accounts()
+		if len(accs) == 0 {
+			glog.Fatalf("external signer %s holds no developer account", accman.ExternalSigner().Endpoint())
+		}
+		return accs[0]
+	}
+	if accs := accman.Accounts(); len(accs) > 0 {
+		dev = accs[0]
+	} else {
+		var err error
+		if dev, err = accman.NewAccount(""); err != nil {
+			glog.Fatalf("failed to create developer account: %v", err)
+		}
+		glog.V(logger.Info).Infof("Created developer account %x with an empty password", dev.Address)
+	}
+	// Signing only succeeds if the account was already unlocked by --unlock.
+	if _, err := accman.Sign(dev.Address, make([]byte, 32)); err != nil {
+		if err := accman.Unlock(dev, ""); err != nil {
+			glog.V(logger.Warn).Infof("WARNING: developer account %x is locked (%v), use --%s to unlock it", dev.Address, err, aliasableName(UnlockedAccountFlag.Name, ctx))
+			return dev
+		}
+	}
+	glog.V(logger.Info).Infof("Using developer account %x", dev.Address)
+	return dev
+}
+
 // mustMakeSufficientChainConfig makes a sufficent chain configuration (id, chainconfig, nodes,...)
 // based on --chain or defaults or fails hard.
 // - User must provide a full and complete config file if any is specified located at /custom/chain.json
@@ -752,6 +958,13 @@ func mustMakeSufficientChainConfig(ctx *cli.Context) *core.SufficientChainConfig
 
 	chainIdentity := mustMakeChainIdentity(ctx)
 
+	// Developer mode uses a private chain without any configuration file.
+	// Its genesis is completed with the developer account by mustMakeEthConf.
+	if chainIdentity == core.DevChainIdentity && ctx.GlobalBool(aliasableName(DevModeFlag.Name, ctx)) {
+		config = core.MakeDevChainConfig()
+		return config
+	}
+
 	// If chain identity is either of defaults (via config file or flag), use defaults.
 	if chainIdentitiesMain[chainIdentity] || chainIdentitiesMorden[chainIdentity] {
 		// Initialise chain configuration before handling migrations or setting up node.
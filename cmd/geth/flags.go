@@ -5,14 +5,17 @@ import (
 	"runtime"
 
 	"strings"
+	"time"
 
 	"path/filepath"
 
+	"github.com/ethereumproject/go-ethereum/accounts"
 	"github.com/ethereumproject/go-ethereum/common"
 	"github.com/ethereumproject/go-ethereum/core"
 	"github.com/ethereumproject/go-ethereum/eth"
 	"github.com/ethereumproject/go-ethereum/logger"
 	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/ethereumproject/go-ethereum/miner"
 	"github.com/ethereumproject/go-ethereum/rpc"
 	"gopkg.in/urfave/cli.v1"
 )
@@ -55,7 +58,11 @@ var (
 	}
 	DevModeFlag = cli.BoolFlag{
 		Name:  "dev",
-		Usage: "Developer mode: pre-configured private network with several debugging flags",
+		Usage: "Developer mode: private network sealing blocks instantly, with a pre-funded developer account",
+	}
+	DevPeriodFlag = cli.IntFlag{
+		Name:  "dev-period",
+		Usage: "Block period in seconds of developer mode (0 = seal a block whenever transactions are pending)",
 	}
 	NodeNameFlag = cli.StringFlag{
 		Name:  "identity,name",
@@ -88,6 +95,11 @@ var (
 		Name:  "light-kdf,lightkdf",
 		Usage: "Reduce key-derivation RAM & CPU usage at some expense of KDF strength",
 	}
+	KeyStoreKDFFlag = cli.StringFlag{
+		Name:  "keystore-kdf",
+		Usage: "Key-derivation function of newly encrypted keys (scrypt or argon2id)",
+		Value: accounts.KDFScrypt,
+	}
 	// Network Split settings
 	ETFChain = cli.BoolFlag{
 		Name:  "etf",
@@ -127,6 +139,16 @@ var (
 		Usage: "Minimal gas price to accept for mining a transactions",
 		Value: new(big.Int).Mul(big.NewInt(20), common.Shannon).String(),
 	}
+	TxPolicyFlag = cli.StringFlag{
+		Name:  "miner-tx-policy",
+		Usage: "Transaction ordering policy for mined blocks (" + strings.Join(miner.SelectorNames, ", ") + ")",
+		Value: miner.DefaultSelectorName,
+	}
+	TxWhitelistFlag = cli.StringFlag{
+		Name:  "miner-tx-whitelist",
+		Usage: "Comma separated list of sender addresses prioritised by the whitelist transaction policy",
+		Value: "",
+	}
 	ExtraDataFlag = cli.StringFlag{
 		Name:  "extra-data,extradata",
 		Usage: "Freeform header field set by the miner",
@@ -142,6 +164,16 @@ var (
 		Usage: "Password file to use for non-inteactive password input",
 		Value: "",
 	}
+	SigningRulesFlag = cli.StringFlag{
+		Name:  "signing-rules",
+		Usage: "JSON rules file restricting the transactions signed with local accounts (recipients, values, daily limits, calldata selectors)",
+		Value: "",
+	}
+	SignerFlag = cli.StringFlag{
+		Name:  "signer",
+		Usage: "External signer holding the accounts (IPC socket path or http:// URL), no key is held by geth",
+		Value: "",
+	}
 
 	// logging and debug settings
 	VerbosityFlag = cli.GenericFlag{
@@ -224,6 +256,36 @@ var (
 		Name:  "metrics",
 		Usage: "Enables metrics reporting. When the value is a path, either relative or absolute, then a log is written to the respective file.",
 	}
+	MetricsAddrFlag = cli.StringFlag{
+		Name:  "metrics-addr",
+		Usage: "Listening address of an HTTP endpoint exposing all metrics in the Prometheus text format on /metrics, e.g. 127.0.0.1:6060",
+	}
+	MetricsInfluxDBFlag = cli.StringFlag{
+		Name:  "metrics-influxdb",
+		Usage: "URL of an InfluxDB compatible HTTP endpoint to periodically push all metrics to, e.g. http://localhost:8086",
+	}
+	MetricsInfluxDBDatabaseFlag = cli.StringFlag{
+		Name:  "metrics-influxdb-database",
+		Usage: "InfluxDB database to push the metrics to",
+		Value: "geth",
+	}
+	MetricsInfluxDBUsernameFlag = cli.StringFlag{
+		Name:  "metrics-influxdb-username",
+		Usage: "Username for InfluxDB authentication",
+	}
+	MetricsInfluxDBPasswordFlag = cli.StringFlag{
+		Name:  "metrics-influxdb-password",
+		Usage: "Password for InfluxDB authentication",
+	}
+	MetricsInfluxDBIntervalFlag = cli.DurationFlag{
+		Name:  "metrics-influxdb-interval",
+		Usage: "Interval between two pushes of the metrics to InfluxDB",
+		Value: 10 * time.Second,
+	}
+	MetricsInfluxDBTagsFlag = cli.StringFlag{
+		Name:  "metrics-influxdb-tags",
+		Usage: "Comma separated key=value tags attached to the pushed metrics, in addition to host, chain and node",
+	}
 	FakePoWFlag = cli.BoolFlag{
 		Name:  "fake-pow, fakepow",
 		Usage: "Disables proof-of-work verification",
@@ -292,6 +354,116 @@ var (
 		Usage: "Origins from which to accept websockets requests",
 		Value: "",
 	}
+	GraphQLEnabledFlag = cli.BoolFlag{
+		Name:  "graphql",
+		Usage: "Enable the GraphQL query endpoint at /graphql on the HTTP-RPC server (requires --rpc, access controlled as the graphql namespace)",
+	}
+	RPCPathPrefixFlag = cli.StringFlag{
+		Name:  "rpc-prefix",
+		Usage: "URL path prefix under which the HTTP-RPC interface is served, e.g. /rpc (empty = all paths)",
+	}
+	WSPathPrefixFlag = cli.StringFlag{
+		Name:  "ws-prefix",
+		Usage: "URL path prefix under which the WS-RPC interface is served, e.g. /ws (empty = all paths). Use the HTTP-RPC port to share its listener",
+	}
+	RPCVirtualHostsFlag = cli.StringFlag{
+		Name:  "rpc-vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept HTTP-RPC requests (server enforced, '*' = all)",
+		Value: "localhost",
+	}
+	RPCTLSCertFlag = cli.StringFlag{
+		Name:  "rpc-tls-cert",
+		Usage: "PEM encoded TLS certificate file of the HTTP-RPC server (enables HTTPS)",
+	}
+	RPCTLSKeyFlag = cli.StringFlag{
+		Name:  "rpc-tls-key",
+		Usage: "PEM encoded TLS private key file of the HTTP-RPC server",
+	}
+	WSVirtualHostsFlag = cli.StringFlag{
+		Name:  "ws-vhosts",
+		Usage: "Comma separated list of virtual hostnames from which to accept WS-RPC connections (server enforced, '*' = all)",
+		Value: "localhost",
+	}
+	WSTLSCertFlag = cli.StringFlag{
+		Name:  "ws-tls-cert",
+		Usage: "PEM encoded TLS certificate file of the WS-RPC server (enables secure websockets)",
+	}
+	WSTLSKeyFlag = cli.StringFlag{
+		Name:  "ws-tls-key",
+		Usage: "PEM encoded TLS private key file of the WS-RPC server",
+	}
+	RPCAllowFlag = cli.StringFlag{
+		Name:  "rpc-allow",
+		Usage: "Comma separated list of methods (namespace_method) or namespaces callable over the HTTP-RPC interface (empty = all offered API's)",
+	}
+	RPCDenyFlag = cli.StringFlag{
+		Name:  "rpc-deny",
+		Usage: "Comma separated list of methods (namespace_method) or namespaces not callable over the HTTP-RPC interface",
+	}
+	RPCTokensFileFlag = cli.StringFlag{
+		Name:  "rpc-tokens-file",
+		Usage: "File with one bearer token per line, one of which HTTP-RPC clients must present (empty = no authentication)",
+	}
+	WSAllowFlag = cli.StringFlag{
+		Name:  "ws-allow",
+		Usage: "Comma separated list of methods (namespace_method) or namespaces callable over the WS-RPC interface (empty = all offered API's)",
+	}
+	WSDenyFlag = cli.StringFlag{
+		Name:  "ws-deny",
+		Usage: "Comma separated list of methods (namespace_method) or namespaces not callable over the WS-RPC interface",
+	}
+	WSTokensFileFlag = cli.StringFlag{
+		Name:  "ws-tokens-file",
+		Usage: "File with one bearer token per line, one of which WS-RPC clients must present (empty = no authentication)",
+	}
+	IPCAllowFlag = cli.StringFlag{
+		Name:  "ipc-allow",
+		Usage: "Comma separated list of methods (namespace_method) or namespaces callable over the IPC-RPC interface (empty = all offered API's)",
+	}
+	IPCDenyFlag = cli.StringFlag{
+		Name:  "ipc-deny",
+		Usage: "Comma separated list of methods (namespace_method) or namespaces not callable over the IPC-RPC interface",
+	}
+	RPCReadTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc-read-timeout",
+		Usage: "Maximum duration for reading an HTTP-RPC request or a WS-RPC handshake (0 = no limit)",
+		Value: rpc.DefaultLimits.ReadTimeout,
+	}
+	RPCWriteTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc-write-timeout",
+		Usage: "Maximum duration for writing an HTTP-RPC response or a WS-RPC message (0 = no limit)",
+		Value: rpc.DefaultLimits.WriteTimeout,
+	}
+	RPCIdleTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc-idle-timeout",
+		Usage: "Maximum duration to keep an idle HTTP-RPC connection open (0 = no limit)",
+		Value: rpc.DefaultLimits.IdleTimeout,
+	}
+	RPCCallTimeoutFlag = cli.DurationFlag{
+		Name:  "rpc-call-timeout",
+		Usage: "Maximum execution time of an HTTP-RPC or WS-RPC method call (0 = no limit)",
+		Value: rpc.DefaultLimits.HandlerTimeout,
+	}
+	RPCSlowCallFlag = cli.DurationFlag{
+		Name:  "rpc-slow-call",
+		Usage: "Execution time from which on IPC-RPC, HTTP-RPC and WS-RPC method calls are logged as slow (0 = off)",
+		Value: 5 * time.Second,
+	}
+	RPCMaxRequestSizeFlag = cli.IntFlag{
+		Name:  "rpc-max-request-size",
+		Usage: "Maximum size in bytes of an HTTP-RPC request or a WS-RPC message (0 = no limit)",
+		Value: int(rpc.DefaultLimits.MaxRequestSize),
+	}
+	RPCMaxInFlightFlag = cli.IntFlag{
+		Name:  "rpc-max-inflight",
+		Usage: "Maximum number of concurrently executing HTTP-RPC or WS-RPC method calls (0 = no limit)",
+		Value: rpc.DefaultLimits.MaxInFlight,
+	}
+	RPCMaxConnInFlightFlag = cli.IntFlag{
+		Name:  "rpc-max-conn-inflight",
+		Usage: "Maximum number of concurrently executing requests per WS-RPC connection (0 = no limit)",
+		Value: rpc.DefaultLimits.MaxConnInFlight,
+	}
 	ExecFlag = cli.StringFlag{
 		Name:  "exec",
 		Usage: "Execute JavaScript statement (only in combination with console/attach)",
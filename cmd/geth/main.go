@@ -127,6 +127,8 @@ func makeCLIApp() (app *cli.App) {
 		NodeNameFlag,
 		UnlockedAccountFlag,
 		PasswordFileFlag,
+		SignerFlag,
+		SigningRulesFlag,
 		AccountsIndexFlag,
 		BootnodesFlag,
 		DataDirFlag,
@@ -137,12 +139,15 @@ func makeCLIApp() (app *cli.App) {
 		FastSyncFlag,
 		CacheFlag,
 		LightKDFFlag,
+		KeyStoreKDFFlag,
 		JSpathFlag,
 		ListenPortFlag,
 		MaxPeersFlag,
 		MaxPendingPeersFlag,
 		EtherbaseFlag,
 		GasPriceFlag,
+		TxPolicyFlag,
+		TxWhitelistFlag,
 		MinerThreadsFlag,
 		MiningEnabledFlag,
 		MiningGPUFlag,
@@ -162,6 +167,31 @@ func makeCLIApp() (app *cli.App) {
 		WSPortFlag,
 		WSApiFlag,
 		WSAllowedOriginsFlag,
+		GraphQLEnabledFlag,
+		RPCPathPrefixFlag,
+		WSPathPrefixFlag,
+		RPCVirtualHostsFlag,
+		RPCTLSCertFlag,
+		RPCTLSKeyFlag,
+		WSVirtualHostsFlag,
+		WSTLSCertFlag,
+		WSTLSKeyFlag,
+		RPCAllowFlag,
+		RPCDenyFlag,
+		RPCTokensFileFlag,
+		WSAllowFlag,
+		WSDenyFlag,
+		WSTokensFileFlag,
+		IPCAllowFlag,
+		IPCDenyFlag,
+		RPCReadTimeoutFlag,
+		RPCWriteTimeoutFlag,
+		RPCIdleTimeoutFlag,
+		RPCCallTimeoutFlag,
+		RPCSlowCallFlag,
+		RPCMaxRequestSizeFlag,
+		RPCMaxInFlightFlag,
+		RPCMaxConnInFlightFlag,
 		IPCDisabledFlag,
 		IPCApiFlag,
 		IPCPathFlag,
@@ -169,6 +199,7 @@ func makeCLIApp() (app *cli.App) {
 		PreloadJSFlag,
 		WhisperEnabledFlag,
 		DevModeFlag,
+		DevPeriodFlag,
 		TestNetFlag,
 		NetworkIdFlag,
 		RPCCORSDomainFlag,
@@ -187,6 +218,13 @@ func makeCLIApp() (app *cli.App) {
 		MLogComponentsFlag,
 		BacktraceAtFlag,
 		MetricsFlag,
+		MetricsAddrFlag,
+		MetricsInfluxDBFlag,
+		MetricsInfluxDBDatabaseFlag,
+		MetricsInfluxDBUsernameFlag,
+		MetricsInfluxDBPasswordFlag,
+		MetricsInfluxDBIntervalFlag,
+		MetricsInfluxDBTagsFlag,
 		FakePoWFlag,
 		SolcPathFlag,
 		GpoMinGasPriceFlag,
@@ -261,6 +299,13 @@ func makeCLIApp() (app *cli.App) {
 		if s := ctx.String("metrics"); s != "" {
 			go metrics.CollectToFile(s)
 		}
+		if addr := ctx.GlobalString(MetricsAddrFlag.Name); addr != "" {
+			go func() {
+				if err := metrics.ServePrometheus(addr); err != nil {
+					glog.Fatalf("metrics: failed to serve Prometheus endpoint on %s: %v", addr, err)
+				}
+			}()
+		}
 
 		// This should be the only place where reporting is enabled
 		// because it is not intended to run while testing.
@@ -275,10 +320,10 @@ func makeCLIApp() (app *cli.App) {
 			log.Fatalf("malformed %s flag value %q", aliasableName(TargetGasLimitFlag.Name, ctx), gasLimit)
 		}
 
-		// Set morden chain by default for dev mode.
+		// Set the private developer chain by default for dev mode.
 		if ctx.GlobalBool(aliasableName(DevModeFlag.Name, ctx)) {
 			if !ctx.GlobalIsSet(aliasableName(ChainIdentityFlag.Name, ctx)) {
-				if e := ctx.Set(aliasableName(ChainIdentityFlag.Name, ctx), "morden"); e != nil {
+				if e := ctx.Set(aliasableName(ChainIdentityFlag.Name, ctx), core.DevChainIdentity); e != nil {
 					log.Fatalf("failed to set chain value: %v", e)
 				}
 			}
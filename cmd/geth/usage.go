@@ -60,9 +60,11 @@ var AppHelpFlagGroups = []flagGroup{
 			KeyStoreDirFlag,
 			NetworkIdFlag,
 			DevModeFlag,
+			DevPeriodFlag,
 			NodeNameFlag,
 			FastSyncFlag,
 			LightKDFFlag,
+			KeyStoreKDFFlag,
 			CacheFlag,
 			BlockchainVersionFlag,
 			SputnikVMFlag,
@@ -73,6 +75,8 @@ var AppHelpFlagGroups = []flagGroup{
 		Flags: []cli.Flag{
 			UnlockedAccountFlag,
 			PasswordFileFlag,
+			SignerFlag,
+			SigningRulesFlag,
 			AccountsIndexFlag,
 		},
 	},
@@ -88,6 +92,31 @@ var AppHelpFlagGroups = []flagGroup{
 			WSPortFlag,
 			WSApiFlag,
 			WSAllowedOriginsFlag,
+			GraphQLEnabledFlag,
+			RPCPathPrefixFlag,
+			WSPathPrefixFlag,
+			RPCVirtualHostsFlag,
+			RPCTLSCertFlag,
+			RPCTLSKeyFlag,
+			WSVirtualHostsFlag,
+			WSTLSCertFlag,
+			WSTLSKeyFlag,
+			RPCAllowFlag,
+			RPCDenyFlag,
+			RPCTokensFileFlag,
+			WSAllowFlag,
+			WSDenyFlag,
+			WSTokensFileFlag,
+			IPCAllowFlag,
+			IPCDenyFlag,
+			RPCReadTimeoutFlag,
+			RPCWriteTimeoutFlag,
+			RPCIdleTimeoutFlag,
+			RPCCallTimeoutFlag,
+			RPCSlowCallFlag,
+			RPCMaxRequestSizeFlag,
+			RPCMaxInFlightFlag,
+			RPCMaxConnInFlightFlag,
 			IPCDisabledFlag,
 			IPCApiFlag,
 			IPCPathFlag,
@@ -120,6 +149,8 @@ var AppHelpFlagGroups = []flagGroup{
 			EtherbaseFlag,
 			TargetGasLimitFlag,
 			GasPriceFlag,
+			TxPolicyFlag,
+			TxWhitelistFlag,
 			ExtraDataFlag,
 		},
 	},
@@ -152,6 +183,13 @@ var AppHelpFlagGroups = []flagGroup{
 			MLogComponentsFlag,
 			BacktraceAtFlag,
 			MetricsFlag,
+			MetricsAddrFlag,
+			MetricsInfluxDBFlag,
+			MetricsInfluxDBDatabaseFlag,
+			MetricsInfluxDBUsernameFlag,
+			MetricsInfluxDBPasswordFlag,
+			MetricsInfluxDBIntervalFlag,
+			MetricsInfluxDBTagsFlag,
 			FakePoWFlag,
 		},
 	},
@@ -0,0 +1,188 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of go-ethereum.
+//
+// go-ethereum is free software: you can redistribute it and/or modify
+// it under the terms of the GNU General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// go-ethereum is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU General Public License for more details.
+//
+// You should have received a copy of the GNU General Public License
+// along with go-ethereum. If not, see <http://www.gnu.org/licenses/>.
+
+// signer is a standalone external signer holding a keystore. geth delegates
+// its accounts and signing to it with the --signer flag, so that no key is
+// held by the node. Every request is approved on the terminal or by a rules
+// file and can be recorded in an audit log.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"math/big"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/console"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/ethereumproject/go-ethereum/rpc"
+	"github.com/ethereumproject/go-ethereum/signer"
+)
+
+// Version is the application revision identifier. It can be set with the linker
+// as in: go build -ldflags "-X main.Version="`git describe --tags`
+var Version = "unknown"
+
+var (
+	keystoreDir  = flag.String("keystore", "", "directory of the key files")
+	ipcPath      = flag.String("ipc", "signer.ipc", "path of the IPC socket served to geth, empty to disable")
+	httpAddr     = flag.String("http", "", "address of the HTTP endpoint served to geth (e.g. 127.0.0.1:8550), empty to disable")
+	rulesFile    = flag.String("rules", "", "JSON signing rules file approving the requests it allows without prompting, rejecting any other")
+	auditFile    = flag.String("audit", "", "file the requests are appended to")
+	unlock       = flag.String("unlock", "", "comma separated list of accounts to unlock, required by -rules")
+	passwordFile = flag.String("password", "", "password file for -unlock, one password per line")
+	chainID      = flag.String("chainid", "", "only sign transactions replay protected for this chain id")
+	lightKDF     = flag.Bool("lightkdf", false, "use the light scrypt parameters for new keys")
+	versionFlag  = flag.Bool("version", false, "print the revision identifier and exit")
+)
+
+func main() {
+	flag.Var(glog.GetVerbosity(), "verbosity", "log verbosity (0-9)")
+	flag.Var(glog.GetVModule(), "vmodule", "log verbosity pattern")
+	glog.SetToStderr(true)
+	flag.Parse()
+
+	if *versionFlag {
+		fmt.Println("signer version", Version)
+		os.Exit(0)
+	}
+	if *keystoreDir == "" {
+		log.Fatal("Use -keystore to specify the key directory")
+	}
+	if *ipcPath == "" && *httpAddr == "" {
+		log.Fatal("Use -ipc or -http to specify an endpoint")
+	}
+
+	scryptN, scryptP := accounts.StandardScryptN, accounts.StandardScryptP
+	if *lightKDF {
+		scryptN, scryptP = accounts.LightScryptN, accounts.LightScryptP
+	}
+	am, err := accounts.NewManager(*keystoreDir, scryptN, scryptP, false)
+	if err != nil {
+		log.Fatalf("can't open keystore: %v", err)
+	}
+	unlockAccounts(am)
+
+	var ui signer.UI = signer.NewCommandlineUI(console.Stdin, os.Stdout)
+	if *rulesFile != "" {
+		counters := filepath.Join(filepath.Dir(filepath.Clean(*keystoreDir)), "signing-counters.json")
+		rules, err := accounts.LoadSigningRules(*rulesFile, counters)
+		if err != nil {
+			log.Fatal(err)
+		}
+		// The manager enforces the daily limits of the approved transactions
+		am.SetSigningRules(rules)
+		ui = signer.NewRulesUI(rules)
+	}
+	var audit *signer.AuditLog
+	if *auditFile != "" {
+		if audit, err = signer.NewAuditLog(*auditFile); err != nil {
+			log.Fatalf("can't open audit log: %v", err)
+		}
+	}
+	var chain *big.Int
+	if *chainID != "" {
+		var ok bool
+		if chain, ok = new(big.Int).SetString(*chainID, 0); !ok {
+			log.Fatalf("invalid chain id %q", *chainID)
+		}
+	}
+
+	srv, err := signer.NewServer(signer.NewSignerAPI(am, ui, audit, chain))
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *ipcPath != "" {
+		listener, err := rpc.CreateIPCListener(*ipcPath)
+		if err != nil {
+			log.Fatalf("can't listen on %s: %v", *ipcPath, err)
+		}
+		defer listener.Close()
+		go serveIPC(srv, listener)
+		fmt.Printf("Serving IPC endpoint %s\n", *ipcPath)
+	}
+	if *httpAddr != "" {
+		listener, err := net.Listen("tcp", *httpAddr)
+		if err != nil {
+			log.Fatalf("can't listen on %s: %v", *httpAddr, err)
+		}
+		defer listener.Close()
+		go rpc.NewHTTPServer("", []string{"localhost"}, srv).Serve(listener)
+		fmt.Printf("Serving HTTP endpoint http://%s\n", *httpAddr)
+	}
+
+	sigc := make(chan os.Signal, 1)
+	signal.Notify(sigc, os.Interrupt)
+	<-sigc
+}
+
+// unlockAccounts unlocks the accounts given by -unlock with the passwords of
+// the -password file, the last one applying to all remaining accounts.
+func unlockAccounts(am *accounts.Manager) {
+	if *unlock == "" {
+		return
+	}
+	var passwords []string
+	if *passwordFile != "" {
+		data, err := ioutil.ReadFile(*passwordFile)
+		if err != nil {
+			log.Fatalf("can't read password file: %v", err)
+		}
+		passwords = strings.Split(strings.TrimRight(string(data), "\r\n"), "\n")
+		for i := range passwords {
+			passwords[i] = strings.TrimRight(passwords[i], "\r")
+		}
+	}
+	for i, addr := range strings.Split(*unlock, ",") {
+		addr = strings.TrimSpace(addr)
+		if !common.IsHexAddress(addr) {
+			log.Fatalf("invalid account %q", addr)
+		}
+		var password string
+		switch {
+		case i < len(passwords):
+			password = passwords[i]
+		case len(passwords) > 0:
+			password = passwords[len(passwords)-1]
+		default:
+			var err error
+			if password, err = console.Stdin.PromptPassword(fmt.Sprintf("Passphrase of %s: ", addr)); err != nil {
+				log.Fatal(err)
+			}
+		}
+		if err := am.Unlock(accounts.Account{Address: common.HexToAddress(addr)}, password); err != nil {
+			log.Fatalf("can't unlock %s: %v", addr, err)
+		}
+	}
+}
+
+func serveIPC(srv *rpc.Server, listener net.Listener) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go srv.ServeCodec(rpc.NewJSONCodec(conn), rpc.OptionMethodInvocation)
+	}
+}
@@ -0,0 +1,378 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package multisig implements the wallet RPC namespace, which operates the
+// Gnosis multisig wallets of package contract with the accounts of the node.
+package multisig
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/accounts/abi"
+	"github.com/ethereumproject/go-ethereum/accounts/abi/bind"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/contracts/multisig/contract"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+// executeGasReserve is the gas the wallet doesn't forward to the destination
+// when it executes a wallet transaction.
+var executeGasReserve = big.NewInt(34710)
+
+var (
+	ErrNotOwner         = errors.New("sender is not an owner of the wallet")
+	ErrUnknownTx        = errors.New("unknown wallet transaction")
+	ErrExecuted         = errors.New("wallet transaction already executed")
+	ErrConfirmed        = errors.New("wallet transaction already confirmed by sender")
+	ErrNotConfirmed     = errors.New("wallet transaction not confirmed by sender")
+	ErrNotEnoughConfirm = errors.New("wallet transaction lacks confirmations")
+)
+
+// PrivateWalletAPI operates multisig wallets. The transactions are
+// signed with the accounts of the node, which must be unlocked. The conditions
+// the wallet enforces are checked against the pending state first, as failing
+// wallet calls would consume all of their gas.
+type PrivateWalletAPI struct {
+	backend bind.ContractBackend
+	am      *accounts.Manager
+	signer  func() types.Signer
+}
+
+// NewPrivateWalletAPI creates a new wallet API. Signer returns the transaction
+// signer of the next block.
+func NewPrivateWalletAPI(backend bind.ContractBackend, am *accounts.Manager, signer func() types.Signer) *PrivateWalletAPI {
+	return &PrivateWalletAPI{backend: backend, am: am, signer: signer}
+}
+
+// WalletInfo describes a multisig wallet.
+type WalletInfo struct {
+	Owners           []common.Address `json:"owners"`
+	Required         *rpc.HexNumber   `json:"required"`
+	TransactionCount *rpc.HexNumber   `json:"transactionCount"`
+}
+
+// PendingTransaction is a wallet transaction which isn't executed yet.
+type PendingTransaction struct {
+	ID            *rpc.HexNumber   `json:"id"`
+	Destination   common.Address   `json:"destination"`
+	Value         *rpc.HexNumber   `json:"value"`
+	Data          string           `json:"data"`
+	Confirmations *rpc.HexNumber   `json:"confirmations"`
+	ConfirmedBy   []common.Address `json:"confirmedBy"`
+	Executable    bool             `json:"executable"`
+}
+
+// transactOpts returns the options to send transactions from an account of the node.
+func (api *PrivateWalletAPI) transactOpts(from common.Address) *bind.TransactOpts {
+	return &bind.TransactOpts{
+		From: from,
+		Signer: func(_ types.Signer, addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
+			if addr != from {
+				return nil, errors.New("not authorized to sign this account")
+			}
+			return api.am.SignTx(addr, tx, api.signer())
+		},
+	}
+}
+
+// Info returns the owners, the required confirmations and the number of
+// transactions of a wallet.
+func (api *PrivateWalletAPI) Info(wallet common.Address) (*WalletInfo, error) {
+	w, err := api.bind(wallet)
+	if err != nil {
+		return nil, err
+	}
+	owners, err := w.GetOwners()
+	if err != nil {
+		return nil, err
+	}
+	required, err := w.Required()
+	if err != nil {
+		return nil, err
+	}
+	count, err := w.TransactionCount()
+	if err != nil {
+		return nil, err
+	}
+	return &WalletInfo{
+		Owners:           owners,
+		Required:         rpc.NewHexNumber(required),
+		TransactionCount: rpc.NewHexNumber(count),
+	}, nil
+}
+
+// Submit proposes a transaction of value wei with the 0x prefixed hex encoded
+// data to the destination, and confirms it for the sender. The transaction gets
+// the next id of the wallet once mined, see Pending. The wallet executes it
+// right away when it requires a single confirmation.
+func (api *PrivateWalletAPI) Submit(wallet, from, to common.Address, value *rpc.HexNumber, data string) (common.Hash, error) {
+	input, err := decodeData(data)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	w, err := api.bind(wallet)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkOwner(w, from); err != nil {
+		return common.Hash{}, err
+	}
+	if (to == common.Address{}) {
+		return common.Hash{}, errors.New("zero destination address")
+	}
+	amount := new(big.Int)
+	if value != nil {
+		amount = value.BigInt()
+	}
+	opts, err := api.executeOpts(wallet, from, "submitTransaction", to, amount, input)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := w.Contract.SubmitTransaction(opts, to, amount, input)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// Confirm confirms the wallet transaction id for the sender. The wallet
+// executes the transaction with the last required confirmation.
+func (api *PrivateWalletAPI) Confirm(wallet, from common.Address, id rpc.HexNumber) (common.Hash, error) {
+	w, err := api.bind(wallet)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkOwner(w, from); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkPending(w, id.BigInt()); err != nil {
+		return common.Hash{}, err
+	}
+	confirmed, err := w.Confirmations(id.BigInt(), from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if confirmed {
+		return common.Hash{}, ErrConfirmed
+	}
+	opts, err := api.executeOpts(wallet, from, "confirmTransaction", id.BigInt())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := w.Contract.ConfirmTransaction(opts, id.BigInt())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// Revoke withdraws the confirmation of the wallet transaction id by the sender.
+func (api *PrivateWalletAPI) Revoke(wallet, from common.Address, id rpc.HexNumber) (common.Hash, error) {
+	w, err := api.bind(wallet)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkOwner(w, from); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkPending(w, id.BigInt()); err != nil {
+		return common.Hash{}, err
+	}
+	confirmed, err := w.Confirmations(id.BigInt(), from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if !confirmed {
+		return common.Hash{}, ErrNotConfirmed
+	}
+	tx, err := w.Contract.RevokeConfirmation(api.transactOpts(from), id.BigInt())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// Execute executes the wallet transaction id once it is confirmed by the
+// required number of owners, the sender being one of them. A failing call of
+// the destination doesn't execute the transaction, so that it can be retried.
+func (api *PrivateWalletAPI) Execute(wallet, from common.Address, id rpc.HexNumber) (common.Hash, error) {
+	w, err := api.bind(wallet)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkOwner(w, from); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkPending(w, id.BigInt()); err != nil {
+		return common.Hash{}, err
+	}
+	confirmed, err := w.Confirmations(id.BigInt(), from)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if !confirmed {
+		return common.Hash{}, ErrNotConfirmed
+	}
+	confirmations, err := w.GetConfirmationCount(id.BigInt())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	required, err := w.Required()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if confirmations.Cmp(required) < 0 {
+		return common.Hash{}, fmt.Errorf("%v: have %v, want %v", ErrNotEnoughConfirm, confirmations, required)
+	}
+	opts, err := api.executeOpts(wallet, from, "executeTransaction", id.BigInt())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	tx, err := w.Contract.ExecuteTransaction(opts, id.BigInt())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return tx.Hash(), nil
+}
+
+// Pending lists the wallet transactions which aren't executed yet, with the
+// owners who confirmed them.
+func (api *PrivateWalletAPI) Pending(wallet common.Address) ([]PendingTransaction, error) {
+	w, err := api.bind(wallet)
+	if err != nil {
+		return nil, err
+	}
+	required, err := w.Required()
+	if err != nil {
+		return nil, err
+	}
+	count, err := w.TransactionCount()
+	if err != nil {
+		return nil, err
+	}
+	pending := []PendingTransaction{}
+	for id := new(big.Int); id.Cmp(count) < 0; id = new(big.Int).Add(id, common.Big1) {
+		tx, err := w.Transactions(id)
+		if err != nil {
+			return nil, err
+		}
+		if tx.Executed {
+			continue
+		}
+		confirmedBy, err := w.GetConfirmations(id)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, PendingTransaction{
+			ID:            rpc.NewHexNumber(id),
+			Destination:   tx.Destination,
+			Value:         rpc.NewHexNumber(tx.Value),
+			Data:          fmt.Sprintf("0x%x", tx.Data),
+			Confirmations: rpc.NewHexNumber(len(confirmedBy)),
+			ConfirmedBy:   confirmedBy,
+			Executable:    big.NewInt(int64(len(confirmedBy))).Cmp(required) >= 0,
+		})
+	}
+	return pending, nil
+}
+
+// bind returns a session of the wallet reading the pending state.
+func (api *PrivateWalletAPI) bind(wallet common.Address) (*contract.MultiSigWalletSession, error) {
+	w, err := contract.NewMultiSigWallet(wallet, api.backend)
+	if err != nil {
+		return nil, err
+	}
+	session := &contract.MultiSigWalletSession{
+		Contract: w,
+		CallOpts: bind.CallOpts{Pending: true},
+	}
+	if _, err := session.Required(); err == bind.ErrNoCode {
+		return nil, fmt.Errorf("no wallet at %x", wallet)
+	} else if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func (api *PrivateWalletAPI) checkOwner(w *contract.MultiSigWalletSession, from common.Address) error {
+	owner, err := w.IsOwner(from)
+	if err != nil {
+		return err
+	}
+	if !owner {
+		return ErrNotOwner
+	}
+	return nil
+}
+
+// checkPending checks that the wallet transaction id exists and isn't executed
+// yet.
+func (api *PrivateWalletAPI) checkPending(w *contract.MultiSigWalletSession, id *big.Int) error {
+	count, err := w.TransactionCount()
+	if err != nil {
+		return err
+	}
+	if id.Sign() < 0 || id.Cmp(count) >= 0 {
+		return ErrUnknownTx
+	}
+	tx, err := w.Transactions(id)
+	if err != nil {
+		return err
+	}
+	if tx.Executed {
+		return ErrExecuted
+	}
+	return nil
+}
+
+// executeOpts returns the options to send a wallet call which may execute a
+// wallet transaction. The wallet keeps executeGasReserve gas for itself when
+// calling the destination, the estimate of the call doesn't account for it.
+func (api *PrivateWalletAPI) executeOpts(wallet, from common.Address, method string, args ...interface{}) (*bind.TransactOpts, error) {
+	parsed, err := abi.JSON(strings.NewReader(contract.MultiSigWalletABI))
+	if err != nil {
+		return nil, err
+	}
+	input, err := parsed.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	gas, err := api.backend.EstimateGasLimit(from, &wallet, new(big.Int), input)
+	if err != nil {
+		return nil, err
+	}
+	opts := api.transactOpts(from)
+	opts.GasLimit = new(big.Int).Add(gas, executeGasReserve)
+	return opts, nil
+}
+
+// decodeData decodes the 0x prefixed hex encoded data of a wallet transaction.
+func decodeData(data string) ([]byte, error) {
+	if !strings.HasPrefix(data, "0x") {
+		return nil, errors.New("data: hex string without 0x prefix")
+	}
+	b, err := hex.DecodeString(data[2:])
+	if err != nil {
+		return nil, fmt.Errorf("data: invalid hex string %q", data)
+	}
+	return b, nil
+}
@@ -0,0 +1,280 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package multisig
+
+import (
+	"bytes"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/accounts/abi"
+	"github.com/ethereumproject/go-ethereum/accounts/abi/bind"
+	"github.com/ethereumproject/go-ethereum/accounts/abi/bind/backends"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/contracts/multisig/contract"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+func newTestAPI(t *testing.T, n int) (string, *PrivateWalletAPI, *backends.SimulatedBackend, []common.Address) {
+	dir, err := ioutil.TempDir("", "multisig-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	am, err := accounts.NewManager(dir, 2, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		addrs   []common.Address
+		genesis []core.GenesisAccount
+	)
+	for i := 0; i < n; i++ {
+		a, err := am.NewAccount("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := am.Unlock(a, ""); err != nil {
+			t.Fatal(err)
+		}
+		addrs = append(addrs, a.Address)
+		genesis = append(genesis, core.GenesisAccount{Address: a.Address, Balance: big.NewInt(1e18)})
+	}
+	sim := backends.NewSimulatedBackend(genesis...)
+	signer := func() types.Signer { return core.DefaultConfigMorden.ChainConfig.GetSigner(big.NewInt(1)) }
+	return dir, NewPrivateWalletAPI(sim, am, signer), sim, addrs
+}
+
+// storeCode deploys a contract which stores the first word of its call data in
+// slot 0, and returns slot 0 and its balance when called without data:
+//
+//	CALLDATASIZE ISZERO PUSH1 0x0c JUMPI PUSH1 0 CALLDATALOAD PUSH1 0 SSTORE STOP
+//	JUMPDEST PUSH1 0 SLOAD PUSH1 0 MSTORE ADDRESS BALANCE PUSH1 0x20 MSTORE PUSH1 0x40 PUSH1 0 RETURN
+var storeCode = common.FromHex("601d600c600039601d6000f3" + "3615600c57600035600055005b600054600052303160205260406000f3")
+
+// deployWallet deploys the wallet compiled from contract/MultiSigWallet.sol,
+// skipping the test if the compiler output isn't generated.
+func deployWallet(t *testing.T, api *PrivateWalletAPI, sim *backends.SimulatedBackend, from common.Address, owners []common.Address, required int64) common.Address {
+	code, err := ioutil.ReadFile(filepath.Join("contract", "MultiSigWallet.bin"))
+	if os.IsNotExist(err) {
+		t.Skip("contract/MultiSigWallet.bin missing, run go generate with solc 0.4.15")
+	} else if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := abi.JSON(strings.NewReader(contract.MultiSigWalletABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr, _, _, err := bind.DeployContract(api.transactOpts(from), parsed, common.FromHex(strings.TrimSpace(string(code))), sim, owners, big.NewInt(required))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	return addr
+}
+
+// fund sends value wei to the wallet through its fallback function.
+func fund(t *testing.T, api *PrivateWalletAPI, sim *backends.SimulatedBackend, wallet, from common.Address, value int64) {
+	w, err := contract.NewMultiSigWallet(wallet, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := api.transactOpts(from)
+	opts.Value = big.NewInt(value)
+	if _, err := (&contract.MultiSigWalletRaw{Contract: w}).Transfer(opts); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+}
+
+func TestSubmitData(t *testing.T) {
+	dir, api, _, addrs := newTestAPI(t, 1)
+	defer os.RemoveAll(dir)
+
+	for _, data := range []string{"", "deadbeef", "0xdeadbee", "0xdeadbeeg", "0x 1234"} {
+		if _, err := api.Submit(addrs[0], addrs[0], common.HexToAddress("0x1234"), nil, data); err == nil || !strings.HasPrefix(err.Error(), "data:") {
+			t.Errorf("submit with data %q: have error %v, want invalid data", data, err)
+		}
+	}
+}
+
+func TestWallet(t *testing.T) {
+	dir, api, sim, addrs := newTestAPI(t, 4)
+	defer os.RemoveAll(dir)
+	owners, outsider := addrs[:3], addrs[3]
+
+	wallet := deployWallet(t, api, sim, owners[0], owners, 2)
+	info, err := api.Info(wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(info.Owners, owners) || info.Required.Int() != 2 || info.TransactionCount.Int() != 0 {
+		t.Fatalf("wallet info mismatch: %+v", info)
+	}
+	if _, err := api.Info(outsider); err == nil {
+		t.Error("no error for account without wallet")
+	}
+	fund(t, api, sim, wallet, outsider, 1e6)
+
+	dest := common.HexToAddress("0x1234")
+	if _, err := api.Submit(wallet, outsider, dest, rpc.NewHexNumber(1000), "0x"); err != ErrNotOwner {
+		t.Errorf("submit by outsider: have error %v, want %v", err, ErrNotOwner)
+	}
+	if _, err := api.Submit(wallet, owners[0], dest, rpc.NewHexNumber(1000), "0xdeadbeef"); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	pending, err := api.Pending(wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("have %d pending transactions, want 1", len(pending))
+	}
+	if p := pending[0]; p.ID.Int() != 0 || p.Destination != dest || p.Value.Int() != 1000 || p.Data != "0xdeadbeef" ||
+		p.Confirmations.Int() != 1 || !reflect.DeepEqual(p.ConfirmedBy, owners[:1]) || p.Executable {
+		t.Fatalf("pending transaction mismatch: %+v", p)
+	}
+
+	id := *rpc.NewHexNumber(0)
+	if _, err := api.Execute(wallet, owners[0], id); err == nil {
+		t.Error("executed transaction lacking confirmations")
+	}
+	if _, err := api.Execute(wallet, owners[1], id); err != ErrNotConfirmed {
+		t.Errorf("execute without confirmation: have error %v, want %v", err, ErrNotConfirmed)
+	}
+	if _, err := api.Confirm(wallet, owners[0], id); err != ErrConfirmed {
+		t.Errorf("second confirmation: have error %v, want %v", err, ErrConfirmed)
+	}
+	if _, err := api.Revoke(wallet, owners[1], id); err != ErrNotConfirmed {
+		t.Errorf("revoke without confirmation: have error %v, want %v", err, ErrNotConfirmed)
+	}
+	if _, err := api.Confirm(wallet, owners[1], *rpc.NewHexNumber(1)); err != ErrUnknownTx {
+		t.Errorf("confirm unknown transaction: have error %v, want %v", err, ErrUnknownTx)
+	}
+
+	// Revoking takes the confirmation back
+	if _, err := api.Revoke(wallet, owners[0], id); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if pending, err = api.Pending(wallet); err != nil {
+		t.Fatal(err)
+	}
+	if p := pending[0]; p.Confirmations.Int() != 0 || len(p.ConfirmedBy) != 0 {
+		t.Fatalf("pending transaction mismatch after revocation: %+v", p)
+	}
+
+	// The last required confirmation executes the transaction
+	for _, owner := range owners[:2] {
+		if _, err := api.Confirm(wallet, owner, id); err != nil {
+			t.Fatal(err)
+		}
+		sim.Commit()
+	}
+	if pending, err = api.Pending(wallet); err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("executed transaction still pending: %+v", pending[0])
+	}
+	if _, err := api.Execute(wallet, owners[0], id); err != ErrExecuted {
+		t.Errorf("second execution: have error %v, want %v", err, ErrExecuted)
+	}
+}
+
+func TestWalletContractDestination(t *testing.T) {
+	dir, api, sim, addrs := newTestAPI(t, 3)
+	defer os.RemoveAll(dir)
+
+	wallet := deployWallet(t, api, sim, addrs[0], addrs, 2)
+	fund(t, api, sim, wallet, addrs[0], 1000)
+	dest, _, _, err := bind.DeployContract(api.transactOpts(addrs[0]), abi.ABI{}, storeCode, sim)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+
+	// checkDest checks slot 0 and the balance of the destination.
+	checkDest := func(word common.Hash, balance int64) {
+		out, err := sim.ContractCall(dest, nil, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := append(word.Bytes(), common.BigToHash(big.NewInt(balance)).Bytes()...)
+		if !bytes.Equal(out, want) {
+			t.Fatalf("destination state mismatch: have %x, want %x", out, want)
+		}
+	}
+
+	// Executed by the last required confirmation
+	first := common.HexToHash("0x0102")
+	if _, err := api.Submit(wallet, addrs[0], dest, rpc.NewHexNumber(400), first.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	if _, err := api.Confirm(wallet, addrs[1], *rpc.NewHexNumber(0)); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	checkDest(first, 400)
+
+	// The wallet lacks the value, the call fails and the transaction is kept
+	second := common.HexToHash("0x0304")
+	if _, err := api.Submit(wallet, addrs[0], dest, rpc.NewHexNumber(1000), second.Hex()); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	id := *rpc.NewHexNumber(1)
+	if _, err := api.Confirm(wallet, addrs[1], id); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	checkDest(first, 400)
+	pending, err := api.Pending(wallet)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 || pending[0].ID.Int() != 1 || !pending[0].Executable {
+		t.Fatalf("failed transaction not pending: %+v", pending)
+	}
+
+	// Retried once funded
+	fund(t, api, sim, wallet, addrs[2], 1000)
+	if _, err := api.Execute(wallet, addrs[2], id); err != ErrNotConfirmed {
+		t.Errorf("execute without confirmation: have error %v, want %v", err, ErrNotConfirmed)
+	}
+	if _, err := api.Execute(wallet, addrs[1], id); err != nil {
+		t.Fatal(err)
+	}
+	sim.Commit()
+	checkDest(second, 1400)
+	if pending, err = api.Pending(wallet); err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 0 {
+		t.Fatalf("executed transaction still pending: %+v", pending[0])
+	}
+}
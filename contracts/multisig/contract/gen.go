@@ -0,0 +1,25 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package contract holds the abigen binding of the Gnosis MultiSigWallet.
+//
+// MultiSigWallet.sol is the audited wallet of github.com/gnosis/MultiSigWallet,
+// compiled with solc 0.4.15 (+commit.bbb8e64f) and the optimizer enabled. The
+// compiler output and the binding are regenerated with go generate.
+package contract
+
+//go:generate solc --optimize --abi --bin --overwrite -o . MultiSigWallet.sol
+//go:generate abigen --abi MultiSigWallet.abi --bin MultiSigWallet.bin --pkg contract --type MultiSigWallet --out multisig.go
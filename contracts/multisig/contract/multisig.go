@@ -0,0 +1,668 @@
+// This file is an automatically generated Go binding. Do not modify as any
+// change will likely be lost upon the next re-generation!
+
+package contract
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/ethereumproject/go-ethereum/accounts/abi"
+	"github.com/ethereumproject/go-ethereum/accounts/abi/bind"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+)
+
+// MultiSigWalletABI is the input ABI used to generate the binding from.
+const MultiSigWalletABI = `[{"constant":true,"inputs":[{"name":"","type":"uint256"}],"name":"owners","outputs":[{"name":"","type":"address"}],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"owner","type":"address"}],"name":"removeOwner","outputs":[],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"transactionId","type":"uint256"}],"name":"revokeConfirmation","outputs":[],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"","type":"address"}],"name":"isOwner","outputs":[{"name":"","type":"bool"}],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"","type":"uint256"},{"name":"","type":"address"}],"name":"confirmations","outputs":[{"name":"","type":"bool"}],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"pending","type":"bool"},{"name":"executed","type":"bool"}],"name":"getTransactionCount","outputs":[{"name":"count","type":"uint256"}],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"owner","type":"address"}],"name":"addOwner","outputs":[],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"transactionId","type":"uint256"}],"name":"isConfirmed","outputs":[{"name":"","type":"bool"}],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"transactionId","type":"uint256"}],"name":"getConfirmationCount","outputs":[{"name":"count","type":"uint256"}],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"","type":"uint256"}],"name":"transactions","outputs":[{"name":"destination","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"},{"name":"executed","type":"bool"}],"payable":false,"type":"function"},{"constant":true,"inputs":[],"name":"getOwners","outputs":[{"name":"","type":"address[]"}],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"from","type":"uint256"},{"name":"to","type":"uint256"},{"name":"pending","type":"bool"},{"name":"executed","type":"bool"}],"name":"getTransactionIds","outputs":[{"name":"_transactionIds","type":"uint256[]"}],"payable":false,"type":"function"},{"constant":true,"inputs":[{"name":"transactionId","type":"uint256"}],"name":"getConfirmations","outputs":[{"name":"_confirmations","type":"address[]"}],"payable":false,"type":"function"},{"constant":true,"inputs":[],"name":"transactionCount","outputs":[{"name":"","type":"uint256"}],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"_required","type":"uint256"}],"name":"changeRequirement","outputs":[],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"transactionId","type":"uint256"}],"name":"confirmTransaction","outputs":[],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"destination","type":"address"},{"name":"value","type":"uint256"},{"name":"data","type":"bytes"}],"name":"submitTransaction","outputs":[{"name":"transactionId","type":"uint256"}],"payable":false,"type":"function"},{"constant":true,"inputs":[],"name":"MAX_OWNER_COUNT","outputs":[{"name":"","type":"uint256"}],"payable":false,"type":"function"},{"constant":true,"inputs":[],"name":"required","outputs":[{"name":"","type":"uint256"}],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"owner","type":"address"},{"name":"newOwner","type":"address"}],"name":"replaceOwner","outputs":[],"payable":false,"type":"function"},{"constant":false,"inputs":[{"name":"transactionId","type":"uint256"}],"name":"executeTransaction","outputs":[],"payable":false,"type":"function"},{"inputs":[{"name":"_owners","type":"address[]"},{"name":"_required","type":"uint256"}],"payable":false,"type":"constructor"},{"payable":true,"type":"fallback"},{"anonymous":false,"inputs":[{"name":"sender","type":"address","indexed":true},{"name":"transactionId","type":"uint256","indexed":true}],"name":"Confirmation","type":"event"},{"anonymous":false,"inputs":[{"name":"sender","type":"address","indexed":true},{"name":"transactionId","type":"uint256","indexed":true}],"name":"Revocation","type":"event"},{"anonymous":false,"inputs":[{"name":"transactionId","type":"uint256","indexed":true}],"name":"Submission","type":"event"},{"anonymous":false,"inputs":[{"name":"transactionId","type":"uint256","indexed":true}],"name":"Execution","type":"event"},{"anonymous":false,"inputs":[{"name":"transactionId","type":"uint256","indexed":true}],"name":"ExecutionFailure","type":"event"},{"anonymous":false,"inputs":[{"name":"sender","type":"address","indexed":true},{"name":"value","type":"uint256","indexed":false}],"name":"Deposit","type":"event"},{"anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true}],"name":"OwnerAddition","type":"event"},{"anonymous":false,"inputs":[{"name":"owner","type":"address","indexed":true}],"name":"OwnerRemoval","type":"event"},{"anonymous":false,"inputs":[{"name":"required","type":"uint256","indexed":false}],"name":"RequirementChange","type":"event"}]`
+
+// MultiSigWallet is an auto generated Go binding around an Ethereum contract.
+type MultiSigWallet struct {
+	MultiSigWalletCaller     // Read-only binding to the contract
+	MultiSigWalletTransactor // Write-only binding to the contract
+}
+
+// MultiSigWalletCaller is an auto generated read-only Go binding around an Ethereum contract.
+type MultiSigWalletCaller struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigWalletTransactor is an auto generated write-only Go binding around an Ethereum contract.
+type MultiSigWalletTransactor struct {
+	contract *bind.BoundContract // Generic contract wrapper for the low level calls
+}
+
+// MultiSigWalletSession is an auto generated Go binding around an Ethereum contract,
+// with pre-set call and transact options.
+type MultiSigWalletSession struct {
+	Contract     *MultiSigWallet   // Generic contract binding to set the session for
+	CallOpts     bind.CallOpts     // Call options to use throughout this session
+	TransactOpts bind.TransactOpts // Transaction auth options to use throughout this session
+}
+
+// MultiSigWalletCallerSession is an auto generated read-only Go binding around an Ethereum contract,
+// with pre-set call options.
+type MultiSigWalletCallerSession struct {
+	Contract *MultiSigWalletCaller // Generic contract caller binding to set the session for
+	CallOpts bind.CallOpts         // Call options to use throughout this session
+}
+
+// MultiSigWalletTransactorSession is an auto generated write-only Go binding around an Ethereum contract,
+// with pre-set transact options.
+type MultiSigWalletTransactorSession struct {
+	Contract     *MultiSigWalletTransactor // Generic contract transactor binding to set the session for
+	TransactOpts bind.TransactOpts         // Transaction auth options to use throughout this session
+}
+
+// MultiSigWalletRaw is an auto generated low-level Go binding around an Ethereum contract.
+type MultiSigWalletRaw struct {
+	Contract *MultiSigWallet // Generic contract binding to access the raw methods on
+}
+
+// MultiSigWalletCallerRaw is an auto generated low-level read-only Go binding around an Ethereum contract.
+type MultiSigWalletCallerRaw struct {
+	Contract *MultiSigWalletCaller // Generic read-only contract binding to access the raw methods on
+}
+
+// MultiSigWalletTransactorRaw is an auto generated low-level write-only Go binding around an Ethereum contract.
+type MultiSigWalletTransactorRaw struct {
+	Contract *MultiSigWalletTransactor // Generic write-only contract binding to access the raw methods on
+}
+
+// NewMultiSigWallet creates a new instance of MultiSigWallet, bound to a specific deployed contract.
+func NewMultiSigWallet(address common.Address, backend bind.ContractBackend) (*MultiSigWallet, error) {
+	contract, err := bindMultiSigWallet(address, backend.(bind.ContractCaller), backend.(bind.ContractTransactor))
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigWallet{MultiSigWalletCaller: MultiSigWalletCaller{contract: contract}, MultiSigWalletTransactor: MultiSigWalletTransactor{contract: contract}}, nil
+}
+
+// NewMultiSigWalletCaller creates a new read-only instance of MultiSigWallet, bound to a specific deployed contract.
+func NewMultiSigWalletCaller(address common.Address, caller bind.ContractCaller) (*MultiSigWalletCaller, error) {
+	contract, err := bindMultiSigWallet(address, caller, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigWalletCaller{contract: contract}, nil
+}
+
+// NewMultiSigWalletTransactor creates a new write-only instance of MultiSigWallet, bound to a specific deployed contract.
+func NewMultiSigWalletTransactor(address common.Address, transactor bind.ContractTransactor) (*MultiSigWalletTransactor, error) {
+	contract, err := bindMultiSigWallet(address, nil, transactor)
+	if err != nil {
+		return nil, err
+	}
+	return &MultiSigWalletTransactor{contract: contract}, nil
+}
+
+// bindMultiSigWallet binds a generic wrapper to an already deployed contract.
+func bindMultiSigWallet(address common.Address, caller bind.ContractCaller, transactor bind.ContractTransactor) (*bind.BoundContract, error) {
+	parsed, err := abi.JSON(strings.NewReader(MultiSigWalletABI))
+	if err != nil {
+		return nil, err
+	}
+	return bind.NewBoundContract(address, parsed, caller, transactor), nil
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSigWallet *MultiSigWalletRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MultiSigWallet.Contract.MultiSigWalletCaller.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSigWallet *MultiSigWalletRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.MultiSigWalletTransactor.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSigWallet *MultiSigWalletRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.MultiSigWalletTransactor.contract.Transact(opts, method, params...)
+}
+
+// Call invokes the (constant) contract method with params as input values and
+// sets the output to result. The result type might be a single field for simple
+// returns, a slice of interfaces for anonymous returns and a struct for named
+// returns.
+func (_MultiSigWallet *MultiSigWalletCallerRaw) Call(opts *bind.CallOpts, result interface{}, method string, params ...interface{}) error {
+	return _MultiSigWallet.Contract.contract.Call(opts, result, method, params...)
+}
+
+// Transfer initiates a plain transaction to move funds to the contract, calling
+// its default method if one is available.
+func (_MultiSigWallet *MultiSigWalletTransactorRaw) Transfer(opts *bind.TransactOpts) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.contract.Transfer(opts)
+}
+
+// Transact invokes the (paid) contract method with params as input values.
+func (_MultiSigWallet *MultiSigWalletTransactorRaw) Transact(opts *bind.TransactOpts, method string, params ...interface{}) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.contract.Transact(opts, method, params...)
+}
+
+// MAX_OWNER_COUNT is a free data retrieval call binding the contract method 0xd74f8edd.
+//
+// Solidity: function MAX_OWNER_COUNT() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletCaller) MAX_OWNER_COUNT(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "MAX_OWNER_COUNT")
+	return *ret0, err
+}
+
+// MAX_OWNER_COUNT is a free data retrieval call binding the contract method 0xd74f8edd.
+//
+// Solidity: function MAX_OWNER_COUNT() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletSession) MAX_OWNER_COUNT() (*big.Int, error) {
+	return _MultiSigWallet.Contract.MAX_OWNER_COUNT(&_MultiSigWallet.CallOpts)
+}
+
+// MAX_OWNER_COUNT is a free data retrieval call binding the contract method 0xd74f8edd.
+//
+// Solidity: function MAX_OWNER_COUNT() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletCallerSession) MAX_OWNER_COUNT() (*big.Int, error) {
+	return _MultiSigWallet.Contract.MAX_OWNER_COUNT(&_MultiSigWallet.CallOpts)
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletCaller) Confirmations(opts *bind.CallOpts, arg0 *big.Int, arg1 common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "confirmations", arg0, arg1)
+	return *ret0, err
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletSession) Confirmations(arg0 *big.Int, arg1 common.Address) (bool, error) {
+	return _MultiSigWallet.Contract.Confirmations(&_MultiSigWallet.CallOpts, arg0, arg1)
+}
+
+// Confirmations is a free data retrieval call binding the contract method 0x3411c81c.
+//
+// Solidity: function confirmations( uint256,  address) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletCallerSession) Confirmations(arg0 *big.Int, arg1 common.Address) (bool, error) {
+	return _MultiSigWallet.Contract.Confirmations(&_MultiSigWallet.CallOpts, arg0, arg1)
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSigWallet *MultiSigWalletCaller) GetConfirmationCount(opts *bind.CallOpts, transactionId *big.Int) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "getConfirmationCount", transactionId)
+	return *ret0, err
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSigWallet *MultiSigWalletSession) GetConfirmationCount(transactionId *big.Int) (*big.Int, error) {
+	return _MultiSigWallet.Contract.GetConfirmationCount(&_MultiSigWallet.CallOpts, transactionId)
+}
+
+// GetConfirmationCount is a free data retrieval call binding the contract method 0x8b51d13f.
+//
+// Solidity: function getConfirmationCount(transactionId uint256) constant returns(count uint256)
+func (_MultiSigWallet *MultiSigWalletCallerSession) GetConfirmationCount(transactionId *big.Int) (*big.Int, error) {
+	return _MultiSigWallet.Contract.GetConfirmationCount(&_MultiSigWallet.CallOpts, transactionId)
+}
+
+// GetConfirmations is a free data retrieval call binding the contract method 0xb5dc40c3.
+//
+// Solidity: function getConfirmations(transactionId uint256) constant returns(_confirmations address[])
+func (_MultiSigWallet *MultiSigWalletCaller) GetConfirmations(opts *bind.CallOpts, transactionId *big.Int) ([]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "getConfirmations", transactionId)
+	return *ret0, err
+}
+
+// GetConfirmations is a free data retrieval call binding the contract method 0xb5dc40c3.
+//
+// Solidity: function getConfirmations(transactionId uint256) constant returns(_confirmations address[])
+func (_MultiSigWallet *MultiSigWalletSession) GetConfirmations(transactionId *big.Int) ([]common.Address, error) {
+	return _MultiSigWallet.Contract.GetConfirmations(&_MultiSigWallet.CallOpts, transactionId)
+}
+
+// GetConfirmations is a free data retrieval call binding the contract method 0xb5dc40c3.
+//
+// Solidity: function getConfirmations(transactionId uint256) constant returns(_confirmations address[])
+func (_MultiSigWallet *MultiSigWalletCallerSession) GetConfirmations(transactionId *big.Int) ([]common.Address, error) {
+	return _MultiSigWallet.Contract.GetConfirmations(&_MultiSigWallet.CallOpts, transactionId)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSigWallet *MultiSigWalletCaller) GetOwners(opts *bind.CallOpts) ([]common.Address, error) {
+	var (
+		ret0 = new([]common.Address)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "getOwners")
+	return *ret0, err
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSigWallet *MultiSigWalletSession) GetOwners() ([]common.Address, error) {
+	return _MultiSigWallet.Contract.GetOwners(&_MultiSigWallet.CallOpts)
+}
+
+// GetOwners is a free data retrieval call binding the contract method 0xa0e67e2b.
+//
+// Solidity: function getOwners() constant returns(address[])
+func (_MultiSigWallet *MultiSigWalletCallerSession) GetOwners() ([]common.Address, error) {
+	return _MultiSigWallet.Contract.GetOwners(&_MultiSigWallet.CallOpts)
+}
+
+// GetTransactionCount is a free data retrieval call binding the contract method 0x54741525.
+//
+// Solidity: function getTransactionCount(pending bool, executed bool) constant returns(count uint256)
+func (_MultiSigWallet *MultiSigWalletCaller) GetTransactionCount(opts *bind.CallOpts, pending bool, executed bool) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "getTransactionCount", pending, executed)
+	return *ret0, err
+}
+
+// GetTransactionCount is a free data retrieval call binding the contract method 0x54741525.
+//
+// Solidity: function getTransactionCount(pending bool, executed bool) constant returns(count uint256)
+func (_MultiSigWallet *MultiSigWalletSession) GetTransactionCount(pending bool, executed bool) (*big.Int, error) {
+	return _MultiSigWallet.Contract.GetTransactionCount(&_MultiSigWallet.CallOpts, pending, executed)
+}
+
+// GetTransactionCount is a free data retrieval call binding the contract method 0x54741525.
+//
+// Solidity: function getTransactionCount(pending bool, executed bool) constant returns(count uint256)
+func (_MultiSigWallet *MultiSigWalletCallerSession) GetTransactionCount(pending bool, executed bool) (*big.Int, error) {
+	return _MultiSigWallet.Contract.GetTransactionCount(&_MultiSigWallet.CallOpts, pending, executed)
+}
+
+// GetTransactionIds is a free data retrieval call binding the contract method 0xa8abe69a.
+//
+// Solidity: function getTransactionIds(from uint256, to uint256, pending bool, executed bool) constant returns(_transactionIds uint256[])
+func (_MultiSigWallet *MultiSigWalletCaller) GetTransactionIds(opts *bind.CallOpts, from *big.Int, to *big.Int, pending bool, executed bool) ([]*big.Int, error) {
+	var (
+		ret0 = new([]*big.Int)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "getTransactionIds", from, to, pending, executed)
+	return *ret0, err
+}
+
+// GetTransactionIds is a free data retrieval call binding the contract method 0xa8abe69a.
+//
+// Solidity: function getTransactionIds(from uint256, to uint256, pending bool, executed bool) constant returns(_transactionIds uint256[])
+func (_MultiSigWallet *MultiSigWalletSession) GetTransactionIds(from *big.Int, to *big.Int, pending bool, executed bool) ([]*big.Int, error) {
+	return _MultiSigWallet.Contract.GetTransactionIds(&_MultiSigWallet.CallOpts, from, to, pending, executed)
+}
+
+// GetTransactionIds is a free data retrieval call binding the contract method 0xa8abe69a.
+//
+// Solidity: function getTransactionIds(from uint256, to uint256, pending bool, executed bool) constant returns(_transactionIds uint256[])
+func (_MultiSigWallet *MultiSigWalletCallerSession) GetTransactionIds(from *big.Int, to *big.Int, pending bool, executed bool) ([]*big.Int, error) {
+	return _MultiSigWallet.Contract.GetTransactionIds(&_MultiSigWallet.CallOpts, from, to, pending, executed)
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletCaller) IsConfirmed(opts *bind.CallOpts, transactionId *big.Int) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "isConfirmed", transactionId)
+	return *ret0, err
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletSession) IsConfirmed(transactionId *big.Int) (bool, error) {
+	return _MultiSigWallet.Contract.IsConfirmed(&_MultiSigWallet.CallOpts, transactionId)
+}
+
+// IsConfirmed is a free data retrieval call binding the contract method 0x784547a7.
+//
+// Solidity: function isConfirmed(transactionId uint256) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletCallerSession) IsConfirmed(transactionId *big.Int) (bool, error) {
+	return _MultiSigWallet.Contract.IsConfirmed(&_MultiSigWallet.CallOpts, transactionId)
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletCaller) IsOwner(opts *bind.CallOpts, arg0 common.Address) (bool, error) {
+	var (
+		ret0 = new(bool)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "isOwner", arg0)
+	return *ret0, err
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletSession) IsOwner(arg0 common.Address) (bool, error) {
+	return _MultiSigWallet.Contract.IsOwner(&_MultiSigWallet.CallOpts, arg0)
+}
+
+// IsOwner is a free data retrieval call binding the contract method 0x2f54bf6e.
+//
+// Solidity: function isOwner( address) constant returns(bool)
+func (_MultiSigWallet *MultiSigWalletCallerSession) IsOwner(arg0 common.Address) (bool, error) {
+	return _MultiSigWallet.Contract.IsOwner(&_MultiSigWallet.CallOpts, arg0)
+}
+
+// Owners is a free data retrieval call binding the contract method 0x025e7c27.
+//
+// Solidity: function owners( uint256) constant returns(address)
+func (_MultiSigWallet *MultiSigWalletCaller) Owners(opts *bind.CallOpts, arg0 *big.Int) (common.Address, error) {
+	var (
+		ret0 = new(common.Address)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "owners", arg0)
+	return *ret0, err
+}
+
+// Owners is a free data retrieval call binding the contract method 0x025e7c27.
+//
+// Solidity: function owners( uint256) constant returns(address)
+func (_MultiSigWallet *MultiSigWalletSession) Owners(arg0 *big.Int) (common.Address, error) {
+	return _MultiSigWallet.Contract.Owners(&_MultiSigWallet.CallOpts, arg0)
+}
+
+// Owners is a free data retrieval call binding the contract method 0x025e7c27.
+//
+// Solidity: function owners( uint256) constant returns(address)
+func (_MultiSigWallet *MultiSigWalletCallerSession) Owners(arg0 *big.Int) (common.Address, error) {
+	return _MultiSigWallet.Contract.Owners(&_MultiSigWallet.CallOpts, arg0)
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletCaller) Required(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "required")
+	return *ret0, err
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletSession) Required() (*big.Int, error) {
+	return _MultiSigWallet.Contract.Required(&_MultiSigWallet.CallOpts)
+}
+
+// Required is a free data retrieval call binding the contract method 0xdc8452cd.
+//
+// Solidity: function required() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletCallerSession) Required() (*big.Int, error) {
+	return _MultiSigWallet.Contract.Required(&_MultiSigWallet.CallOpts)
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletCaller) TransactionCount(opts *bind.CallOpts) (*big.Int, error) {
+	var (
+		ret0 = new(*big.Int)
+	)
+	out := ret0
+	err := _MultiSigWallet.contract.Call(opts, out, "transactionCount")
+	return *ret0, err
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletSession) TransactionCount() (*big.Int, error) {
+	return _MultiSigWallet.Contract.TransactionCount(&_MultiSigWallet.CallOpts)
+}
+
+// TransactionCount is a free data retrieval call binding the contract method 0xb77bf600.
+//
+// Solidity: function transactionCount() constant returns(uint256)
+func (_MultiSigWallet *MultiSigWalletCallerSession) TransactionCount() (*big.Int, error) {
+	return _MultiSigWallet.Contract.TransactionCount(&_MultiSigWallet.CallOpts)
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSigWallet *MultiSigWalletCaller) Transactions(opts *bind.CallOpts, arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	ret := new(struct {
+		Destination common.Address
+		Value       *big.Int
+		Data        []byte
+		Executed    bool
+	})
+	out := ret
+	err := _MultiSigWallet.contract.Call(opts, out, "transactions", arg0)
+	return *ret, err
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSigWallet *MultiSigWalletSession) Transactions(arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	return _MultiSigWallet.Contract.Transactions(&_MultiSigWallet.CallOpts, arg0)
+}
+
+// Transactions is a free data retrieval call binding the contract method 0x9ace38c2.
+//
+// Solidity: function transactions( uint256) constant returns(destination address, value uint256, data bytes, executed bool)
+func (_MultiSigWallet *MultiSigWalletCallerSession) Transactions(arg0 *big.Int) (struct {
+	Destination common.Address
+	Value       *big.Int
+	Data        []byte
+	Executed    bool
+}, error) {
+	return _MultiSigWallet.Contract.Transactions(&_MultiSigWallet.CallOpts, arg0)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(owner address) returns()
+func (_MultiSigWallet *MultiSigWalletTransactor) AddOwner(opts *bind.TransactOpts, owner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "addOwner", owner)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(owner address) returns()
+func (_MultiSigWallet *MultiSigWalletSession) AddOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.AddOwner(&_MultiSigWallet.TransactOpts, owner)
+}
+
+// AddOwner is a paid mutator transaction binding the contract method 0x7065cb48.
+//
+// Solidity: function addOwner(owner address) returns()
+func (_MultiSigWallet *MultiSigWalletTransactorSession) AddOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.AddOwner(&_MultiSigWallet.TransactOpts, owner)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(_required uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactor) ChangeRequirement(opts *bind.TransactOpts, _required *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "changeRequirement", _required)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(_required uint256) returns()
+func (_MultiSigWallet *MultiSigWalletSession) ChangeRequirement(_required *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ChangeRequirement(&_MultiSigWallet.TransactOpts, _required)
+}
+
+// ChangeRequirement is a paid mutator transaction binding the contract method 0xba51a6df.
+//
+// Solidity: function changeRequirement(_required uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactorSession) ChangeRequirement(_required *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ChangeRequirement(&_MultiSigWallet.TransactOpts, _required)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactor) ConfirmTransaction(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "confirmTransaction", transactionId)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletSession) ConfirmTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ConfirmTransaction(&_MultiSigWallet.TransactOpts, transactionId)
+}
+
+// ConfirmTransaction is a paid mutator transaction binding the contract method 0xc01a8c84.
+//
+// Solidity: function confirmTransaction(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactorSession) ConfirmTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ConfirmTransaction(&_MultiSigWallet.TransactOpts, transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactor) ExecuteTransaction(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "executeTransaction", transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletSession) ExecuteTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ExecuteTransaction(&_MultiSigWallet.TransactOpts, transactionId)
+}
+
+// ExecuteTransaction is a paid mutator transaction binding the contract method 0xee22610b.
+//
+// Solidity: function executeTransaction(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactorSession) ExecuteTransaction(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ExecuteTransaction(&_MultiSigWallet.TransactOpts, transactionId)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(owner address) returns()
+func (_MultiSigWallet *MultiSigWalletTransactor) RemoveOwner(opts *bind.TransactOpts, owner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "removeOwner", owner)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(owner address) returns()
+func (_MultiSigWallet *MultiSigWalletSession) RemoveOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.RemoveOwner(&_MultiSigWallet.TransactOpts, owner)
+}
+
+// RemoveOwner is a paid mutator transaction binding the contract method 0x173825d9.
+//
+// Solidity: function removeOwner(owner address) returns()
+func (_MultiSigWallet *MultiSigWalletTransactorSession) RemoveOwner(owner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.RemoveOwner(&_MultiSigWallet.TransactOpts, owner)
+}
+
+// ReplaceOwner is a paid mutator transaction binding the contract method 0xe20056e6.
+//
+// Solidity: function replaceOwner(owner address, newOwner address) returns()
+func (_MultiSigWallet *MultiSigWalletTransactor) ReplaceOwner(opts *bind.TransactOpts, owner common.Address, newOwner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "replaceOwner", owner, newOwner)
+}
+
+// ReplaceOwner is a paid mutator transaction binding the contract method 0xe20056e6.
+//
+// Solidity: function replaceOwner(owner address, newOwner address) returns()
+func (_MultiSigWallet *MultiSigWalletSession) ReplaceOwner(owner common.Address, newOwner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ReplaceOwner(&_MultiSigWallet.TransactOpts, owner, newOwner)
+}
+
+// ReplaceOwner is a paid mutator transaction binding the contract method 0xe20056e6.
+//
+// Solidity: function replaceOwner(owner address, newOwner address) returns()
+func (_MultiSigWallet *MultiSigWalletTransactorSession) ReplaceOwner(owner common.Address, newOwner common.Address) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.ReplaceOwner(&_MultiSigWallet.TransactOpts, owner, newOwner)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactor) RevokeConfirmation(opts *bind.TransactOpts, transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "revokeConfirmation", transactionId)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletSession) RevokeConfirmation(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.RevokeConfirmation(&_MultiSigWallet.TransactOpts, transactionId)
+}
+
+// RevokeConfirmation is a paid mutator transaction binding the contract method 0x20ea8d86.
+//
+// Solidity: function revokeConfirmation(transactionId uint256) returns()
+func (_MultiSigWallet *MultiSigWalletTransactorSession) RevokeConfirmation(transactionId *big.Int) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.RevokeConfirmation(&_MultiSigWallet.TransactOpts, transactionId)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSigWallet *MultiSigWalletTransactor) SubmitTransaction(opts *bind.TransactOpts, destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSigWallet.contract.Transact(opts, "submitTransaction", destination, value, data)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSigWallet *MultiSigWalletSession) SubmitTransaction(destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.SubmitTransaction(&_MultiSigWallet.TransactOpts, destination, value, data)
+}
+
+// SubmitTransaction is a paid mutator transaction binding the contract method 0xc6427474.
+//
+// Solidity: function submitTransaction(destination address, value uint256, data bytes) returns(transactionId uint256)
+func (_MultiSigWallet *MultiSigWalletTransactorSession) SubmitTransaction(destination common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	return _MultiSigWallet.Contract.SubmitTransaction(&_MultiSigWallet.TransactOpts, destination, value, data)
+}
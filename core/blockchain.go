@@ -1333,6 +1333,7 @@ func (self *BlockChain) WriteBlock(block *types.Block) (status WriteStatus, err
 		status = CanonStatTy
 	} else {
 		status = SideStatTy
+		self.writeSideBlock(block, externTd, false)
 	}
 	// Irrelevant of the canonical status, write the block itself to the database
 	if err := self.hc.WriteTd(block.Hash(), externTd); err != nil {
@@ -1624,6 +1625,28 @@ func (self *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 		))
 	}
 
+	// Keep track of the blocks which lost their place in the canonical chain
+	reorg := &Reorg{
+		Time:         uint64(time.Now().Unix()),
+		CommonNumber: commonBlock.NumberU64(),
+		CommonHash:   commonHash,
+		OldNumber:    oldStart.NumberU64(),
+		OldHead:      oldStart.Hash(),
+		NewNumber:    newStart.NumberU64(),
+		NewHead:      newStart.Hash(),
+	}
+	for _, block := range oldChain {
+		self.writeSideBlock(block, self.GetTd(block.Hash()), true)
+		reorg.Dropped = append(reorg.Dropped, block.Hash())
+	}
+	for _, block := range newChain {
+		self.deleteSideBlock(block)
+		reorg.Added = append(reorg.Added, block.Hash())
+	}
+	if err := WriteReorg(self.chainDb, reorg); err != nil {
+		glog.V(logger.Error).Infof("failed to index reorg to #%d [%x…]: %v", reorg.NewNumber, reorg.NewHead[:4], err)
+	}
+
 	var addedTxs types.Transactions
 	// insert blocks. Order does not matter. Last block will be written in ImportChain itself which creates the new head properly
 	for _, block := range newChain {
@@ -1669,6 +1692,7 @@ func (self *BlockChain) reorg(oldBlock, newBlock *types.Block) error {
 			}
 		}()
 	}
+	go self.eventMux.Post(ChainReorgEvent{Common: commonBlock, Dropped: oldChain, Added: newChain})
 
 	return nil
 }
@@ -1176,6 +1176,117 @@ func TestLogReorgs(t *testing.T) {
 	}
 }
 
+// Tests that blocks losing against the canonical chain and reorganisations
+// are indexed.
+func TestSideBlockIndex(t *testing.T) {
+	db, err := ethdb.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := WriteGenesisBlock(db, DefaultConfigMorden.Genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc := chm(t, genesis, db)
+
+	first := makeBlockChainWithDiff(genesis, []int{1, 2, 4}, 11)
+	second := makeBlockChainWithDiff(genesis, []int{1, 2, 3, 4}, 22)
+	if _, err := bc.InsertChain(first); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := bc.InsertChain(second); err != nil {
+		t.Fatal(err)
+	}
+
+	// Blocks of the second chain were side blocks only until it became heavier
+	sides := bc.GetSideBlocks(0, 10)
+	if len(sides) != len(first) {
+		t.Fatalf("indexed %d side blocks, want %d", len(sides), len(first))
+	}
+	for i, side := range sides {
+		if side.Hash != first[i].Hash() || side.Number != first[i].NumberU64() {
+			t.Errorf("side block %d: have #%d [%x], want #%d [%x]", i, side.Number, side.Hash[:4], first[i].NumberU64(), first[i].Hash().Bytes()[:4])
+		}
+		if !side.Reorged {
+			t.Errorf("side block %d: not marked as reorganised", i)
+		}
+		if side.Miner != (common.Address{11}) {
+			t.Errorf("side block %d: miner mismatch: have %x", i, side.Miner)
+		}
+		if want := bc.GetTd(first[i].Hash()); side.Td.Cmp(want) != 0 {
+			t.Errorf("side block %d: td mismatch: have %v, want %v", i, side.Td, want)
+		}
+		if bc.GetUncleInclusion(side) != nil {
+			t.Errorf("side block %d: unexpected uncle inclusion", i)
+		}
+	}
+
+	reorgs := bc.GetReorgs(0, 10)
+	if len(reorgs) != 1 {
+		t.Fatalf("indexed %d reorgs, want 1", len(reorgs))
+	}
+	reorg := reorgs[0]
+	if reorg.CommonHash != genesis.Hash() || reorg.CommonNumber != 0 {
+		t.Errorf("common ancestor mismatch: have #%d [%x]", reorg.CommonNumber, reorg.CommonHash[:4])
+	}
+	if reorg.OldHead != first[2].Hash() || reorg.NewHead != second[3].Hash() || reorg.NewNumber != 4 {
+		t.Errorf("reorg heads mismatch: old %x, new #%d %x", reorg.OldHead[:4], reorg.NewNumber, reorg.NewHead[:4])
+	}
+	if len(reorg.Dropped) != len(first) || len(reorg.Added) != len(second) {
+		t.Errorf("reorg dropped %d and added %d blocks, want %d and %d", len(reorg.Dropped), len(reorg.Added), len(first), len(second))
+	}
+	if len(bc.GetReorgs(0, 3)) != 0 {
+		t.Error("reorg indexed at the wrong height")
+	}
+}
+
+// Tests that a reorganisation posts the dropped and added chain segments.
+func TestReorgEvent(t *testing.T) {
+	db, err := ethdb.NewMemDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	genesis, err := WriteGenesisBlock(db, DefaultConfigMorden.Genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bc := chm(t, genesis, db)
+	sub := bc.eventMux.Subscribe(ChainReorgEvent{})
+	defer sub.Unsubscribe()
+
+	first := makeBlockChainWithDiff(genesis, []int{1, 2, 4}, 11)
+	if _, err := bc.InsertChain(first); err != nil {
+		t.Fatal(err)
+	}
+	second := makeBlockChainWithDiff(genesis, []int{1, 2, 5}, 22)
+	if _, err := bc.InsertChain(second); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case ev := <-sub.Chan():
+		reorg := ev.Data.(ChainReorgEvent)
+		if reorg.Common.Hash() != genesis.Hash() {
+			t.Errorf("common ancestor mismatch: have #%v [%x]", reorg.Common.Number(), reorg.Common.Hash().Bytes()[:4])
+		}
+		if len(reorg.Dropped) != len(first) || len(reorg.Added) != len(second) {
+			t.Fatalf("dropped %d and added %d blocks, want %d and %d", len(reorg.Dropped), len(reorg.Added), len(first), len(second))
+		}
+		for i := range first {
+			// Segments are ordered from the highest block down
+			j := len(first) - 1 - i
+			if reorg.Dropped[i].Hash() != first[j].Hash() {
+				t.Errorf("dropped block %d: have %x, want %x", i, reorg.Dropped[i].Hash().Bytes()[:4], first[j].Hash().Bytes()[:4])
+			}
+			if reorg.Added[i].Hash() != second[j].Hash() {
+				t.Errorf("added block %d: have %x, want %x", i, reorg.Added[i].Hash().Bytes()[:4], second[j].Hash().Bytes()[:4])
+			}
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no reorg event posted")
+	}
+}
+
 func TestReorgSideEvent(t *testing.T) {
 	// This test itself is a little bit incorrect. Below,
 	// MakeDiehardChainConfig would make a chain configuration that
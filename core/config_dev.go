@@ -0,0 +1,78 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	hexlib "encoding/hex"
+	"math/big"
+
+	"github.com/ethereumproject/go-ethereum/common"
+)
+
+const (
+	// DevChainIdentity is the chain identity used by developer mode.
+	DevChainIdentity = "dev"
+	// DevNetworkId is the network id of developer mode chains.
+	DevNetworkId = 1337
+)
+
+// MakeDevChainConfig returns the configuration of a private developer chain.
+// It follows the rules of the Morden testnet with every fork active from the
+// genesis block on, and funds the given accounts in the genesis state.
+func MakeDevChainConfig(accounts ...GenesisAccount) *SufficientChainConfig {
+	morden := DefaultConfigMorden.ChainConfig
+
+	// Forks keep their order since features of later forks take precedence.
+	forks := make(Forks, len(morden.Forks))
+	for i, f := range morden.Forks {
+		forks[i] = &Fork{
+			Name:     f.Name,
+			Block:    new(big.Int),
+			Features: f.Features,
+		}
+	}
+	return &SufficientChainConfig{
+		Identity:    DevChainIdentity,
+		Name:        "Developer Chain",
+		State:       &StateConfig{},
+		Network:     DevNetworkId,
+		Consensus:   "ethash-test",
+		Genesis:     MakeDevGenesisDump(accounts...),
+		ChainConfig: &ChainConfig{Forks: forks},
+	}
+}
+
+// MakeDevGenesisDump returns a genesis for developer chains with a generous
+// gas limit, funding the precompiled contracts and the given accounts.
+func MakeDevGenesisDump(accounts ...GenesisAccount) *GenesisDump {
+	dump := &GenesisDump{
+		Nonce:      "0x0000000000000042",
+		GasLimit:   "0x47E7C4",
+		Difficulty: "0x020000",
+		Alloc:      make(map[hex]*GenesisDumpAlloc, len(accounts)+4),
+	}
+	for i := byte(1); i <= 4; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		dump.Alloc[hex(hexlib.EncodeToString(addr[:]))] = &GenesisDumpAlloc{Balance: "1"}
+	}
+	for _, a := range accounts {
+		dump.Alloc[hex(hexlib.EncodeToString(a.Address[:]))] = &GenesisDumpAlloc{
+			Balance: a.Balance.String(),
+		}
+	}
+	return dump
+}
@@ -4,6 +4,8 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/state"
 	"github.com/ethereumproject/go-ethereum/core/types"
 	"github.com/ethereumproject/go-ethereum/ethdb"
 	"path/filepath"
@@ -684,3 +686,37 @@ func TestSufficientChainConfig_IsValid(t *testing.T) {
 		}
 	}
 }
+
+func TestMakeDevChainConfig(t *testing.T) {
+	dev := GenesisAccount{Address: common.Address{0xde, 0xf}, Balance: new(big.Int).Mul(big.NewInt(1000), common.Ether)}
+	config := MakeDevChainConfig(dev)
+
+	if field, ok := config.IsValid(); !ok {
+		t.Fatalf("invalid dev chain config: %s", field)
+	}
+	for _, fork := range config.ChainConfig.Forks {
+		if fork.Block.Sign() != 0 {
+			t.Errorf("fork %q not active from genesis: %v", fork.Name, fork.Block)
+		}
+	}
+	if !config.ChainConfig.IsDiehard(big.NewInt(0)) {
+		t.Error("expected Diehard rules at genesis")
+	}
+	// The Morden configuration must not be modified
+	if DefaultConfigMorden.ChainConfig.ForkByName("Homestead").Block.Sign() == 0 {
+		t.Error("Morden fork blocks were changed")
+	}
+
+	db, _ := ethdb.NewMemDatabase()
+	genesis, err := WriteGenesisBlock(db, config.Genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb, err := state.New(genesis.Root(), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if balance := statedb.GetBalance(dev.Address); balance.Cmp(dev.Balance) != 0 {
+		t.Errorf("developer balance mismatch: have %v, want %v", balance, dev.Balance)
+	}
+}
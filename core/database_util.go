@@ -49,6 +49,9 @@ var (
 	mipmapPre    = []byte("mipmap-log-bloom-")
 	MIPMapLevels = []uint64{1000000, 500000, 100000, 50000, 1000}
 
+	sideBlocksPrefix = []byte("side-blocks-") // side-blocks-<num> -> non-canonical blocks at height
+	reorgsPrefix     = []byte("reorgs-")      // reorgs-<num> -> reorganisations to a head at height
+
 	blockHashPrefix = []byte("block-hash-") // [deprecated by the header/block split, remove eventually]
 )
 
@@ -519,6 +522,71 @@ func GetMipmapBloom(db ethdb.Database, number, level uint64) types.Bloom {
 	return types.BytesToBloom(bloomDat)
 }
 
+// heightKey returns prefix || number as uint64 big endian.
+func heightKey(prefix []byte, number uint64) []byte {
+	key := make([]byte, len(prefix)+8)
+	copy(key, prefix)
+	binary.BigEndian.PutUint64(key[len(prefix):], number)
+	return key
+}
+
+// GetSideBlocks retrieves the non-canonical blocks recorded at the given height.
+func GetSideBlocks(db ethdb.Database, number uint64) []*SideBlock {
+	data, _ := db.Get(heightKey(sideBlocksPrefix, number))
+	if len(data) == 0 {
+		return nil
+	}
+	var blocks []*SideBlock
+	if err := rlp.DecodeBytes(data, &blocks); err != nil {
+		glog.V(logger.Error).Infof("invalid side block list RLP at #%d: %v", number, err)
+		return nil
+	}
+	return blocks
+}
+
+// WriteSideBlocks stores the non-canonical blocks recorded at the given height.
+func WriteSideBlocks(db ethdb.Database, number uint64, blocks []*SideBlock) error {
+	key := heightKey(sideBlocksPrefix, number)
+	if len(blocks) == 0 {
+		return db.Delete(key)
+	}
+	data, err := rlp.EncodeToBytes(blocks)
+	if err != nil {
+		return err
+	}
+	if err := db.Put(key, data); err != nil {
+		glog.Fatalf("failed to store side blocks into database: %v", err)
+	}
+	return nil
+}
+
+// GetReorgs retrieves the reorganisations whose new head is at the given height.
+func GetReorgs(db ethdb.Database, number uint64) []*Reorg {
+	data, _ := db.Get(heightKey(reorgsPrefix, number))
+	if len(data) == 0 {
+		return nil
+	}
+	var reorgs []*Reorg
+	if err := rlp.DecodeBytes(data, &reorgs); err != nil {
+		glog.V(logger.Error).Infof("invalid reorg list RLP at #%d: %v", number, err)
+		return nil
+	}
+	return reorgs
+}
+
+// WriteReorg appends a reorganisation to those recorded at the height of its new head.
+func WriteReorg(db ethdb.Database, reorg *Reorg) error {
+	reorgs := append(GetReorgs(db, reorg.NewNumber), reorg)
+	data, err := rlp.EncodeToBytes(reorgs)
+	if err != nil {
+		return err
+	}
+	if err := db.Put(heightKey(reorgsPrefix, reorg.NewNumber), data); err != nil {
+		glog.Fatalf("failed to store reorg into database: %v", err)
+	}
+	return nil
+}
+
 // GetBlockChainVersion reads the version number from db.
 func GetBlockChainVersion(db ethdb.Database) int {
 	var vsn uint
@@ -62,6 +62,15 @@ type ChainEvent struct {
 	Logs  vm.Logs
 }
 
+// ChainReorgEvent is posted when the canonical chain is reorganised onto
+// another branch. Both block lists run from the highest block down to, but
+// excluding, the common ancestor.
+type ChainReorgEvent struct {
+	Common  *types.Block // common ancestor of both branches
+	Dropped types.Blocks // blocks removed from the canonical chain
+	Added   types.Blocks // blocks added to the canonical chain
+}
+
 type ChainSideEvent struct {
 	Block *types.Block
 	Logs  vm.Logs
@@ -0,0 +1,137 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package core
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+)
+
+// maxUncleDepth is the number of blocks after a side block in which it can be
+// included as an uncle.
+const maxUncleDepth = 7
+
+// SideBlock is an index entry of a block which lost against the canonical
+// chain, or was reorganised out of it.
+type SideBlock struct {
+	Number     uint64
+	Hash       common.Hash
+	ParentHash common.Hash
+	Td         *big.Int
+	Miner      common.Address
+	FirstSeen  uint64 // unix time the block was first written as non-canonical
+	Reorged    bool   // whether the block was canonical before a reorganisation
+}
+
+// Reorg is an index entry of a chain reorganisation.
+type Reorg struct {
+	Time         uint64 // unix time of the reorganisation
+	CommonNumber uint64
+	CommonHash   common.Hash
+	OldNumber    uint64
+	OldHead      common.Hash
+	NewNumber    uint64
+	NewHead      common.Hash
+	Dropped      []common.Hash // blocks removed from the canonical chain, highest first
+	Added        []common.Hash // blocks added to the canonical chain, highest first
+}
+
+// writeSideBlock adds the block to the index of non-canonical blocks. The
+// caller must hold self.mu.
+func (self *BlockChain) writeSideBlock(block *types.Block, td *big.Int, reorged bool) {
+	number, hash := block.NumberU64(), block.Hash()
+	blocks := GetSideBlocks(self.chainDb, number)
+	for _, side := range blocks {
+		if side.Hash == hash {
+			return
+		}
+	}
+	seen := block.ReceivedAt
+	if seen.IsZero() {
+		seen = time.Now()
+	}
+	blocks = append(blocks, &SideBlock{
+		Number:     number,
+		Hash:       hash,
+		ParentHash: block.ParentHash(),
+		Td:         td,
+		Miner:      block.Coinbase(),
+		FirstSeen:  uint64(seen.Unix()),
+		Reorged:    reorged,
+	})
+	if err := WriteSideBlocks(self.chainDb, number, blocks); err != nil {
+		glog.V(logger.Error).Infof("failed to index side block #%d [%x…]: %v", number, hash[:4], err)
+	}
+}
+
+// deleteSideBlock removes a block which became canonical from the index of
+// non-canonical blocks. The caller must hold self.mu.
+func (self *BlockChain) deleteSideBlock(block *types.Block) {
+	number, hash := block.NumberU64(), block.Hash()
+	blocks := GetSideBlocks(self.chainDb, number)
+	for i, side := range blocks {
+		if side.Hash == hash {
+			blocks = append(blocks[:i], blocks[i+1:]...)
+			if err := WriteSideBlocks(self.chainDb, number, blocks); err != nil {
+				glog.V(logger.Error).Infof("failed to unindex side block #%d [%x…]: %v", number, hash[:4], err)
+			}
+			return
+		}
+	}
+}
+
+// GetSideBlocks returns the non-canonical blocks seen between the heights from
+// and to, both inclusive.
+func (self *BlockChain) GetSideBlocks(from, to uint64) []*SideBlock {
+	var blocks []*SideBlock
+	for n := from; n <= to && n >= from; n++ {
+		blocks = append(blocks, GetSideBlocks(self.chainDb, n)...)
+	}
+	return blocks
+}
+
+// GetReorgs returns the chain reorganisations which led to a new head between
+// the heights from and to, both inclusive.
+func (self *BlockChain) GetReorgs(from, to uint64) []*Reorg {
+	var reorgs []*Reorg
+	for n := from; n <= to && n >= from; n++ {
+		reorgs = append(reorgs, GetReorgs(self.chainDb, n)...)
+	}
+	return reorgs
+}
+
+// GetUncleInclusion returns the canonical block which includes the given side
+// block as an uncle, or nil if there is none (yet).
+func (self *BlockChain) GetUncleInclusion(side *SideBlock) *types.Block {
+	for n := side.Number + 1; n <= side.Number+maxUncleDepth; n++ {
+		block := self.GetBlockByNumber(n)
+		if block == nil {
+			return nil
+		}
+		for _, uncle := range block.Uncles() {
+			if uncle.Hash() == side.Hash {
+				return block
+			}
+		}
+	}
+	return nil
+}
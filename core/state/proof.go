@@ -0,0 +1,77 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"fmt"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/trie"
+)
+
+// GetProof returns the merkle proof of the account at the given address in
+// the state trie. Modifications which are not committed yet are not covered
+// by the proof.
+func (self *StateDB) GetProof(addr common.Address) []rlp.RawValue {
+	return self.trie.Prove(addr[:])
+}
+
+// GetStorageProof returns the merkle proof of the given slot in the storage
+// trie of the account at the given address, or nil if the account doesn't
+// exist. Modifications which are not committed yet are not covered by the
+// proof.
+func (self *StateDB) GetStorageProof(addr common.Address, key common.Hash) []rlp.RawValue {
+	stateObject := self.GetStateObject(addr)
+	if stateObject == nil {
+		return nil
+	}
+	return stateObject.getTrie(self.db).Prove(key[:])
+}
+
+// VerifyProof checks the merkle proof of the account at the given address
+// against a state root, e.g. the one of a block header. It returns the proven
+// account, or nil if the proof shows that the account doesn't exist.
+func VerifyProof(root common.Hash, addr common.Address, proof []rlp.RawValue) (*Account, error) {
+	enc, err := trie.VerifyProof(root, crypto.Keccak256(addr[:]), proof)
+	if err != nil {
+		return nil, err
+	}
+	if enc == nil {
+		return nil, nil
+	}
+	account := new(Account)
+	if err := rlp.DecodeBytes(enc, account); err != nil {
+		return nil, fmt.Errorf("can't decode account %x: %v", addr, err)
+	}
+	return account, nil
+}
+
+// VerifyStorageProof checks the merkle proof of a storage slot against the
+// storage root of an account and returns the proven value of the slot.
+func VerifyStorageProof(root common.Hash, key common.Hash, proof []rlp.RawValue) (common.Hash, error) {
+	enc, err := trie.VerifyProof(root, crypto.Keccak256(key[:]), proof)
+	if err != nil || enc == nil {
+		return common.Hash{}, err
+	}
+	_, content, _, err := rlp.Split(enc)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("can't decode storage slot %x: %v", key, err)
+	}
+	return common.BytesToHash(content), nil
+}
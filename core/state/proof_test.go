@@ -0,0 +1,76 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/ethdb"
+)
+
+// Tests that account and storage proofs of a committed state verify against
+// its root and prove the absence of missing accounts and slots.
+func TestStateProofs(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	state, _ := New(common.Hash{}, db)
+	for i := byte(1); i < 64; i++ {
+		addr := common.BytesToAddress([]byte{i})
+		state.AddBalance(addr, big.NewInt(int64(i)))
+		state.SetNonce(addr, uint64(i))
+		state.SetState(addr, common.BytesToHash([]byte{i}), common.BytesToHash([]byte{i, i}))
+	}
+	root, err := state.Commit()
+	if err != nil {
+		t.Fatalf("failed to commit state: %v", err)
+	}
+	state, _ = New(root, db)
+
+	addr := common.BytesToAddress([]byte{42})
+	account, err := VerifyProof(root, addr, state.GetProof(addr))
+	if err != nil {
+		t.Fatalf("account proof failed: %v", err)
+	}
+	if account == nil || account.Balance.Cmp(big.NewInt(42)) != 0 || account.Nonce != 42 {
+		t.Fatalf("proven account mismatch: %+v", account)
+	}
+	if account.Root != state.GetStorageRoot(addr) {
+		t.Errorf("storage root mismatch: have %x, want %x", account.Root, state.GetStorageRoot(addr))
+	}
+	key := common.BytesToHash([]byte{42})
+	value, err := VerifyStorageProof(account.Root, key, state.GetStorageProof(addr, key))
+	if err != nil {
+		t.Fatalf("storage proof failed: %v", err)
+	}
+	if value != common.BytesToHash([]byte{42, 42}) {
+		t.Errorf("proven storage value mismatch: have %x", value)
+	}
+	missingKey := common.BytesToHash([]byte{43})
+	if value, err := VerifyStorageProof(account.Root, missingKey, state.GetStorageProof(addr, missingKey)); err != nil || value != (common.Hash{}) {
+		t.Errorf("missing slot proof mismatch: value %x, err %v", value, err)
+	}
+
+	missing := common.BytesToAddress([]byte{200})
+	if account, err := VerifyProof(root, missing, state.GetProof(missing)); err != nil || account != nil {
+		t.Errorf("missing account proof mismatch: account %+v, err %v", account, err)
+	}
+	// A proof must not verify against another root
+	if _, err := VerifyProof(common.Hash{1}, addr, state.GetProof(addr)); err == nil {
+		t.Error("proof verified against the wrong root")
+	}
+}
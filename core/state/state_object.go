@@ -186,6 +186,22 @@ func (self *StateObject) setState(key, value common.Hash) {
 	}
 }
 
+// setStorage discards the storage of the account, including its storage trie,
+// and replaces it with the given slots.
+func (self *StateObject) setStorage(storage Storage) {
+	self.trie = nil
+	self.data.Root = common.Hash{}
+	self.cachedStorage = make(Storage)
+	self.dirtyStorage = make(Storage)
+	for key, value := range storage {
+		self.setState(key, value)
+	}
+	if self.onDirty != nil {
+		self.onDirty(self.Address())
+		self.onDirty = nil
+	}
+}
+
 // updateTrie writes cached storage modifications into the object's storage trie.
 func (self *StateObject) updateTrie(db trie.Database) {
 	tr := self.getTrie(db)
@@ -273,6 +273,16 @@ func (self *StateDB) GetCodeHash(addr common.Address) common.Hash {
 	return common.BytesToHash(stateObject.CodeHash())
 }
 
+// GetStorageRoot returns the root hash of the storage trie of the account at
+// the given address, as of the last commit.
+func (self *StateDB) GetStorageRoot(addr common.Address) common.Hash {
+	stateObject := self.GetStateObject(addr)
+	if stateObject == nil {
+		return common.Hash{}
+	}
+	return stateObject.data.Root
+}
+
 func (self *StateDB) GetState(a common.Address, b common.Hash) common.Hash {
 	stateObject := self.GetStateObject(a)
 	if stateObject != nil {
@@ -328,6 +338,16 @@ func (self *StateDB) SetState(addr common.Address, key common.Hash, value common
 	}
 }
 
+// SetStorage replaces the entire storage of the account at the given address
+// with the given slots. The replacement isn't journalled, it is meant for
+// preparing copies of a state for simulations before any snapshot is taken.
+func (self *StateDB) SetStorage(addr common.Address, storage Storage) {
+	stateObject := self.GetOrNewStateObject(addr)
+	if stateObject != nil {
+		stateObject.setStorage(storage)
+	}
+}
+
 // Suicide marks the given account as suicided.
 // This clears the account balance.
 //
@@ -534,6 +554,16 @@ func (self *StateDB) GetRefund() *big.Int {
 	return self.refund
 }
 
+// DirtyAccounts returns the addresses of the accounts modified since the state
+// was created or last committed, in no particular order.
+func (self *StateDB) DirtyAccounts() []common.Address {
+	addrs := make([]common.Address, 0, len(self.stateObjectsDirty))
+	for addr := range self.stateObjectsDirty {
+		addrs = append(addrs, addr)
+	}
+	return addrs
+}
+
 // IntermediateRoot computes the current root hash of the state trie.
 // It is called in between transactions to get the root hash that
 // goes into transaction receipts.
@@ -552,6 +582,19 @@ func (s *StateDB) IntermediateRoot() common.Hash {
 	return s.trie.Hash()
 }
 
+// Finalise ends the current transaction like IntermediateRoot does, flagging
+// the suicided objects as deleted and resetting the journal and the refund
+// counter, but without writing the dirty objects into the account trie. It is
+// meant for executing transactions on copies sharing the trie of their origin.
+func (s *StateDB) Finalise() {
+	for addr := range s.stateObjectsDirty {
+		if stateObject := s.stateObjects[addr]; stateObject.suicided {
+			stateObject.deleted = true
+		}
+	}
+	s.clearJournalAndRefund()
+}
+
 // DeleteSuicides flags the suicided objects for deletion so that it
 // won't be referenced again when called / queried up on.
 //
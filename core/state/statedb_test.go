@@ -354,3 +354,34 @@ func (test *snapshotTest) checkEqual(state, checkstate *StateDB) error {
 	}
 	return nil
 }
+
+// Tests that replacing the storage of an account discards all the committed
+// slots and keeps only the new ones.
+func TestSetStorage(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	state, _ := New(common.Hash{}, db)
+
+	addr := common.BytesToAddress([]byte{1})
+	state.SetState(addr, common.BytesToHash([]byte{1}), common.BytesToHash([]byte{1}))
+	state.SetState(addr, common.BytesToHash([]byte{2}), common.BytesToHash([]byte{2}))
+	root, _ := state.Commit()
+
+	state, _ = New(root, db)
+	state.SetStorage(addr, Storage{common.BytesToHash([]byte{2}): common.BytesToHash([]byte{3})})
+
+	if value := state.GetState(addr, common.BytesToHash([]byte{1})); value != (common.Hash{}) {
+		t.Errorf("replaced slot 1 mismatch: have %x, want empty", value)
+	}
+	if value := state.GetState(addr, common.BytesToHash([]byte{2})); value != common.BytesToHash([]byte{3}) {
+		t.Errorf("replaced slot 2 mismatch: have %x, want 3", value)
+	}
+
+	// The replaced storage must hash to the same root as a fresh one
+	want, _ := New(common.Hash{}, db)
+	want.SetState(addr, common.BytesToHash([]byte{2}), common.BytesToHash([]byte{3}))
+	state.IntermediateRoot()
+	want.IntermediateRoot()
+	if have, want := state.GetStorageRoot(addr), want.GetStorageRoot(addr); have != want {
+		t.Errorf("storage root mismatch: have %x, want %x", have, want)
+	}
+}
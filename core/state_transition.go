@@ -60,6 +60,9 @@ type StateTransition struct {
 	state         vm.Database
 
 	env vm.Environment
+
+	// vmErr is the non-consensus error the EVM execution failed with
+	vmErr error
 }
 
 // Message represents a message sent to a contract.
@@ -134,6 +137,32 @@ func ApplyMessage(env vm.Environment, msg Message, gp *GasPool) ([]byte, *big.In
 	return ret, gasUsed, err
 }
 
+// ExecutionResult is the outcome of a message applied by SimulateMessage.
+type ExecutionResult struct {
+	ReturnData []byte   // bytes returned by the EVM execution, if it took place
+	UsedGas    *big.Int // gas used by the message, including gas refunds
+	Err        error    // error the EVM execution failed with, if any
+}
+
+// Failed returns whether the EVM execution of the message failed. Such a
+// failure isn't a core error, the message still consumes its gas.
+func (r *ExecutionResult) Failed() bool {
+	return r.Err != nil
+}
+
+// SimulateMessage is like ApplyMessage, but it additionally reports the error
+// the EVM execution of the message failed with in the result, which is why
+// ApplyMessage drops it. The error returned is always a core error.
+func SimulateMessage(env vm.Environment, msg Message, gp *GasPool) (*ExecutionResult, error) {
+	st := NewStateTransition(env, msg, gp)
+
+	ret, _, gasUsed, err := st.TransitionDb()
+	if err != nil {
+		return nil, err
+	}
+	return &ExecutionResult{ReturnData: ret, UsedGas: gasUsed, Err: st.vmErr}, nil
+}
+
 func (self *StateTransition) from() (vm.Account, error) {
 	var (
 		f   common.Address
@@ -262,6 +291,7 @@ func (self *StateTransition) TransitionDb() (ret []byte, requiredGas, usedGas *b
 
 	// We aren't interested in errors here. Errors returned by the VM are non-consensus errors and therefor shouldn't bubble up
 	if err != nil {
+		self.vmErr = err
 		err = nil
 	}
 
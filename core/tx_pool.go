@@ -221,75 +221,110 @@ func (pool *TxPool) SetLocal(tx *types.Transaction) {
 	pool.localTx.add(tx.Hash())
 }
 
+// TxCheckError is a check a transaction failed to enter the pool, Err is one of
+// the pool errors. Have and Want are the compared values of the transaction and
+// of the pool, if any.
+type TxCheckError struct {
+	Err        error
+	Have, Want *big.Int
+}
+
+func (e *TxCheckError) Error() string {
+	if e.Have == nil {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%v: have %v, want %v", e.Err, e.Have, e.Want)
+}
+
+// Validate runs the checks of a transaction entering the pool without adding
+// it, and returns all the failed checks instead of the first. Local
+// transactions skip the gas price check, as those marked with SetLocal.
+func (pool *TxPool) Validate(tx *types.Transaction, local bool) ([]*TxCheckError, error) {
+	pool.mu.RLock()
+	defer pool.mu.RUnlock()
+	return pool.checkTx(tx, local, true)
+}
+
 // validateTx checks whether a transaction is valid according
 // to the consensus rules.
 func (pool *TxPool) validateTx(tx *types.Transaction) (e error) {
-	local := pool.localTx.contains(tx.Hash())
 	defer func() {
 		mlogTxPool.Send(mlogTxPoolValidateTx.SetDetailValues(
 			tx.Hash().Hex(),
 			e,
 		))
 	}()
+	errs, err := pool.checkTx(tx, pool.localTx.contains(tx.Hash()), false)
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs[0].Err
+	}
+	return nil
+}
+
+// checkTx runs the checks of validateTx, stopping at the first failed one
+// unless all is set. Local transactions skip the gas price check. Err is set if
+// the state isn't available.
+func (pool *TxPool) checkTx(tx *types.Transaction, local, all bool) (errs []*TxCheckError, err error) {
+	// fail records a failed check and reports whether to stop
+	fail := func(err error, have, want *big.Int) bool {
+		errs = append(errs, &TxCheckError{Err: err, Have: have, Want: want})
+		return !all
+	}
+
 	// Drop transactions under our own minimal accepted gas price
-	if !local && pool.minGasPrice.Cmp(tx.GasPrice()) > 0 {
-		e = ErrCheap
-		return
+	if !local && pool.minGasPrice.Cmp(tx.GasPrice()) > 0 && fail(ErrCheap, tx.GasPrice(), pool.minGasPrice) {
+		return errs, nil
 	}
 
 	currentState, err := pool.currentState()
 	if err != nil {
-		e = err
-		return
+		return nil, err
 	}
 
 	from, err := types.Sender(pool.signer, tx)
 	if err != nil {
-		e = ErrInvalidSender
-		return
+		fail(ErrInvalidSender, nil, nil)
+		return errs, nil
 	}
 
 	// Make sure the account exist. Non existent accounts
 	// haven't got funds and well therefor never pass.
-	if !currentState.Exist(from) {
-		e = ErrNonExistentAccount
-		return
+	if !currentState.Exist(from) && fail(ErrNonExistentAccount, nil, nil) {
+		return errs, nil
 	}
 
 	// Last but not least check for nonce errors
-	if currentState.GetNonce(from) > tx.Nonce() {
-		e = ErrNonce
-		return
+	if nonce := currentState.GetNonce(from); nonce > tx.Nonce() && fail(ErrNonce, new(big.Int).SetUint64(tx.Nonce()), new(big.Int).SetUint64(nonce)) {
+		return errs, nil
 	}
 
 	// Check the transaction doesn't exceed the current
 	// block limit gas.
-	if pool.gasLimit().Cmp(tx.Gas()) < 0 {
-		e = ErrGasLimit
-		return
+	if gasLimit := pool.gasLimit(); gasLimit.Cmp(tx.Gas()) < 0 && fail(ErrGasLimit, tx.Gas(), gasLimit) {
+		return errs, nil
 	}
 
 	// Transactions can't be negative. This may never happen
 	// using RLP decoded transactions but may occur if you create
 	// a transaction using the RPC for example.
-	if tx.Value().Sign() < 0 {
-		e = ErrNegativeValue
-		return
+	if tx.Value().Sign() < 0 && fail(ErrNegativeValue, nil, nil) {
+		return errs, nil
 	}
 
 	// Transactor should have enough funds to cover the costs
 	// cost == V + GP * GL
-	if currentState.GetBalance(from).Cmp(tx.Cost()) < 0 {
-		e = ErrInsufficientFunds
-		return
+	if balance := currentState.GetBalance(from); balance.Cmp(tx.Cost()) < 0 && fail(ErrInsufficientFunds, balance, tx.Cost()) {
+		return errs, nil
 	}
 
 	intrGas := IntrinsicGas(tx.Data(), MessageCreatesContract(tx), pool.homestead)
 	if tx.Gas().Cmp(intrGas) < 0 {
-		e = ErrIntrinsicGas
-		return
+		fail(ErrIntrinsicGas, tx.Gas(), intrGas)
 	}
-	return // e=nil
+	return errs, nil
 }
 
 // validate and queue transactions.
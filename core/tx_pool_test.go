@@ -89,6 +89,50 @@ func TestInvalidTransactions(t *testing.T) {
 	}
 }
 
+func TestValidate(t *testing.T) {
+	pool, key := setupTxPool()
+	pool.minGasPrice = big.NewInt(1000)
+
+	tx := transaction(0, big.NewInt(2000000), key)
+	from, _ := deriveSender(tx)
+	currentState, _ := pool.currentState()
+	currentState.AddBalance(from, big.NewInt(1))
+	currentState.SetNonce(from, 1)
+
+	errs, err := pool.Validate(tx, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*TxCheckError{
+		{ErrCheap, big.NewInt(1), big.NewInt(1000)},
+		{ErrNonce, big.NewInt(0), big.NewInt(1)},
+		{ErrGasLimit, big.NewInt(2000000), big.NewInt(1000000)},
+		{ErrInsufficientFunds, big.NewInt(1), big.NewInt(2000100)},
+	}
+	if len(errs) != len(want) {
+		t.Fatalf("have %d failed checks %v, want %d", len(errs), errs, len(want))
+	}
+	for i := range want {
+		if errs[i].Err != want[i].Err || errs[i].Have.Cmp(want[i].Have) != 0 || errs[i].Want.Cmp(want[i].Want) != 0 {
+			t.Errorf("check %d: have %v, want %v", i, errs[i], want[i])
+		}
+	}
+	if len(pool.pending) != 0 || len(pool.queue) != 0 {
+		t.Error("validated transaction added to the pool")
+	}
+
+	// The failed checks are those of Add
+	if err := pool.Add(tx); err != ErrCheap {
+		t.Error("expected", ErrCheap, "got", err)
+	}
+	if errs, _ = pool.Validate(tx, true); len(errs) != 3 || errs[0].Err != ErrNonce {
+		t.Errorf("local transaction: have failed checks %v, want 3 from %v", errs, ErrNonce)
+	}
+	if pool.localTx.contains(tx.Hash()) {
+		t.Error("validated transaction marked local")
+	}
+}
+
 func TestTransactionQueue(t *testing.T) {
 	pool, key := setupTxPool()
 	tx := transaction(0, big.NewInt(100), key)
@@ -27,6 +27,7 @@ import (
 	"math/big"
 	"os"
 	"runtime"
+	"strings"
 	"sync"
 	"time"
 
@@ -275,6 +276,146 @@ func (s *PrivateMinerAPI) SetGasPrice(gasPrice rpc.HexNumber) bool {
 	return true
 }
 
+// TxPolicy describes the transaction ordering policy used by the miner.
+type TxPolicy struct {
+	Name      string           `json:"name"`
+	Whitelist []common.Address `json:"whitelist,omitempty"`
+	Available []string         `json:"available"`
+}
+
+// TxPolicy returns the transaction ordering policy used when assembling blocks.
+func (s *PrivateMinerAPI) TxPolicy() *TxPolicy {
+	selector := s.e.Miner().TransactionSelector()
+	policy := &TxPolicy{Name: selector.Name(), Available: miner.SelectorNames}
+	if wl, ok := selector.(*miner.WhitelistSelector); ok {
+		policy.Whitelist = wl.Senders()
+	}
+	return policy
+}
+
+// SetTxPolicy sets the transaction ordering policy used when assembling blocks.
+// The whitelist is only used by the "whitelist" policy.
+func (s *PrivateMinerAPI) SetTxPolicy(name string, whitelist *[]common.Address) (bool, error) {
+	var senders []common.Address
+	if whitelist != nil {
+		senders = *whitelist
+	}
+	selector, err := miner.NewTransactionSelector(name, senders)
+	if err != nil {
+		return false, err
+	}
+	s.e.Miner().SetTransactionSelector(selector)
+	return true, nil
+}
+
+// BuildBlockArgs selects the parent and the transactions of a simulated block.
+// Without a parent the block is built on top of the current head, without
+// transactions it is filled from the transaction pool.
+type BuildBlockArgs struct {
+	Parent       *rpc.BlockNumber `json:"parent"`
+	ParentHash   *common.Hash     `json:"parentHash"`
+	Transactions *[]string        `json:"transactions"` // RLP encoded signed transactions
+}
+
+// BuildBlock assembles the block this node would currently mine, without
+// sealing it or starting the miner, and returns it together with the
+// receipts, gas used, fees and resulting state root.
+func (s *PrivateMinerAPI) BuildBlock(args *BuildBlockArgs) (map[string]interface{}, error) {
+	if args == nil {
+		args = new(BuildBlockArgs)
+	}
+	bc := s.e.BlockChain()
+
+	parent := bc.CurrentBlock()
+	switch {
+	case args.ParentHash != nil:
+		parent = bc.GetBlock(*args.ParentHash)
+	case args.Parent != nil && *args.Parent != rpc.LatestBlockNumber:
+		if *args.Parent == rpc.PendingBlockNumber {
+			return nil, errors.New("cannot build on top of the pending block")
+		}
+		parent = bc.GetBlockByNumber(uint64(args.Parent.Int64()))
+	}
+	if parent == nil {
+		return nil, errors.New("parent block not found")
+	}
+
+	var txs types.Transactions
+	if args.Transactions != nil {
+		txs = make(types.Transactions, 0, len(*args.Transactions))
+		for i, encoded := range *args.Transactions {
+			tx := new(types.Transaction)
+			if err := rlp.DecodeBytes(common.FromHex(encoded), tx); err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+			txs = append(txs, tx)
+		}
+	}
+
+	result, err := s.e.Miner().BuildBlock(parent, txs)
+	if err != nil {
+		return nil, err
+	}
+	block := result.Block
+
+	transactions := make([]common.Hash, len(block.Transactions()))
+	for i, tx := range block.Transactions() {
+		transactions[i] = tx.Hash()
+	}
+	uncles := make([]common.Hash, len(block.Uncles()))
+	for i, uncle := range block.Uncles() {
+		uncles[i] = uncle.Hash()
+	}
+	receipts := make([]map[string]interface{}, len(result.Receipts))
+	for i, receipt := range result.Receipts {
+		tx := block.Transactions()[i]
+		from, _ := tx.From()
+		fields := map[string]interface{}{
+			"transactionHash":   tx.Hash(),
+			"transactionIndex":  rpc.NewHexNumber(i),
+			"from":              from,
+			"to":                tx.To(),
+			"gasUsed":           rpc.NewHexNumber(receipt.GasUsed),
+			"cumulativeGasUsed": rpc.NewHexNumber(receipt.CumulativeGasUsed),
+			"contractAddress":   nil,
+			"logs":              receipt.Logs,
+		}
+		if receipt.Logs == nil {
+			fields["logs"] = []vm.Logs{}
+		}
+		if tx.To() == nil {
+			fields["contractAddress"] = receipt.ContractAddress
+		}
+		receipts[i] = fields
+	}
+	skipped := make([]common.Hash, len(result.Skipped))
+	for i, tx := range result.Skipped {
+		skipped[i] = tx.Hash()
+	}
+
+	return map[string]interface{}{
+		"number":           rpc.NewHexNumber(block.Number()),
+		"parentHash":       block.ParentHash(),
+		"sha3Uncles":       block.UncleHash(),
+		"logsBloom":        block.Bloom(),
+		"stateRoot":        block.Root(),
+		"miner":            block.Coinbase(),
+		"difficulty":       rpc.NewHexNumber(block.Difficulty()),
+		"extraData":        fmt.Sprintf("0x%x", block.Extra()),
+		"size":             rpc.NewHexNumber(block.Size().Int64()),
+		"gasLimit":         rpc.NewHexNumber(block.GasLimit()),
+		"gasUsed":          rpc.NewHexNumber(block.GasUsed()),
+		"timestamp":        rpc.NewHexNumber(block.Time()),
+		"transactionsRoot": block.TxHash(),
+		"receiptsRoot":     block.ReceiptHash(),
+		"transactions":     transactions,
+		"uncles":           uncles,
+		"receipts":         receipts,
+		"fees":             rpc.NewHexNumber(result.Fees),
+		"skipped":          skipped,
+	}, nil
+}
+
 // SetEtherbase sets the etherbase of the miner
 func (s *PrivateMinerAPI) SetEtherbase(etherbase common.Address) bool {
 	s.e.SetEtherbase(etherbase)
@@ -354,6 +495,74 @@ func (s *PublicTxPoolAPI) Status() map[string]*rpc.HexNumber {
 	}
 }
 
+// TxValidation is the outcome of validating a raw transaction against the pool.
+type TxValidation struct {
+	Transaction *RPCTransaction     `json:"transaction"`
+	Valid       bool                `json:"valid"`
+	Errors      []TxValidationError `json:"errors"`
+}
+
+// TxValidationError is a check a transaction failed to enter the pool. Have and
+// Want are the compared values of the transaction and of the pool, if any.
+type TxValidationError struct {
+	Reason  string         `json:"reason"`
+	Message string         `json:"message"`
+	Have    *rpc.HexNumber `json:"have,omitempty"`
+	Want    *rpc.HexNumber `json:"want,omitempty"`
+}
+
+// txValidationReasons are the reasons of the transaction pool errors.
+var txValidationReasons = map[error]string{
+	core.ErrInvalidSender:      "invalidSender",
+	core.ErrNonce:              "nonceTooLow",
+	core.ErrCheap:              "gasPriceTooLow",
+	core.ErrNonExistentAccount: "unknownAccount",
+	core.ErrInsufficientFunds:  "insufficientFunds",
+	core.ErrIntrinsicGas:       "intrinsicGasTooLow",
+	core.ErrGasLimit:           "gasLimitExceeded",
+	core.ErrNegativeValue:      "negativeValue",
+}
+
+// Validate decodes a signed transaction and runs the checks of the transaction
+// pool against it, without adding it to the pool. All failed checks are
+// reported. The transaction is checked as a local one, like those sent with
+// eth_sendRawTransaction, so the gas price isn't checked.
+func (s *PublicTxPoolAPI) Validate(encodedTx string) (*TxValidation, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(encodedTx), tx); err != nil {
+		return nil, err
+	}
+	result := &TxValidation{Errors: []TxValidationError{}}
+	rpcTx, err := newRPCRawTransaction(s.e.BlockChain(), tx)
+	if err != nil {
+		rpcTx = newRPCPendingTransaction(tx)
+		rpcTx.From = common.Address{}
+		result.Errors = append(result.Errors, TxValidationError{
+			Reason:  txValidationReasons[core.ErrInvalidSender],
+			Message: err.Error(),
+		})
+	}
+	result.Transaction = rpcTx
+
+	errs, err := s.e.TxPool().Validate(tx, true)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range errs {
+		if e.Err == core.ErrInvalidSender && len(result.Errors) > 0 {
+			continue // already reported
+		}
+		result.Errors = append(result.Errors, TxValidationError{
+			Reason:  txValidationReasons[e.Err],
+			Message: e.Error(),
+			Have:    rpc.NewHexNumber(e.Have),
+			Want:    rpc.NewHexNumber(e.Want),
+		})
+	}
+	result.Valid = len(result.Errors) == 0
+	return result, nil
+}
+
 // Inspect retrieves the content of the transaction pool and flattens it into an
 // easily inspectable list.
 func (s *PublicTxPoolAPI) Inspect() map[string]map[string]map[string][]string {
@@ -464,6 +673,77 @@ func (s *PrivateAccountAPI) ImportRawKey(privkey string, password string) (commo
 	return acc.Address, err
 }
 
+// HDWalletResult is a new HD wallet, returned with the mnemonic to be written
+// down as its backup.
+type HDWalletResult struct {
+	Mnemonic string         `json:"mnemonic"`
+	Address  common.Address `json:"address"`
+}
+
+// HDWalletInfo describes a HD wallet and its derived accounts.
+type HDWalletInfo struct {
+	ID       string           `json:"id"`
+	File     string           `json:"file"`
+	Path     string           `json:"path"`
+	Accounts []common.Address `json:"accounts"`
+}
+
+// hdPath parses an optional BIP-44 root derivation path, defaulting to the
+// Ethereum Classic path m/44'/61'/0'/0.
+func hdPath(path *string) (accounts.DerivationPath, error) {
+	if path == nil {
+		return accounts.DefaultRootDerivationPath, nil
+	}
+	return accounts.ParseDerivationPath(*path)
+}
+
+// NewHDWallet creates a HD wallet from a new random mnemonic, encrypted with
+// the given password, and returns the mnemonic and the first account derived
+// along the optional root path.
+func (s *PrivateAccountAPI) NewHDWallet(password string, path *string) (*HDWalletResult, error) {
+	root, err := hdPath(path)
+	if err != nil {
+		return nil, err
+	}
+	mnemonic, acc, err := s.am.NewHDWallet(password, root)
+	if err != nil {
+		return nil, err
+	}
+	return &HDWalletResult{Mnemonic: mnemonic, Address: acc.Address}, nil
+}
+
+// ImportMnemonic stores a HD wallet for the given BIP-39 mnemonic, encrypted
+// with the given password, and returns the first account derived along the
+// optional root path.
+func (s *PrivateAccountAPI) ImportMnemonic(mnemonic string, password string, path *string) (common.Address, error) {
+	root, err := hdPath(path)
+	if err != nil {
+		return common.Address{}, err
+	}
+	acc, err := s.am.ImportHDWallet(mnemonic, password, root)
+	return acc.Address, err
+}
+
+// DeriveAccount derives the next account of the HD wallet holding the given
+// account.
+func (s *PrivateAccountAPI) DeriveAccount(addr common.Address, password string) (common.Address, error) {
+	acc, err := s.am.DeriveHDAccount(accounts.Account{Address: addr}, password)
+	return acc.Address, err
+}
+
+// ListHDWallets returns the HD wallets and their derived accounts.
+func (s *PrivateAccountAPI) ListHDWallets() []HDWalletInfo {
+	wallets := []HDWalletInfo{}
+	for _, w := range s.am.HDWallets() {
+		info := HDWalletInfo{ID: w.ID, File: w.File, Path: w.Path.String(), Accounts: []common.Address{}}
+		for _, acc := range w.Accounts {
+			info.Accounts = append(info.Accounts, acc.Address)
+		}
+		wallets = append(wallets, info)
+	}
+	return wallets
+}
+
 // UnlockAccount will unlock the account associated with the given address with
 // the given password for duration seconds. If duration is nil it will use a
 // default of 300 seconds. It returns an indication if the account was unlocked.
@@ -504,14 +784,83 @@ func (s *PrivateAccountAPI) SignAndSendTransaction(args SendTxArgs, passwd strin
 		tx = types.NewTransaction(args.Nonce.Uint64(), *args.To, args.Value.BigInt(), args.Gas.BigInt(), args.GasPrice.BigInt(), common.FromHex(args.Data))
 	}
 
-	tx.SetSigner(s.bc.Config().GetSigner(s.bc.CurrentBlock().Number()))
-
-	signature, err := s.am.SignWithPassphrase(args.From, passwd, tx.SigHash().Bytes())
+	signer := s.bc.Config().GetSigner(s.bc.CurrentBlock().Number())
+	signedTx, err := s.am.SignTxWithPassphrase(args.From, passwd, tx, signer)
 	if err != nil {
 		return common.Hash{}, err
 	}
 
-	return submitTransaction(s.bc, s.txPool, tx, signature)
+	return submitTransaction(s.txPool, signedTx)
+}
+
+// Sign calculates an Ethereum ECDSA signature for:
+// keccak256("\x19Ethereum Signed Message:\n" + len(message) + message))
+//
+// The prefix makes the signed data distinguishable from a transaction, so a
+// signed message can't be abused to authorize one. The key of the account is
+// decrypted with the given passphrase for the signing only. The returned
+// signature has a recovery id (V) of 27 or 28, it can be checked with EcRecover.
+func (s *PrivateAccountAPI) Sign(data string, addr common.Address, passwd string) (string, error) {
+	message, err := decodeHexArg("data", data)
+	if err != nil {
+		return "", err
+	}
+	return s.signHash(addr, passwd, accounts.TextHash(message))
+}
+
+// EcRecover returns the address of the account that created the given
+// signature of the message with Sign.
+func (s *PrivateAccountAPI) EcRecover(data string, sig string) (common.Address, error) {
+	message, err := decodeHexArg("data", data)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signature, err := decodeHexArg("sig", sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(signature) != 65 {
+		return common.Address{}, fmt.Errorf("signature must be 65 bytes long")
+	}
+	if signature[64] != 27 && signature[64] != 28 {
+		return common.Address{}, fmt.Errorf("invalid Ethereum signature (V is not 27 or 28)")
+	}
+	signature[64] -= 27
+
+	pub, err := crypto.SigToPub(accounts.TextHash(message), signature)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// SignTypedData calculates an Ethereum ECDSA signature of EIP-712 structured
+// data. The domain of the data must include the chain id of the node's chain,
+// so a signature can't be replayed on another chain.
+func (s *PrivateAccountAPI) SignTypedData(addr common.Address, data accounts.TypedData, passwd string) (string, error) {
+	chainID, err := data.ChainID()
+	if err != nil {
+		return "", fmt.Errorf("domain: %v", err)
+	}
+	if want := s.bc.Config().GetChainID(); chainID == nil || chainID.Cmp(want) != 0 {
+		return "", fmt.Errorf("domain chain id %v doesn't match chain id %v", chainID, want)
+	}
+	hash, err := data.Hash()
+	if err != nil {
+		return "", err
+	}
+	return s.signHash(addr, passwd, hash)
+}
+
+// signHash signs the hash with the key of the given account, returning the
+// signature with a recovery id of 27 or 28.
+func (s *PrivateAccountAPI) signHash(addr common.Address, passwd string, hash []byte) (string, error) {
+	signature, err := s.am.SignWithPassphrase(addr, passwd, hash)
+	if err != nil {
+		return "", err
+	}
+	signature[64] += 27
+	return common.ToHex(signature), nil
 }
 
 // PublicBlockChainAPI provides an API to access the Ethereum blockchain.
@@ -523,6 +872,8 @@ type PublicBlockChainAPI struct {
 	eventMux                *event.TypeMux
 	muNewBlockSubscriptions sync.Mutex                             // protects newBlocksSubscriptions
 	newBlockSubscriptions   map[string]func(core.ChainEvent) error // callbacks for new block subscriptions
+	muReorgSubscriptions    sync.Mutex                             // protects reorgSubscriptions
+	reorgSubscriptions      map[string]rpc.Subscription            // chain reorganisation subscriptions
 	am                      *accounts.Manager
 	miner                   *miner.Miner
 	gpo                     *GasPriceOracle
@@ -538,6 +889,7 @@ func NewPublicBlockChainAPI(config *core.ChainConfig, bc *core.BlockChain, m *mi
 		eventMux: eventMux,
 		am:       am,
 		newBlockSubscriptions: make(map[string]func(core.ChainEvent) error),
+		reorgSubscriptions:    make(map[string]rpc.Subscription),
 		gpo: gpo,
 	}
 
@@ -548,16 +900,28 @@ func NewPublicBlockChainAPI(config *core.ChainConfig, bc *core.BlockChain, m *mi
 
 // subscriptionLoop reads events from the global event mux and creates notifications for the matched subscriptions.
 func (s *PublicBlockChainAPI) subscriptionLoop() {
-	sub := s.eventMux.Subscribe(core.ChainEvent{})
+	sub := s.eventMux.Subscribe(core.ChainEvent{}, core.ChainReorgEvent{})
 	for event := range sub.Chan() {
-		if chainEvent, ok := event.Data.(core.ChainEvent); ok {
+		switch ev := event.Data.(type) {
+		case core.ChainEvent:
 			s.muNewBlockSubscriptions.Lock()
 			for id, notifyOf := range s.newBlockSubscriptions {
-				if notifyOf(chainEvent) == rpc.ErrNotificationNotFound {
+				if notifyOf(ev) == rpc.ErrNotificationNotFound {
 					delete(s.newBlockSubscriptions, id)
 				}
 			}
 			s.muNewBlockSubscriptions.Unlock()
+		case core.ChainReorgEvent:
+			s.muReorgSubscriptions.Lock()
+			if len(s.reorgSubscriptions) > 0 {
+				notification := rpcOutputReorg(ev)
+				for id, sub := range s.reorgSubscriptions {
+					if sub.Notify(notification) == rpc.ErrNotificationNotFound {
+						delete(s.reorgSubscriptions, id)
+					}
+				}
+			}
+			s.muReorgSubscriptions.Unlock()
 		}
 	}
 }
@@ -688,6 +1052,60 @@ func (s *PublicBlockChainAPI) NewBlocks(ctx context.Context, args NewBlocksArgs)
 	return subscription, nil
 }
 
+// Reorgs creates a subscription that fires each time the canonical chain is reorganised. Notifications
+// hold the common ancestor and the blocks dropped from and added to the canonical chain, highest first.
+func (s *PublicBlockChainAPI) Reorgs(ctx context.Context) (rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+
+	subscription, err := notifier.NewSubscription(func(id string) {
+		s.muReorgSubscriptions.Lock()
+		delete(s.reorgSubscriptions, id)
+		s.muReorgSubscriptions.Unlock()
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	s.muReorgSubscriptions.Lock()
+	s.reorgSubscriptions[subscription.ID()] = subscription
+	s.muReorgSubscriptions.Unlock()
+
+	return subscription, nil
+}
+
+// rpcOutputReorg converts a chain reorganisation into the RPC notification format.
+func rpcOutputReorg(e core.ChainReorgEvent) map[string]interface{} {
+	segment := func(blocks types.Blocks) []map[string]interface{} {
+		fields := make([]map[string]interface{}, len(blocks))
+		for i, block := range blocks {
+			txs := make([]common.Hash, len(block.Transactions()))
+			for j, tx := range block.Transactions() {
+				txs[j] = tx.Hash()
+			}
+			fields[i] = map[string]interface{}{
+				"number":       rpc.NewHexNumber(block.Number()),
+				"hash":         block.Hash(),
+				"parentHash":   block.ParentHash(),
+				"transactions": txs,
+			}
+		}
+		return fields
+	}
+	return map[string]interface{}{
+		"commonAncestor": map[string]interface{}{
+			"number": rpc.NewHexNumber(e.Common.Number()),
+			"hash":   e.Common.Hash(),
+		},
+		"depth":   len(e.Dropped),
+		"dropped": segment(e.Dropped),
+		"added":   segment(e.Added),
+	}
+}
+
 // GetCode returns the code stored at the given address in the state for the given block number.
 func (s *PublicBlockChainAPI) GetCode(address common.Address, blockNr rpc.BlockNumber) (string, error) {
 	state, _, err := stateAndBlockByNumber(s.miner, s.bc, blockNr, s.chainDb)
@@ -712,6 +1130,67 @@ func (s *PublicBlockChainAPI) GetStorageAt(address common.Address, key string, b
 	return state.GetState(address, common.HexToHash(key)).Hex(), nil
 }
 
+// AccountResult is the merkle proof of an account and of some of its storage
+// slots, as returned by eth_getProof.
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []string        `json:"accountProof"`
+	Balance      *rpc.HexNumber  `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        *rpc.HexNumber  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+// StorageResult is the merkle proof of a storage slot of an account.
+type StorageResult struct {
+	Key   common.Hash `json:"key"`
+	Value common.Hash `json:"value"`
+	Proof []string    `json:"proof"`
+}
+
+// GetProof returns the merkle proof of the account at the given address in
+// the state trie of the given block, along with the proofs of the given slots
+// in the storage trie of the account. The proofs can be verified against the
+// state root of the block header without trusting the node, see
+// state.VerifyProof. Proofs of the pending block are not available.
+func (s *PublicBlockChainAPI) GetProof(address common.Address, storageKeys []string, blockNr rpc.BlockNumber) (*AccountResult, error) {
+	if blockNr == rpc.PendingBlockNumber {
+		return nil, errors.New("proofs are not available for the pending block")
+	}
+	state, _, err := stateAndBlockByNumber(s.miner, s.bc, blockNr, s.chainDb)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	storageProof := make([]StorageResult, len(storageKeys))
+	for i, key := range storageKeys {
+		slot := common.HexToHash(key)
+		storageProof[i] = StorageResult{
+			Key:   slot,
+			Value: state.GetState(address, slot),
+			Proof: encodeProof(state.GetStorageProof(address, slot)),
+		}
+	}
+	return &AccountResult{
+		Address:      address,
+		AccountProof: encodeProof(state.GetProof(address)),
+		Balance:      rpc.NewHexNumber(state.GetBalance(address)),
+		CodeHash:     state.GetCodeHash(address),
+		Nonce:        rpc.NewHexNumber(state.GetNonce(address)),
+		StorageHash:  state.GetStorageRoot(address),
+		StorageProof: storageProof,
+	}, nil
+}
+
+// encodeProof encodes the nodes of a merkle proof as hex strings.
+func encodeProof(proof []rlp.RawValue) []string {
+	nodes := make([]string, len(proof))
+	for i, node := range proof {
+		nodes[i] = common.ToHex(node)
+	}
+	return nodes
+}
+
 // callmsg is the message type used for call transactions.
 type callmsg struct {
 	from          *state.StateObject
@@ -741,7 +1220,130 @@ type CallArgs struct {
 	Data     string          `json:"data"`
 }
 
-func (s *PublicBlockChainAPI) doCall(args CallArgs, blockNr rpc.BlockNumber) (string, *big.Int, error) {
+// OverrideAccount holds the fields of an account that are replaced before a call
+// is executed. State replaces the entire storage of the account, while
+// StateDiff only replaces the given slots; at most one of them can be set.
+type OverrideAccount struct {
+	Nonce     *rpc.HexNumber          `json:"nonce"`
+	Code      *string                 `json:"code"`
+	Balance   *rpc.HexNumber          `json:"balance"`
+	State     *map[string]common.Hash `json:"state"`
+	StateDiff *map[string]common.Hash `json:"stateDiff"`
+}
+
+// StateOverride is the collection of overridden accounts, keyed by address.
+type StateOverride map[string]OverrideAccount
+
+// Apply overrides the fields of the specified accounts in the given state.
+func (diff *StateOverride) Apply(stateDb *state.StateDB) error {
+	if diff == nil {
+		return nil
+	}
+	for hexAddr, account := range *diff {
+		if !common.IsHexAddress(hexAddr) {
+			return fmt.Errorf("invalid override address %q", hexAddr)
+		}
+		addr := common.HexToAddress(hexAddr)
+
+		if account.Nonce != nil {
+			stateDb.SetNonce(addr, account.Nonce.Uint64())
+		}
+		if account.Code != nil {
+			stateDb.SetCode(addr, common.FromHex(*account.Code))
+		}
+		if account.Balance != nil {
+			stateDb.SetBalance(addr, account.Balance.BigInt())
+		}
+		if account.State != nil && account.StateDiff != nil {
+			return fmt.Errorf("account %s has both 'state' and 'stateDiff'", addr.Hex())
+		}
+		if account.State != nil {
+			storage, err := overrideStorage(*account.State)
+			if err != nil {
+				return err
+			}
+			stateDb.SetStorage(addr, storage)
+		}
+		if account.StateDiff != nil {
+			storage, err := overrideStorage(*account.StateDiff)
+			if err != nil {
+				return err
+			}
+			for key, value := range storage {
+				stateDb.SetState(addr, key, value)
+			}
+		}
+	}
+	return nil
+}
+
+// decodeHexArg decodes a 0x prefixed hex argument. Unlike common.FromHex it
+// fails for odd lengths and characters which aren't hex digits.
+func decodeHexArg(name, value string) ([]byte, error) {
+	if !strings.HasPrefix(value, "0x") {
+		return nil, fmt.Errorf("%s: hex string without 0x prefix", name)
+	}
+	b, err := hex.DecodeString(value[2:])
+	if err != nil {
+		return nil, fmt.Errorf("%s: invalid hex string %q", name, value)
+	}
+	return b, nil
+}
+
+// overrideStorage converts the hex encoded slots of a storage override.
+func overrideStorage(slots map[string]common.Hash) (state.Storage, error) {
+	storage := make(state.Storage, len(slots))
+	for key, value := range slots {
+		slot, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+		if err != nil || len(slot) != common.HashLength {
+			return nil, fmt.Errorf("invalid storage slot %q", key)
+		}
+		storage[common.BytesToHash(slot)] = value
+	}
+	return storage, nil
+}
+
+// BlockOverrides holds the fields of the block context that are replaced
+// before a call is executed.
+type BlockOverrides struct {
+	Number   *rpc.HexNumber  `json:"number"`
+	Time     *rpc.HexNumber  `json:"time"`
+	Coinbase *common.Address `json:"coinbase"`
+	GasLimit *rpc.HexNumber  `json:"gasLimit"`
+}
+
+// Apply overrides the given header fields. The header is modified in place, it
+// should be a copy of the one of the block the call is executed on.
+func (diff *BlockOverrides) Apply(header *types.Header) {
+	if diff == nil {
+		return
+	}
+	if diff.Number != nil {
+		header.Number = diff.Number.BigInt()
+	}
+	if diff.Time != nil {
+		header.Time = diff.Time.BigInt()
+	}
+	if diff.Coinbase != nil {
+		header.Coinbase = *diff.Coinbase
+	}
+	if diff.GasLimit != nil {
+		header.GasLimit = diff.GasLimit.BigInt()
+	}
+}
+
+// callSender returns the address a call is made from, defaulting to the first
+// managed account if the call doesn't specify one.
+func (s *PublicBlockChainAPI) callSender(from common.Address) common.Address {
+	if from == (common.Address{}) {
+		if accounts := s.am.Accounts(); len(accounts) > 0 {
+			return accounts[0].Address
+		}
+	}
+	return from
+}
+
+func (s *PublicBlockChainAPI) doCall(args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, blockOverrides *BlockOverrides) (string, *big.Int, error) {
 	// Fetch the state associated with the block number
 	stateDb, block, err := stateAndBlockByNumber(s.miner, s.bc, blockNr, s.chainDb)
 	if stateDb == nil || err != nil {
@@ -750,19 +1352,16 @@ func (s *PublicBlockChainAPI) doCall(args CallArgs, blockNr rpc.BlockNumber) (st
 	stateDb = stateDb.Copy()
 
 	// Retrieve the account state object to interact with
-	var from *state.StateObject
-	if args.From == (common.Address{}) {
-		accounts := s.am.Accounts()
-		if len(accounts) == 0 {
-			from = stateDb.GetOrNewStateObject(common.Address{})
-		} else {
-			from = stateDb.GetOrNewStateObject(accounts[0].Address)
-		}
-	} else {
-		from = stateDb.GetOrNewStateObject(args.From)
-	}
+	from := stateDb.GetOrNewStateObject(s.callSender(args.From))
 	from.SetBalance(common.MaxBig)
 
+	// Apply the overrides last, so an explicit balance for the sender wins
+	if err := overrides.Apply(stateDb); err != nil {
+		return "0x", nil, err
+	}
+	header := types.CopyHeader(block.Header())
+	blockOverrides.Apply(header)
+
 	// Assemble the CALL invocation
 	msg := callmsg{
 		from:     from,
@@ -780,7 +1379,7 @@ func (s *PublicBlockChainAPI) doCall(args CallArgs, blockNr rpc.BlockNumber) (st
 	}
 
 	// Execute the call and return
-	vmenv := core.NewEnv(stateDb, s.config, s.bc, msg, block.Header())
+	vmenv := core.NewEnv(stateDb, s.config, s.bc, msg, header)
 	gp := new(core.GasPool).AddGas(common.MaxBig)
 
 	res, requiredGas, _, err := core.NewStateTransition(vmenv, msg, gp).TransitionDb()
@@ -792,14 +1391,16 @@ func (s *PublicBlockChainAPI) doCall(args CallArgs, blockNr rpc.BlockNumber) (st
 
 // Call executes the given transaction on the state for the given block number.
 // It doesn't make and changes in the state/blockchain and is useful to execute and retrieve values.
-func (s *PublicBlockChainAPI) Call(args CallArgs, blockNr rpc.BlockNumber) (string, error) {
-	result, _, err := s.doCall(args, blockNr)
+// The optional overrides replace account fields and block context values for the call only.
+func (s *PublicBlockChainAPI) Call(args CallArgs, blockNr rpc.BlockNumber, overrides *StateOverride, blockOverrides *BlockOverrides) (string, error) {
+	result, _, err := s.doCall(args, blockNr, overrides, blockOverrides)
 	return result, err
 }
 
 // EstimateGas returns an estimate of the amount of gas needed to execute the given transaction.
-func (s *PublicBlockChainAPI) EstimateGas(args CallArgs) (*rpc.HexNumber, error) {
-	_, gas, err := s.doCall(args, rpc.PendingBlockNumber)
+// The optional overrides are applied to the pending state and block before the estimation.
+func (s *PublicBlockChainAPI) EstimateGas(args CallArgs, overrides *StateOverride, blockOverrides *BlockOverrides) (*rpc.HexNumber, error) {
+	_, gas, err := s.doCall(args, rpc.PendingBlockNumber, overrides, blockOverrides)
 	return rpc.NewHexNumber(gas), err
 }
 
@@ -812,6 +1413,7 @@ func (s *PublicBlockChainAPI) rpcOutputBlock(b *types.Block, inclTx bool, fullTx
 		"hash":             b.Hash(),
 		"parentHash":       b.ParentHash(),
 		"nonce":            b.Header().Nonce,
+		"mixHash":          b.MixDigest(),
 		"sha3Uncles":       b.UncleHash(),
 		"logsBloom":        b.Bloom(),
 		"stateRoot":        b.Root(),
@@ -877,6 +1479,9 @@ type RPCTransaction struct {
 	Value            *rpc.HexNumber  `json:"value"`
 	ReplayProtected  bool            `json:"replayProtected"`
 	ChainId          *big.Int        `json:"chainId,omitempty"`
+	V                *rpc.HexNumber  `json:"v"`
+	R                *rpc.HexNumber  `json:"r"`
+	S                *rpc.HexNumber  `json:"s"`
 }
 
 // newRPCPendingTransaction returns a pending transaction that will serialize to the RPC representation
@@ -889,6 +1494,7 @@ func newRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
 		protected = true
 		chainId = tx.ChainId()
 	}
+	v, r, sig := tx.RawSignatureValues()
 
 	return &RPCTransaction{
 		From:            from,
@@ -901,9 +1507,24 @@ func newRPCPendingTransaction(tx *types.Transaction) *RPCTransaction {
 		Value:           rpc.NewHexNumber(tx.Value()),
 		ReplayProtected: protected,
 		ChainId:         chainId,
+		V:               rpc.NewHexNumber(v),
+		R:               rpc.NewHexNumber(r),
+		S:               rpc.NewHexNumber(sig),
 	}
 }
 
+// newRPCRawTransaction returns a decoded raw transaction that will serialize to
+// the RPC representation, with the sender recovered by the signer of the current block.
+func newRPCRawTransaction(bc *core.BlockChain, tx *types.Transaction) (*RPCTransaction, error) {
+	from, err := types.Sender(bc.Config().GetSigner(bc.CurrentBlock().Number()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("invalid sender: %v", err)
+	}
+	rpcTx := newRPCPendingTransaction(tx)
+	rpcTx.From = from
+	return rpcTx, nil
+}
+
 // newRPCTransaction returns a transaction that will serialize to the RPC representation.
 func newRPCTransactionFromBlockIndex(b *types.Block, txIndex int) (*RPCTransaction, error) {
 	if txIndex >= 0 && txIndex < len(b.Transactions()) {
@@ -917,6 +1538,7 @@ func newRPCTransactionFromBlockIndex(b *types.Block, txIndex int) (*RPCTransacti
 			chainId = tx.ChainId()
 		}
 		from, _ := types.Sender(signer, tx)
+		v, r, s := tx.RawSignatureValues()
 
 		return &RPCTransaction{
 			BlockHash:        b.Hash(),
@@ -932,6 +1554,9 @@ func newRPCTransactionFromBlockIndex(b *types.Block, txIndex int) (*RPCTransacti
 			Value:            rpc.NewHexNumber(tx.Value()),
 			ReplayProtected:  protected,
 			ChainId:          chainId,
+			V:                rpc.NewHexNumber(v),
+			R:                rpc.NewHexNumber(r),
+			S:                rpc.NewHexNumber(s),
 		}, nil
 	}
 
@@ -1166,12 +1791,7 @@ func (s *PublicTransactionPoolAPI) GetTransactionReceipt(txHash common.Hash) (ma
 // sign is a helper function that signs a transaction with the private key of the given address.
 func (s *PublicTransactionPoolAPI) sign(addr common.Address, tx *types.Transaction) (*types.Transaction, error) {
 	signer := s.bc.Config().GetSigner(s.bc.CurrentBlock().Number())
-
-	signature, err := s.am.Sign(addr, signer.Hash(tx).Bytes())
-	if err != nil {
-		return nil, err
-	}
-	return tx.WithSigner(signer).WithSignature(signature)
+	return s.am.SignTx(addr, tx, signer)
 }
 
 // SendTxArgs represents the arguments to sumbit a new transaction into the transaction pool.
@@ -1199,15 +1819,8 @@ func prepareSendTxArgs(args SendTxArgs, gpo *GasPriceOracle) SendTxArgs {
 	return args
 }
 
-// submitTransaction is a helper function that submits tx to txPool and creates a log entry.
-func submitTransaction(bc *core.BlockChain, txPool *core.TxPool, tx *types.Transaction, signature []byte) (common.Hash, error) {
-	signer := bc.Config().GetSigner(bc.CurrentBlock().Number())
-
-	signedTx, err := tx.WithSigner(signer).WithSignature(signature)
-	if err != nil {
-		return common.Hash{}, err
-	}
-
+// submitTransaction is a helper function that submits a signed tx to txPool and creates a log entry.
+func submitTransaction(txPool *core.TxPool, signedTx *types.Transaction) (common.Hash, error) {
 	txPool.SetLocal(signedTx)
 	if err := txPool.Add(signedTx); err != nil {
 		return common.Hash{}, err
@@ -1218,7 +1831,7 @@ func submitTransaction(bc *core.BlockChain, txPool *core.TxPool, tx *types.Trans
 		addr := crypto.CreateAddress(from, signedTx.Nonce())
 		glog.V(logger.Info).Infof("Tx(%s) created: %s\n", signedTx.Hash().Hex(), addr.Hex())
 	} else {
-		glog.V(logger.Info).Infof("Tx(%s) to: %s\n", signedTx.Hash().Hex(), tx.To().Hex())
+		glog.V(logger.Info).Infof("Tx(%s) to: %s\n", signedTx.Hash().Hex(), signedTx.To().Hex())
 	}
 
 	return signedTx.Hash(), nil
@@ -1243,15 +1856,12 @@ func (s *PublicTransactionPoolAPI) SendTransaction(args SendTxArgs) (common.Hash
 		tx = types.NewTransaction(args.Nonce.Uint64(), *args.To, args.Value.BigInt(), args.Gas.BigInt(), args.GasPrice.BigInt(), common.FromHex(args.Data))
 	}
 
-	signer := s.bc.Config().GetSigner(s.bc.CurrentBlock().Number())
-	tx.SetSigner(signer)
-
-	signature, err := s.am.Sign(args.From, signer.Hash(tx).Bytes())
+	signedTx, err := s.sign(args.From, tx)
 	if err != nil {
 		return common.Hash{}, err
 	}
 
-	return submitTransaction(s.bc, s.txPool, tx, signature)
+	return submitTransaction(s.txPool, signedTx)
 }
 
 // SendRawTransaction will add the signed transaction to the transaction pool.
@@ -1281,6 +1891,16 @@ func (s *PublicTransactionPoolAPI) SendRawTransaction(encodedTx string) (string,
 	return tx.Hash().Hex(), nil
 }
 
+// DecodeRawTransaction decodes a signed transaction without adding it to the
+// transaction pool. The sender is recovered with the signer of the current block.
+func (s *PublicTransactionPoolAPI) DecodeRawTransaction(encodedTx string) (*RPCTransaction, error) {
+	tx := new(types.Transaction)
+	if err := rlp.DecodeBytes(common.FromHex(encodedTx), tx); err != nil {
+		return nil, err
+	}
+	return newRPCRawTransaction(s.bc, tx)
+}
+
 // Sign signs the given hash using the key that matches the address. The key must be
 // unlocked in order to sign the hash.
 func (s *PublicTransactionPoolAPI) Sign(addr common.Address, hash common.Hash) (string, error) {
@@ -1680,6 +2300,84 @@ func (api *PublicDebugAPI) SeedHash(number uint64) (string, error) {
 	return fmt.Sprintf("0x%x", hash), nil
 }
 
+// maxIndexRange is the largest number of heights the side block and reorg
+// queries scan at once.
+const maxIndexRange = 100000
+
+// indexRange validates a range of heights to query, defaulting to the current head.
+func (api *PublicDebugAPI) indexRange(from uint64, to *uint64) (uint64, uint64, error) {
+	end := api.eth.BlockChain().CurrentBlock().NumberU64()
+	if to != nil {
+		end = *to
+	}
+	if end < from {
+		return 0, 0, fmt.Errorf("invalid range: #%d is after #%d", from, end)
+	}
+	if end-from >= maxIndexRange {
+		return 0, 0, fmt.Errorf("range of %d blocks exceeds limit of %d", end-from+1, maxIndexRange)
+	}
+	return from, end, nil
+}
+
+// GetSideBlocks returns the non-canonical blocks seen between the heights from
+// and to (inclusive, defaults to the current head), including whether they
+// were later included as an uncle.
+func (api *PublicDebugAPI) GetSideBlocks(from uint64, to *uint64) ([]map[string]interface{}, error) {
+	from, end, err := api.indexRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	chain := api.eth.BlockChain()
+	sides := chain.GetSideBlocks(from, end)
+
+	fields := make([]map[string]interface{}, len(sides))
+	for i, side := range sides {
+		fields[i] = map[string]interface{}{
+			"number":          rpc.NewHexNumber(side.Number),
+			"hash":            side.Hash,
+			"parentHash":      side.ParentHash,
+			"totalDifficulty": rpc.NewHexNumber(side.Td),
+			"miner":           side.Miner,
+			"firstSeen":       rpc.NewHexNumber(side.FirstSeen),
+			"reorged":         side.Reorged,
+			"uncle":           false,
+			"includedIn":      nil,
+		}
+		if block := chain.GetUncleInclusion(side); block != nil {
+			fields[i]["uncle"] = true
+			fields[i]["includedIn"] = block.Hash()
+		}
+	}
+	return fields, nil
+}
+
+// GetReorgs returns the chain reorganisations which led to a new head between
+// the heights from and to (inclusive, defaults to the current head).
+func (api *PublicDebugAPI) GetReorgs(from uint64, to *uint64) ([]map[string]interface{}, error) {
+	from, end, err := api.indexRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	reorgs := api.eth.BlockChain().GetReorgs(from, end)
+
+	fields := make([]map[string]interface{}, len(reorgs))
+	for i, reorg := range reorgs {
+		fields[i] = map[string]interface{}{
+			"time":         rpc.NewHexNumber(reorg.Time),
+			"commonNumber": rpc.NewHexNumber(reorg.CommonNumber),
+			"commonHash":   reorg.CommonHash,
+			"oldNumber":    rpc.NewHexNumber(reorg.OldNumber),
+			"oldHead":      reorg.OldHead,
+			"newNumber":    rpc.NewHexNumber(reorg.NewNumber),
+			"newHead":      reorg.NewHead,
+			"depth":        len(reorg.Dropped),
+			"dropped":      reorg.Dropped,
+			"added":        reorg.Added,
+		}
+	}
+	return fields, nil
+}
+
 // Metrics return all available registered metrics for the client.
 // See https://github.com/ethereumproject/go-ethereum/wiki/Metrics-and-Monitoring for prophetic documentation.
 func (api *PublicDebugAPI) Metrics(raw bool) (map[string]interface{}, error) {
@@ -1724,13 +2422,20 @@ func (api *PublicDebugAPI) Metrics(raw bool) (map[string]interface{}, error) {
 					h5m := format(m["5m.rate"].(float64)*300, m["5m.rate"].(float64))
 					h15m := format(m["15m.rate"].(float64)*900, m["15m.rate"].(float64))
 					hmr := format(m["mean.rate"].(float64), m["mean.rate"].(float64))
-					rout[k] = map[string]interface{}{
+					hm := map[string]interface{}{
 						"1m.rate":   h1m,
 						"5m.rate":   h5m,
 						"15m.rate":  h15m,
 						"mean.rate": hmr,
 						"count":     fmt.Sprintf("%v", m["count"]),
 					}
+					// Timers additionally hold a histogram of durations in nanoseconds.
+					for _, p := range []string{"median", "95%", "99%", "max"} {
+						if d, ok := m[p].(float64); ok {
+							hm[p] = time.Duration(d).String()
+						}
+					}
+					rout[k] = hm
 				} else if _, ok := m["value"]; ok {
 					rout[k] = map[string]interface{}{
 						"value": fmt.Sprintf("%v", m["value"]),
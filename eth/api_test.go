@@ -0,0 +1,377 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/ethdb"
+	"github.com/ethereumproject/go-ethereum/event"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+// newTestBlockChainAPI creates a blockchain API over a chain of the given
+// number of empty blocks on top of the testBank genesis.
+func newTestBlockChainAPI(t *testing.T, blocks int) *PublicBlockChainAPI {
+	var (
+		evmux   = new(event.TypeMux)
+		db, _   = ethdb.NewMemDatabase()
+		genesis = core.WriteGenesisBlockForTesting(db, testBank)
+		config  = core.MakeChainConfig()
+	)
+	blockchain, err := core.NewBlockChain(db, config, core.FakePow{}, evmux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	chain, _ := core.GenerateChain(config, genesis, db, blocks, nil)
+	if _, err := blockchain.InsertChain(chain); err != nil {
+		t.Fatal(err)
+	}
+	return NewPublicBlockChainAPI(config, blockchain, nil, db, nil, evmux, nil)
+}
+
+func TestCallOverrides(t *testing.T) {
+	api := newTestBlockChainAPI(t, 2)
+	defer api.eventMux.Stop()
+
+	var (
+		contract = common.HexToAddress("0x1234")
+		// returns NUMBER, COINBASE, BALANCE(contract) and SLOAD of slots 0 and 1
+		code = "0x43600052416020523031604052600054606052600154608052" + "60a06000f3"
+		args = CallArgs{From: testBank.Address, To: &contract, GasPrice: rpc.NewHexNumber(1)}
+	)
+	var overrides StateOverride
+	if err := json.Unmarshal([]byte(`{"`+contract.Hex()+`": {
+		"code": "`+code+`",
+		"balance": "0x2a",
+		"state": {"0x0000000000000000000000000000000000000000000000000000000000000000": "0x0000000000000000000000000000000000000000000000000000000000000007"}
+	}}`), &overrides); err != nil {
+		t.Fatal(err)
+	}
+	var blockOverrides BlockOverrides
+	if err := json.Unmarshal([]byte(`{"number": "0x64", "coinbase": "0x00000000000000000000000000000000000000ff"}`), &blockOverrides); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without overrides the contract doesn't exist and returns nothing
+	if res, err := api.Call(args, rpc.LatestBlockNumber, nil, nil); err != nil || res != "0x" {
+		t.Fatalf("plain call mismatch: have %s, %v, want 0x", res, err)
+	}
+	res, err := api.Call(args, rpc.LatestBlockNumber, &overrides, &blockOverrides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []common.Hash{
+		common.BigToHash(big.NewInt(100)),
+		common.BigToHash(big.NewInt(0xff)),
+		common.BigToHash(big.NewInt(42)),
+		common.BigToHash(big.NewInt(7)),
+		{},
+	}
+	out := common.FromHex(res)
+	if len(out) != len(want)*common.HashLength {
+		t.Fatalf("output length mismatch: have %d, want %d", len(out), len(want)*common.HashLength)
+	}
+	for i, word := range want {
+		if have := common.BytesToHash(out[i*common.HashLength : (i+1)*common.HashLength]); have != word {
+			t.Errorf("word %d mismatch: have %x, want %x", i, have, word)
+		}
+	}
+
+	// The overrides must not leak into the chain state
+	stateDb, _ := api.bc.State()
+	if code := stateDb.GetCode(contract); len(code) != 0 {
+		t.Errorf("override leaked into the chain state: code %x", code)
+	}
+}
+
+func TestCallOverridesConflict(t *testing.T) {
+	api := newTestBlockChainAPI(t, 1)
+	defer api.eventMux.Stop()
+
+	var overrides StateOverride
+	if err := json.Unmarshal([]byte(`{"0x0000000000000000000000000000000000001234": {"state": {}, "stateDiff": {}}}`), &overrides); err != nil {
+		t.Fatal(err)
+	}
+	contract := common.HexToAddress("0x1234")
+	args := CallArgs{From: testBank.Address, To: &contract, GasPrice: rpc.NewHexNumber(1)}
+	if _, err := api.Call(args, rpc.LatestBlockNumber, &overrides, nil); err == nil {
+		t.Error("expected error for conflicting state and stateDiff")
+	}
+}
+
+func TestCallBundle(t *testing.T) {
+	api := newTestBlockChainAPI(t, 1)
+	defer api.eventMux.Stop()
+
+	var (
+		recipient = common.HexToAddress("0xabcd")
+		// emits an empty LOG0 and returns
+		logger = common.HexToAddress("0x1234")
+		// executes an invalid opcode
+		failer = common.HexToAddress("0x5678")
+	)
+	overrides := StateOverride{
+		logger.Hex(): OverrideAccount{Code: &[]string{"0x60006000a0"}[0]},
+		failer.Hex(): OverrideAccount{Code: &[]string{"0xfe"}[0]},
+	}
+	transfer, _ := types.NewTransaction(0, recipient, big.NewInt(1000), big.NewInt(21000), big.NewInt(1), nil).SignECDSA(testBankKey)
+	raw, _ := rlp.EncodeToBytes(transfer)
+	rawTx := common.ToHex(raw)
+
+	txs := []BundleTxArgs{
+		{Raw: &rawTx},
+		{CallArgs: CallArgs{From: testBank.Address, To: &logger, Gas: rpc.NewHexNumber(50000), GasPrice: rpc.NewHexNumber(1)}},
+		{Raw: &rawTx},
+		{CallArgs: CallArgs{From: testBank.Address, To: &failer, Gas: rpc.NewHexNumber(30000), GasPrice: rpc.NewHexNumber(1)}},
+	}
+	result, err := api.CallBundle(txs, rpc.LatestBlockNumber, &overrides, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Transactions) != len(txs) {
+		t.Fatalf("result count mismatch: have %d, want %d", len(result.Transactions), len(txs))
+	}
+
+	// The transfer succeeds and moves the value and the fee
+	res := result.Transactions[0]
+	if res.Error != "" || res.TxHash == nil || *res.TxHash != transfer.Hash() || res.GasUsed.Int64() != 21000 {
+		t.Errorf("transfer mismatch: %+v", res)
+	}
+	changes := make(map[common.Address]BalanceChange)
+	for _, change := range res.BalanceChanges {
+		changes[change.Address] = change
+	}
+	if change := changes[recipient]; change.After == nil || change.After.Int64() != 1000 {
+		t.Errorf("recipient balance change mismatch: %+v", change)
+	}
+	if change := changes[testBank.Address]; change.After == nil || change.Before.Int64()-change.After.Int64() != 22000 {
+		t.Errorf("sender balance change mismatch: %+v", change)
+	}
+
+	// The unsigned call uses the nonce left by the transfer and emits a log
+	if res := result.Transactions[1]; res.Error != "" || res.TxHash != nil || len(res.Logs) != 1 || res.Logs[0].Address != logger {
+		t.Errorf("log call mismatch: %+v", res)
+	}
+	// Replaying the transfer fails its nonce check without changing the state
+	if res := result.Transactions[2]; res.Error == "" || res.GasUsed.Int64() != 0 || len(res.BalanceChanges) != 0 {
+		t.Errorf("replayed transfer mismatch: %+v", res)
+	}
+	// The failing call reports the VM error and consumes all its gas
+	if res := result.Transactions[3]; res.Error == "" || res.GasUsed.Int64() != 30000 {
+		t.Errorf("failing call mismatch: %+v", res)
+	}
+	if have, want := result.GasUsed.Int64(), result.Transactions[0].GasUsed.Int64()+result.Transactions[1].GasUsed.Int64()+30000; have != want {
+		t.Errorf("bundle gas mismatch: have %d, want %d", have, want)
+	}
+
+	// Nothing must have leaked into the chain state
+	stateDb, _ := api.bc.State()
+	if nonce := stateDb.GetNonce(testBank.Address); nonce != 0 {
+		t.Errorf("bundle leaked into the chain state: nonce %d", nonce)
+	}
+}
+
+func TestPersonalSign(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eth-personal-sign-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	am, err := accounts.NewManager(dir, 2, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, err := am.NewAccount("foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	bcapi := newTestBlockChainAPI(t, 1)
+	defer bcapi.eventMux.Stop()
+	api := &PrivateAccountAPI{bc: bcapi.bc, am: am}
+
+	sig, err := api.Sign("0x68656c6c6f", account.Address, "foo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v := common.FromHex(sig)[64]; v != 27 && v != 28 {
+		t.Errorf("recovery id mismatch: have %d, want 27 or 28", v)
+	}
+	if addr, err := api.EcRecover("0x68656c6c6f", sig); err != nil || addr != account.Address {
+		t.Errorf("recovered address mismatch: have %x, %v, want %x", addr, err, account.Address)
+	}
+	if _, err := api.Sign("0x68656c6c6f", account.Address, "bar"); err == nil {
+		t.Error("expected error for wrong passphrase")
+	}
+	// Malformed hex is rejected rather than signed or recovered partially
+	for _, bad := range []string{"hello", "0xhello", "0x68656c6c6", "68656c6c6f"} {
+		if _, err := api.Sign(bad, account.Address, "foo"); err == nil {
+			t.Errorf("no error signing data %q", bad)
+		}
+		if _, err := api.EcRecover(bad, sig); err == nil {
+			t.Errorf("no error recovering data %q", bad)
+		}
+	}
+	for _, bad := range []string{sig[:len(sig)-1], sig[2:], "0x" + strings.Repeat("zz", 65)} {
+		if _, err := api.EcRecover("0x68656c6c6f", bad); err == nil {
+			t.Errorf("no error recovering signature %q", bad)
+		}
+	}
+
+	// Typed data is only signed for the chain of the node
+	chainID := bcapi.bc.Config().GetChainID()
+	typed := func(chainID string) accounts.TypedData {
+		var data accounts.TypedData
+		if err := json.Unmarshal([]byte(`{
+			"types": {"EIP712Domain": [{"name": "chainId", "type": "uint256"}], "Login": [{"name": "nonce", "type": "uint64"}]},
+			"primaryType": "Login",
+			"domain": {"chainId": `+chainID+`},
+			"message": {"nonce": 7}
+		}`), &data); err != nil {
+			t.Fatal(err)
+		}
+		return data
+	}
+	if _, err := api.SignTypedData(account.Address, typed(chainID.String()), "foo"); err != nil {
+		t.Errorf("failed to sign typed data: %v", err)
+	}
+	wrongChainID := new(big.Int).Add(chainID, common.Big1)
+	if _, err := api.SignTypedData(account.Address, typed(wrongChainID.String()), "foo"); err == nil {
+		t.Error("expected error for typed data of another chain")
+	}
+}
+
+func TestRawTransactionValidation(t *testing.T) {
+	var (
+		evmux = new(event.TypeMux)
+		db, _ = ethdb.NewMemDatabase()
+	)
+	defer evmux.Stop()
+	core.WriteGenesisBlockForTesting(db, testBank)
+	// The chain is replay protected from the genesis block
+	bc, err := core.NewBlockChain(db, core.MakeDiehardChainConfig(), core.FakePow{}, evmux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := core.NewTxPool(bc.Config(), evmux, bc.State, bc.GasLimit)
+	defer pool.Stop()
+	txapi := &PublicTransactionPoolAPI{bc: bc, txPool: pool}
+	poolapi := NewPublicTxPoolAPI(&Ethereum{blockchain: bc, txPool: pool})
+
+	chainID := bc.Config().GetChainID()
+	sign := func(signer types.Signer, tx *types.Transaction) string {
+		tx, err := signer.SignECDSA(tx, testBankKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, _ := rlp.EncodeToBytes(tx)
+		return common.ToHex(raw)
+	}
+	recipient := common.HexToAddress("0xabcd")
+	transfer := types.NewTransaction(0, recipient, big.NewInt(1000), big.NewInt(21000), big.NewInt(1), nil)
+
+	// Replay protected and unprotected transactions are decoded
+	protected := sign(types.NewChainIdSigner(chainID), transfer)
+	tx, err := txapi.DecodeRawTransaction(protected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.From != testBank.Address || !tx.ReplayProtected || tx.ChainId.Cmp(chainID) != 0 || *tx.To != recipient || tx.Value.Int() != 1000 {
+		t.Errorf("protected transaction mismatch: %+v", tx)
+	}
+	protectedHash := tx.Hash
+	if tx, err = txapi.DecodeRawTransaction(sign(types.BasicSigner{}, transfer)); err != nil {
+		t.Fatal(err)
+	}
+	if tx.From != testBank.Address || tx.ReplayProtected || tx.ChainId != nil {
+		t.Errorf("unprotected transaction mismatch: %+v", tx)
+	}
+	otherChain := sign(types.NewChainIdSigner(new(big.Int).Add(chainID, common.Big1)), transfer)
+	if _, err := txapi.DecodeRawTransaction(otherChain); err == nil {
+		t.Error("decoded transaction of another chain")
+	}
+	if _, err := txapi.DecodeRawTransaction("0x1234"); err == nil {
+		t.Error("decoded invalid RLP")
+	}
+
+	// Validation reports all failed checks without adding to the pool
+	result, err := poolapi.Validate(protected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || len(result.Errors) != 0 || result.Transaction.Hash != protectedHash {
+		t.Errorf("valid transaction mismatch: %+v", result)
+	}
+	if pending, queued := pool.Stats(); pending+queued != 0 {
+		t.Errorf("validated transaction added to the pool")
+	}
+
+	expensive := types.NewTransaction(0, recipient, big.NewInt(2000000), big.NewInt(20000), big.NewInt(1), nil)
+	if result, err = poolapi.Validate(sign(types.NewChainIdSigner(chainID), expensive)); err != nil {
+		t.Fatal(err)
+	}
+	want := []TxValidationError{
+		{Reason: "insufficientFunds", Have: rpc.NewHexNumber(1000000), Want: rpc.NewHexNumber(2020000)},
+		{Reason: "intrinsicGasTooLow", Have: rpc.NewHexNumber(20000), Want: rpc.NewHexNumber(21000)},
+	}
+	if result.Valid || len(result.Errors) != len(want) {
+		t.Fatalf("invalid transaction mismatch: %+v", result)
+	}
+	for i, e := range result.Errors {
+		if e.Reason != want[i].Reason || e.Have.BigInt().Cmp(want[i].Have.BigInt()) != 0 || e.Want.BigInt().Cmp(want[i].Want.BigInt()) != 0 {
+			t.Errorf("error %d: have %+v, want %+v", i, e, want[i])
+		}
+	}
+
+	// The gas price of raw transactions isn't checked. The price change is
+	// posted twice, the pool handled the first once the second is delivered.
+	for i := 0; i < 2; i++ {
+		evmux.Post(core.GasPriceChanged{Price: big.NewInt(1000)})
+	}
+	cheap := types.NewTransaction(0, recipient, big.NewInt(1000), big.NewInt(21000), big.NewInt(40), nil)
+	if result, err = poolapi.Validate(sign(types.NewChainIdSigner(chainID), cheap)); err != nil {
+		t.Fatal(err)
+	}
+	if !result.Valid || len(result.Errors) != 0 {
+		t.Errorf("transaction below the minimal gas price mismatch: %+v", result)
+	}
+	// while the pool rejects it unless it is marked local
+	signed, err := types.NewChainIdSigner(chainID).SignECDSA(cheap, testBankKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pool.Add(signed); err != core.ErrCheap {
+		t.Errorf("adding transaction below the minimal gas price: have error %v, want %v", err, core.ErrCheap)
+	}
+
+	if result, err = poolapi.Validate(otherChain); err != nil {
+		t.Fatal(err)
+	}
+	if result.Valid || len(result.Errors) != 1 || result.Errors[0].Reason != "invalidSender" {
+		t.Errorf("transaction of another chain mismatch: %+v", result)
+	}
+}
@@ -33,6 +33,7 @@ import (
 	"github.com/ethereumproject/go-ethereum/common/compiler"
 	"github.com/ethereumproject/go-ethereum/common/httpclient"
 	"github.com/ethereumproject/go-ethereum/common/registrar/ethreg"
+	"github.com/ethereumproject/go-ethereum/contracts/multisig"
 	"github.com/ethereumproject/go-ethereum/core"
 	"github.com/ethereumproject/go-ethereum/core/types"
 	"github.com/ethereumproject/go-ethereum/eth/downloader"
@@ -44,6 +45,7 @@ import (
 	"github.com/ethereumproject/go-ethereum/miner"
 	"github.com/ethereumproject/go-ethereum/node"
 	"github.com/ethereumproject/go-ethereum/p2p"
+	"github.com/ethereumproject/go-ethereum/pow"
 	"github.com/ethereumproject/go-ethereum/rlp"
 	"github.com/ethereumproject/go-ethereum/rpc"
 )
@@ -74,10 +76,15 @@ type Config struct {
 	PowTest   bool
 	PowShared bool
 
+	InstantSeal       bool          // Seal blocks without proof-of-work (developer mode)
+	InstantSealPeriod time.Duration // Block period of instant sealing, 0 seals as soon as transactions are pending
+
 	AccountManager *accounts.Manager
 	Etherbase      common.Address
 	GasPrice       *big.Int
 	MinerThreads   int
+	TxPolicy       string           // Transaction ordering policy used for mining (see miner.SelectorNames)
+	TxWhitelist    []common.Address // Senders prioritised by the "whitelist" transaction policy
 	SolcPath       string
 
 	GpoMinGasPrice          *big.Int
@@ -105,7 +112,7 @@ type Ethereum struct {
 	txMu            sync.Mutex
 	blockchain      *core.BlockChain
 	accountManager  *accounts.Manager
-	pow             *ethash.Ethash
+	pow             pow.PoW
 	protocolManager *ProtocolManager
 	SolcPath        string
 	solc            *compiler.Solidity
@@ -128,6 +135,8 @@ type Ethereum struct {
 	NatSpec       bool
 	AutoDAG       bool
 	PowTest       bool
+	instantSeal   bool
+	sealPeriod    time.Duration
 	autodagquit   chan bool
 	etherbase     common.Address
 	netVersionId  int
@@ -195,6 +204,8 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		SolcPath:                config.SolcPath,
 		AutoDAG:                 config.AutoDAG,
 		PowTest:                 config.PowTest,
+		instantSeal:             config.InstantSeal,
+		sealPeriod:              config.InstantSealPeriod,
 		GpoMinGasPrice:          config.GpoMinGasPrice,
 		GpoMaxGasPrice:          config.GpoMaxGasPrice,
 		GpoFullBlockRatio:       config.GpoFullBlockRatio,
@@ -204,6 +215,9 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 		httpclient:              httpclient.New(config.DocRoot),
 	}
 	switch {
+	case config.InstantSeal:
+		glog.V(logger.Info).Infof("Consensus: blocks are sealed instantly without proof-of-work")
+		eth.pow = core.FakePow{}
 	case config.PowTest:
 		glog.V(logger.Info).Infof("Consensus: ethash used in test mode")
 		eth.pow, err = ethash.NewForTesting()
@@ -263,6 +277,13 @@ func New(ctx *node.ServiceContext, config *Config) (*Ethereum, error) {
 	if err = eth.miner.SetGasPrice(config.GasPrice); err != nil {
 		return nil, err
 	}
+	if config.TxPolicy != "" {
+		selector, err := miner.NewTransactionSelector(config.TxPolicy, config.TxWhitelist)
+		if err != nil {
+			return nil, err
+		}
+		eth.miner.SetTransactionSelector(selector)
+	}
 
 	return eth, nil
 }
@@ -339,6 +360,12 @@ func (s *Ethereum) APIs() []rpc.API {
 			Namespace: "admin",
 			Version:   "1.0",
 			Service:   ethreg.NewPrivateRegistarAPI(s.chainConfig, s.blockchain, s.chainDb, s.txPool, s.accountManager),
+		}, {
+			Namespace: "wallet",
+			Version:   "1.0",
+			Service: multisig.NewPrivateWalletAPI(NewContractBackend(s), s.accountManager, func() types.Signer {
+				return s.chainConfig.GetSigner(s.blockchain.CurrentBlock().Number())
+			}),
 		},
 	}
 }
@@ -394,6 +421,12 @@ func (s *Ethereum) Start(srvr *p2p.Server) error {
 	}
 	s.protocolManager.Start()
 	s.netRPCService = NewPublicNetAPI(srvr, s.NetVersion())
+	if s.instantSeal {
+		s.miner.RegisterInstantSealer(s.sealPeriod)
+		if err := s.StartMining(0, ""); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -17,14 +17,21 @@
 package eth
 
 import (
+	"io/ioutil"
 	"math/big"
+	"os"
 	"testing"
+	"time"
 
+	"github.com/ethereumproject/go-ethereum/accounts"
 	"github.com/ethereumproject/go-ethereum/common"
 	"github.com/ethereumproject/go-ethereum/core"
 	"github.com/ethereumproject/go-ethereum/core/types"
 	"github.com/ethereumproject/go-ethereum/core/vm"
 	"github.com/ethereumproject/go-ethereum/ethdb"
+	"github.com/ethereumproject/go-ethereum/event"
+	"github.com/ethereumproject/go-ethereum/miner"
+	"github.com/ethereumproject/go-ethereum/pow"
 )
 
 func TestMipmapUpgrade(t *testing.T) {
@@ -81,3 +88,62 @@ func TestMipmapUpgrade(t *testing.T) {
 		t.Error("setting-mipmap-version not written to database")
 	}
 }
+
+// trivialPow finds a nonce for any block at once.
+type trivialPow struct{ core.FakePow }
+
+func (trivialPow) Search(pow.Block, <-chan struct{}, int) (uint64, []byte) {
+	return 1, make([]byte, 32)
+}
+
+func TestStartMiningInstantSeal(t *testing.T) {
+	dir, err := ioutil.TempDir("", "eth-instant-seal-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	am, err := accounts.NewManager(dir, 2, 1, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		evmux  = new(event.TypeMux)
+		db, _  = ethdb.NewMemDatabase()
+		config = core.MakeChainConfig()
+	)
+	defer evmux.Stop()
+	core.WriteGenesisBlockForTesting(db, testBank)
+	bc, err := core.NewBlockChain(db, config, trivialPow{}, evmux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	eth := &Ethereum{
+		chainConfig:    config,
+		blockchain:     bc,
+		chainDb:        db,
+		eventMux:       evmux,
+		accountManager: am,
+		etherbase:      common.Address{1},
+		instantSeal:    true,
+	}
+	eth.txPool = core.NewTxPool(config, evmux, bc.State, bc.GasLimit)
+	defer eth.txPool.Stop()
+	eth.miner = miner.New(eth, config, evmux, trivialPow{})
+	defer eth.miner.Stop()
+
+	heads := evmux.Subscribe(core.ChainHeadEvent{})
+	defer heads.Unsubscribe()
+
+	// CPU agents would seal empty blocks right away with the trivial proof-of-work
+	if err := eth.StartMining(4, ""); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-heads.Chan():
+		t.Fatalf("sealed empty block #%v", ev.Data.(core.ChainHeadEvent).Block.Number())
+	case <-time.After(300 * time.Millisecond):
+	}
+	if !eth.miner.Mining() {
+		t.Error("miner not started")
+	}
+}
@@ -73,7 +73,7 @@ func (b *ContractBackend) ContractCall(contract common.Address, data []byte, pen
 		block = rpc.PendingBlockNumber
 	}
 	// Execute the call and convert the output back to Go types
-	out, err := b.bcapi.Call(args, block)
+	out, err := b.bcapi.Call(args, block, nil, nil)
 	return common.FromHex(out), err
 }
 
@@ -101,7 +101,7 @@ func (b *ContractBackend) EstimateGasLimit(sender common.Address, contract *comm
 		To:    contract,
 		Value: *rpc.NewHexNumber(value),
 		Data:  common.ToHex(data),
-	})
+	}, nil, nil)
 	return out.BigInt(), err
 }
 
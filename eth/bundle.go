@@ -0,0 +1,222 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package eth
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/state"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/core/vm"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+// maxBundleSize is the maximum number of transactions in a call bundle.
+const maxBundleSize = 256
+
+// BundleTxArgs is a transaction of a call bundle. It is either a signed
+// transaction in its raw RLP encoding or the arguments of an unsigned call,
+// which is sent with the current nonce of its sender.
+type BundleTxArgs struct {
+	CallArgs
+	Raw *string `json:"raw"`
+}
+
+// BalanceChange is the change of the balance of an account by a transaction.
+type BalanceChange struct {
+	Address common.Address `json:"address"`
+	Before  *rpc.HexNumber `json:"before"`
+	After   *rpc.HexNumber `json:"after"`
+}
+
+// BundleTxResult is the outcome of a transaction of a call bundle. Error is
+// set if the transaction is invalid, in which case it didn't change the
+// state, or if its execution failed in the EVM.
+type BundleTxResult struct {
+	TxHash          *common.Hash    `json:"txHash"`
+	From            common.Address  `json:"from"`
+	To              *common.Address `json:"to"`
+	ContractAddress *common.Address `json:"contractAddress"`
+	GasUsed         *rpc.HexNumber  `json:"gasUsed"`
+	ReturnData      string          `json:"returnData"`
+	Logs            vm.Logs         `json:"logs"`
+	Error           string          `json:"error,omitempty"`
+	BalanceChanges  []BalanceChange `json:"balanceChanges"`
+}
+
+// BundleResult is the outcome of a call bundle.
+type BundleResult struct {
+	BlockNumber  *rpc.HexNumber   `json:"blockNumber"`
+	GasUsed      *rpc.HexNumber   `json:"gasUsed"`
+	Transactions []BundleTxResult `json:"transactions"`
+}
+
+// CallBundle executes the given transactions one after the other on top of the
+// state of the given block, each seeing the changes of the previous ones. None
+// of the transactions is added to the pool and the state is discarded afterwards.
+// Unlike Call, the senders aren't funded, so the bundle fails like it would if
+// broadcast. The optional overrides are applied before the first transaction.
+func (s *PublicBlockChainAPI) CallBundle(txs []BundleTxArgs, blockNr rpc.BlockNumber, overrides *StateOverride, blockOverrides *BlockOverrides) (*BundleResult, error) {
+	if len(txs) == 0 {
+		return nil, errors.New("empty bundle")
+	}
+	if len(txs) > maxBundleSize {
+		return nil, fmt.Errorf("bundle of %d transactions exceeds the maximum of %d", len(txs), maxBundleSize)
+	}
+	stateDb, block, err := stateAndBlockByNumber(s.miner, s.bc, blockNr, s.chainDb)
+	if err != nil {
+		return nil, err
+	}
+	if stateDb == nil {
+		return nil, fmt.Errorf("block #%d not found", blockNr)
+	}
+	stateDb = stateDb.Copy()
+	if err := overrides.Apply(stateDb); err != nil {
+		return nil, err
+	}
+	header := types.CopyHeader(block.Header())
+	blockOverrides.Apply(header)
+
+	// Decode all the transactions before executing any of them
+	msgs := make([]core.Message, len(txs))
+	for i, args := range txs {
+		if args.Raw != nil {
+			tx := new(types.Transaction)
+			if err := rlp.DecodeBytes(common.FromHex(*args.Raw), tx); err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+			tx.SetSigner(s.config.GetSigner(header.Number))
+			if _, err := tx.From(); err != nil {
+				return nil, fmt.Errorf("transaction %d: %v", i, err)
+			}
+			msgs[i] = tx
+			continue
+		}
+		msg := callmsg{
+			from:     stateDb.GetOrNewStateObject(s.callSender(args.From)),
+			to:       args.To,
+			gas:      args.Gas.BigInt(),
+			gasPrice: args.GasPrice.BigInt(),
+			value:    args.Value.BigInt(),
+			data:     common.FromHex(args.Data),
+		}
+		if msg.gas == nil {
+			msg.gas = new(big.Int).Set(header.GasLimit)
+		}
+		if msg.gasPrice == nil {
+			msg.gasPrice = s.gpo.SuggestPrice()
+		}
+		msgs[i] = msg
+	}
+
+	// The balances before each transaction, defaulting to the initial state
+	initial := stateDb.Copy()
+	balances := make(map[common.Address]*big.Int)
+
+	result := &BundleResult{
+		BlockNumber:  rpc.NewHexNumber(header.Number),
+		Transactions: make([]BundleTxResult, len(msgs)),
+	}
+	gp := new(core.GasPool).AddGas(common.MaxBig)
+	totalGas := new(big.Int)
+	for i, msg := range msgs {
+		res := &result.Transactions[i]
+		res.From, _ = msg.From()
+		res.To = msg.To()
+
+		// Unsigned calls are recorded under a unique placeholder hash
+		txHash := common.BigToHash(big.NewInt(int64(i)))
+		if tx, ok := msg.(*types.Transaction); ok {
+			txHash = tx.Hash()
+			res.TxHash = &txHash
+		}
+		nonce := msg.Nonce()
+		stateDb.StartRecord(txHash, common.Hash{}, i)
+
+		vmenv := core.NewEnv(stateDb, s.config, s.bc, msg, header)
+		execution, err := core.SimulateMessage(vmenv, msg, gp)
+		if err != nil {
+			res.Error = err.Error()
+			res.GasUsed = rpc.NewHexNumber(0)
+			res.ReturnData = "0x"
+			res.Logs = vm.Logs{}
+			res.BalanceChanges = []BalanceChange{}
+			continue
+		}
+		// The state shares its account trie with its origin, possibly the live
+		// pending state of the miner, so the trie mustn't be updated.
+		stateDb.Finalise()
+
+		if execution.Failed() {
+			res.Error = execution.Err.Error()
+		}
+		if core.MessageCreatesContract(msg) {
+			addr := crypto.CreateAddress(res.From, nonce)
+			res.ContractAddress = &addr
+		}
+		res.GasUsed = rpc.NewHexNumber(execution.UsedGas)
+		res.ReturnData = common.ToHex(execution.ReturnData)
+		res.Logs = stateDb.GetLogs(txHash)
+		if res.Logs == nil {
+			res.Logs = vm.Logs{}
+		}
+		if res.TxHash == nil {
+			for _, log := range res.Logs {
+				log.TxHash = common.Hash{}
+			}
+		}
+		res.BalanceChanges = balanceChanges(initial, stateDb, balances)
+		totalGas.Add(totalGas, execution.UsedGas)
+	}
+	result.GasUsed = rpc.NewHexNumber(totalGas)
+
+	return result, nil
+}
+
+// balanceChanges returns the changes to the balances of the accounts modified
+// in the given state since the balances were last recorded, and records the
+// current ones. Balances not yet recorded are taken from the initial state.
+func balanceChanges(initial, current *state.StateDB, balances map[common.Address]*big.Int) []BalanceChange {
+	changes := []BalanceChange{}
+	for _, addr := range current.DirtyAccounts() {
+		before, ok := balances[addr]
+		if !ok {
+			before = initial.GetBalance(addr)
+		}
+		after := current.GetBalance(addr)
+		if before.Cmp(after) != 0 {
+			changes = append(changes, BalanceChange{
+				Address: addr,
+				Before:  rpc.NewHexNumber(before),
+				After:   rpc.NewHexNumber(after),
+			})
+		}
+		balances[addr] = new(big.Int).Set(after)
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		return bytes.Compare(changes[i].Address[:], changes[j].Address[:]) < 0
+	})
+	return changes
+}
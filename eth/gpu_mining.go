@@ -41,6 +41,16 @@ func (s *Ethereum) StartMining(threads int, gpus string) error {
 		return err
 	}
 
+	// Development chains are sealed by the instant sealer alone, proof-of-work
+	// agents would seal empty blocks in a loop with the fake difficulty.
+	if s.instantSeal {
+		if threads != 0 || gpus != "" {
+			glog.V(logger.Info).Infoln("Instant sealing, not starting proof-of-work agents")
+		}
+		go s.miner.Start(eb, 0)
+		return nil
+	}
+
 	// GPU mining
 	if gpus != "" {
 		var ids []int
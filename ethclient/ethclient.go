@@ -0,0 +1,544 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package ethclient provides a client for the Ethereum RPC API.
+package ethclient
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereumproject/go-ethereum/accounts/abi/bind"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/core/vm"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+// ErrNotFound is returned when the requested item doesn't exist on the node.
+var ErrNotFound = errors.New("not found")
+
+// This nil assignment ensures compile time that Client implements bind.ContractBackend.
+var _ bind.ContractBackend = (*Client)(nil)
+
+// Client defines typed wrappers for the Ethereum RPC API.
+//
+// Every method takes a context, except for the ones implementing
+// bind.ContractBackend. SuggestGasPrice and SendTransaction take a context in
+// their SuggestGasPriceContext and SendTransactionContext variants.
+type Client struct {
+	c *rpc.ClientConn
+}
+
+// Dial connects a client to the given URL. See rpc.Dial for the supported
+// transports.
+func Dial(rawurl string) (*Client, error) {
+	c, err := rpc.Dial(rawurl)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+// NewClient creates a client that uses the given RPC connection.
+func NewClient(c *rpc.ClientConn) *Client {
+	return &Client{c}
+}
+
+// Close closes the underlying RPC connection.
+func (ec *Client) Close() {
+	ec.c.Close()
+}
+
+// CallMsg contains the parameters of a contract call or gas estimation.
+type CallMsg struct {
+	From     common.Address  // the sender of the 'transaction'
+	To       *common.Address // the destination contract (nil for contract creation)
+	Gas      *big.Int        // if nil, the call executes with near-infinite gas
+	GasPrice *big.Int        // wei <-> gas exchange ratio
+	Value    *big.Int        // amount of wei sent along with the call
+	Data     []byte          // input data, usually an ABI-encoded contract method invocation
+}
+
+// FilterQuery contains options for log filtering.
+type FilterQuery struct {
+	FromBlock *big.Int         // beginning of the queried range, nil means genesis block
+	ToBlock   *big.Int         // end of the range, nil means latest block
+	Addresses []common.Address // restricts matches to events created by specific contracts
+
+	// Topics restricts matches to particular event topics. Each event has a list
+	// of topics; a nil or empty position matches any topic, otherwise one of the
+	// given topics must match at that position.
+	Topics [][]common.Hash
+}
+
+// Blockchain Access
+
+// BlockByHash returns the given full block, including its transactions and
+// uncles.
+func (ec *Client) BlockByHash(ctx context.Context, hash common.Hash) (*types.Block, error) {
+	return ec.getBlock(ctx, "eth_getBlockByHash", hash, true)
+}
+
+// BlockByNumber returns a block from the current canonical chain. If number is
+// nil, the latest known block is returned.
+func (ec *Client) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
+	return ec.getBlock(ctx, "eth_getBlockByNumber", toBlockNumArg(number), true)
+}
+
+// HeaderByHash returns the block header with the given hash.
+func (ec *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
+	var head *rpcHeader
+	if err := ec.c.CallContext(ctx, &head, "eth_getBlockByHash", hash, false); err != nil {
+		return nil, notFound(err)
+	}
+	if head == nil {
+		return nil, ErrNotFound
+	}
+	return head.header()
+}
+
+// HeaderByNumber returns a block header from the current canonical chain. If
+// number is nil, the latest known header is returned.
+func (ec *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	var head *rpcHeader
+	if err := ec.c.CallContext(ctx, &head, "eth_getBlockByNumber", toBlockNumArg(number), false); err != nil {
+		return nil, notFound(err)
+	}
+	if head == nil {
+		return nil, ErrNotFound
+	}
+	return head.header()
+}
+
+func (ec *Client) getBlock(ctx context.Context, method string, args ...interface{}) (*types.Block, error) {
+	var raw json.RawMessage
+	if err := ec.c.CallContext(ctx, &raw, method, args...); err != nil {
+		return nil, notFound(err)
+	}
+	var body struct {
+		rpcHeader
+		Transactions []*rpcTransaction `json:"transactions"`
+		Uncles       []common.Hash     `json:"uncles"`
+	}
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, ErrNotFound
+	}
+	if err := json.Unmarshal(raw, &body); err != nil {
+		return nil, err
+	}
+	head, err := body.header()
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*types.Transaction, len(body.Transactions))
+	for i, tx := range body.Transactions {
+		if txs[i], err = tx.transaction(); err != nil {
+			return nil, err
+		}
+	}
+	// Uncles are only returned by hash, fetch them all in one go.
+	uncles := make([]*types.Header, len(body.Uncles))
+	if len(body.Uncles) > 0 {
+		reqs := make([]rpc.BatchElem, len(body.Uncles))
+		heads := make([]*rpcHeader, len(body.Uncles))
+		for i := range reqs {
+			reqs[i] = rpc.BatchElem{
+				Method: "eth_getUncleByBlockHashAndIndex",
+				Args:   []interface{}{body.Hash, fmt.Sprintf("%#x", i)},
+				Result: &heads[i],
+			}
+		}
+		if err := ec.c.BatchCallContext(ctx, reqs); err != nil {
+			return nil, err
+		}
+		for i := range reqs {
+			if reqs[i].Error != nil {
+				return nil, reqs[i].Error
+			}
+			if heads[i] == nil {
+				return nil, fmt.Errorf("got null header for uncle %d of block %x", i, body.Hash)
+			}
+			if uncles[i], err = heads[i].header(); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return types.NewBlockWithHeader(head).WithBody(txs, uncles), nil
+}
+
+// TransactionByHash returns the transaction with the given hash, and whether it
+// is still pending.
+func (ec *Client) TransactionByHash(ctx context.Context, hash common.Hash) (tx *types.Transaction, isPending bool, err error) {
+	var rtx *rpcTransaction
+	if err := ec.c.CallContext(ctx, &rtx, "eth_getTransactionByHash", hash); err != nil {
+		return nil, false, notFound(err)
+	}
+	if rtx == nil {
+		return nil, false, ErrNotFound
+	}
+	tx, err = rtx.transaction()
+	return tx, rtx.BlockNumber == nil, err
+}
+
+// TransactionReceipt returns the receipt of a mined transaction. Note that the
+// receipt is not available for pending transactions.
+func (ec *Client) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	var r *rpcReceipt
+	if err := ec.c.CallContext(ctx, &r, "eth_getTransactionReceipt", txHash); err != nil {
+		return nil, notFound(err)
+	}
+	if r == nil {
+		return nil, ErrNotFound
+	}
+	return r.receipt(), nil
+}
+
+// SubscribeNewHead subscribes to notifications about the current blockchain
+// head on the given channel.
+func (ec *Client) SubscribeNewHead(ctx context.Context, ch chan<- *types.Header) (Subscription, error) {
+	in := make(chan *rpcHeader)
+	sub, err := ec.c.EthSubscribe(ctx, in, "newBlocks", map[string]bool{"includeTransactions": false})
+	if err != nil {
+		return nil, err
+	}
+	s := &headSubscription{sub: sub, err: make(chan error, 1), quit: make(chan struct{})}
+	go s.forward(in, ch)
+	return s, nil
+}
+
+// State Access
+
+// BalanceAt returns the wei balance of the given account. The block number can
+// be nil, in which case the balance is taken from the latest known block.
+func (ec *Client) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	var balance rpc.HexNumber
+	if err := ec.c.CallContext(ctx, &balance, "eth_getBalance", account, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	return toBig(&balance), nil
+}
+
+// StorageAt returns the value of key in the contract storage of the given
+// account. The block number can be nil, in which case the value is taken from
+// the latest known block.
+func (ec *Client) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	var result string
+	err := ec.c.CallContext(ctx, &result, "eth_getStorageAt", account, key.Hex(), toBlockNumArg(blockNumber))
+	return common.FromHex(result), err
+}
+
+// CodeAt returns the contract code of the given account. The block number can
+// be nil, in which case the code is taken from the latest known block.
+func (ec *Client) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	var result string
+	err := ec.c.CallContext(ctx, &result, "eth_getCode", account, toBlockNumArg(blockNumber))
+	return common.FromHex(result), err
+}
+
+// NonceAt returns the account nonce of the given account. The block number can
+// be nil, in which case the nonce is taken from the latest known block.
+func (ec *Client) NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error) {
+	var result rpc.HexNumber
+	err := ec.c.CallContext(ctx, &result, "eth_getTransactionCount", account, toBlockNumArg(blockNumber))
+	return result.Uint64(), err
+}
+
+// GetProof returns the merkle proof of the given account and of the given
+// slots of its storage. The block number can be nil, in which case the proof
+// is taken from the latest known block. Verify the proof against the header
+// of the same block before trusting its content.
+func (ec *Client) GetProof(ctx context.Context, account common.Address, keys []common.Hash, blockNumber *big.Int) (*AccountProof, error) {
+	hexKeys := make([]string, len(keys))
+	for i, key := range keys {
+		hexKeys[i] = key.Hex()
+	}
+	var result *rpcAccountResult
+	if err := ec.c.CallContext(ctx, &result, "eth_getProof", account, hexKeys, toBlockNumArg(blockNumber)); err != nil {
+		return nil, err
+	}
+	if result == nil {
+		return nil, ErrNotFound
+	}
+	return result.proof(), nil
+}
+
+// FilterLogs executes a filter query.
+func (ec *Client) FilterLogs(ctx context.Context, q FilterQuery) (vm.Logs, error) {
+	var result []*rpcLog
+	if err := ec.c.CallContext(ctx, &result, "eth_getLogs", toFilterArg(q)); err != nil {
+		return nil, err
+	}
+	logs := make(vm.Logs, len(result))
+	for i, l := range result {
+		logs[i] = l.log()
+	}
+	return logs, nil
+}
+
+// Pending State
+
+// PendingBalanceAt returns the wei balance of the given account in the pending
+// state.
+func (ec *Client) PendingBalanceAt(ctx context.Context, account common.Address) (*big.Int, error) {
+	var balance rpc.HexNumber
+	if err := ec.c.CallContext(ctx, &balance, "eth_getBalance", account, "pending"); err != nil {
+		return nil, err
+	}
+	return toBig(&balance), nil
+}
+
+// PendingCodeAt returns the contract code of the given account in the pending
+// state.
+func (ec *Client) PendingCodeAt(ctx context.Context, account common.Address) ([]byte, error) {
+	var result string
+	err := ec.c.CallContext(ctx, &result, "eth_getCode", account, "pending")
+	return common.FromHex(result), err
+}
+
+// PendingNonceAt returns the account nonce of the given account in the pending
+// state. This is the nonce that should be used for the next transaction.
+func (ec *Client) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	var result rpc.HexNumber
+	err := ec.c.CallContext(ctx, &result, "eth_getTransactionCount", account, "pending")
+	return result.Uint64(), err
+}
+
+// Contract Calling
+
+// CallContract executes a message call transaction, which is directly executed
+// in the VM of the node, but never mined into the blockchain. The block number
+// can be nil, in which case the call is executed on the latest known block.
+func (ec *Client) CallContract(ctx context.Context, msg CallMsg, blockNumber *big.Int) ([]byte, error) {
+	var result string
+	err := ec.c.CallContext(ctx, &result, "eth_call", toCallArg(msg), toBlockNumArg(blockNumber))
+	return common.FromHex(result), err
+}
+
+// PendingCallContract executes a message call transaction using the pending
+// state.
+func (ec *Client) PendingCallContract(ctx context.Context, msg CallMsg) ([]byte, error) {
+	var result string
+	err := ec.c.CallContext(ctx, &result, "eth_call", toCallArg(msg), "pending")
+	return common.FromHex(result), err
+}
+
+// SuggestGasPriceContext retrieves the currently suggested gas price to allow
+// a timely execution of a transaction.
+func (ec *Client) SuggestGasPriceContext(ctx context.Context) (*big.Int, error) {
+	var price rpc.HexNumber
+	if err := ec.c.CallContext(ctx, &price, "eth_gasPrice"); err != nil {
+		return nil, err
+	}
+	return toBig(&price), nil
+}
+
+// EstimateGas tries to estimate the gas needed to execute a specific transaction
+// based on the current pending state of the node. There is no guarantee that
+// this is the true gas limit requirement as other transactions may be added or
+// removed by miners, but it should provide a basis for setting a reasonable
+// default.
+func (ec *Client) EstimateGas(ctx context.Context, msg CallMsg) (*big.Int, error) {
+	var gas rpc.HexNumber
+	if err := ec.c.CallContext(ctx, &gas, "eth_estimateGas", toCallArg(msg)); err != nil {
+		return nil, err
+	}
+	return toBig(&gas), nil
+}
+
+// SendTransactionContext injects a signed transaction into the pending pool
+// for execution.
+func (ec *Client) SendTransactionContext(ctx context.Context, tx *types.Transaction) error {
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return err
+	}
+	return ec.c.CallContext(ctx, nil, "eth_sendRawTransaction", common.ToHex(data))
+}
+
+// bind.ContractBackend implementation
+
+// HasCode implements bind.ContractCaller, checking whether any code is deployed
+// at the contract address.
+func (ec *Client) HasCode(contract common.Address, pending bool) (bool, error) {
+	var (
+		code []byte
+		err  error
+	)
+	if pending {
+		code, err = ec.PendingCodeAt(context.Background(), contract)
+	} else {
+		code, err = ec.CodeAt(context.Background(), contract, nil)
+	}
+	return len(code) > 0, err
+}
+
+// ContractCall implements bind.ContractCaller, executing a call against the
+// latest or the pending state.
+func (ec *Client) ContractCall(contract common.Address, data []byte, pending bool) ([]byte, error) {
+	var (
+		msg = CallMsg{To: &contract, Data: data}
+		out []byte
+		err error
+	)
+	if pending {
+		out, err = ec.PendingCallContract(context.Background(), msg)
+	} else {
+		out, err = ec.CallContract(context.Background(), msg, nil)
+	}
+	if err != nil && err.Error() == bind.ErrNoCode.Error() {
+		return nil, bind.ErrNoCode
+	}
+	return out, err
+}
+
+// PendingAccountNonce implements bind.ContractTransactor, see PendingNonceAt.
+func (ec *Client) PendingAccountNonce(account common.Address) (uint64, error) {
+	return ec.PendingNonceAt(context.Background(), account)
+}
+
+// SuggestGasPrice implements bind.ContractTransactor, see
+// SuggestGasPriceContext.
+func (ec *Client) SuggestGasPrice() (*big.Int, error) {
+	return ec.SuggestGasPriceContext(context.Background())
+}
+
+// EstimateGasLimit implements bind.ContractTransactor, see EstimateGas.
+func (ec *Client) EstimateGasLimit(sender common.Address, contract *common.Address, value *big.Int, data []byte) (*big.Int, error) {
+	return ec.EstimateGas(context.Background(), CallMsg{From: sender, To: contract, Value: value, Data: data})
+}
+
+// SendTransaction implements bind.ContractTransactor, see
+// SendTransactionContext.
+func (ec *Client) SendTransaction(tx *types.Transaction) error {
+	return ec.SendTransactionContext(context.Background(), tx)
+}
+
+// Subscription represents an event subscription whose events are delivered on
+// a channel.
+type Subscription interface {
+	// Err returns the subscription error channel. It receives a value if the
+	// subscription fails and is closed when the subscription ends.
+	Err() <-chan error
+	// Unsubscribe stops the delivery of events. It can be called more than once.
+	Unsubscribe()
+}
+
+// headSubscription decodes the blocks of a newBlocks subscription into headers.
+type headSubscription struct {
+	sub       *rpc.ClientSubscription
+	err       chan error
+	quit      chan struct{}
+	unsubOnce sync.Once
+}
+
+func (s *headSubscription) Err() <-chan error {
+	return s.err
+}
+
+func (s *headSubscription) Unsubscribe() {
+	s.unsubOnce.Do(func() {
+		close(s.quit)
+		s.sub.Unsubscribe()
+	})
+}
+
+func (s *headSubscription) forward(in <-chan *rpcHeader, out chan<- *types.Header) {
+	defer close(s.err)
+	for {
+		select {
+		case head := <-in:
+			h, err := head.header()
+			if err != nil {
+				s.sub.Unsubscribe()
+				s.err <- err
+				return
+			}
+			select {
+			case out <- h:
+			case <-s.quit:
+				return
+			}
+		case err := <-s.sub.Err():
+			if err != nil {
+				s.err <- err
+			}
+			return
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// notFound reports a lookup the server answered without a result as
+// ErrNotFound.
+func notFound(err error) error {
+	if err == rpc.ErrNoResult {
+		return ErrNotFound
+	}
+	return err
+}
+
+func toBlockNumArg(number *big.Int) string {
+	if number == nil {
+		return "latest"
+	}
+	return fmt.Sprintf("%#x", number)
+}
+
+func toCallArg(msg CallMsg) interface{} {
+	arg := map[string]interface{}{
+		"from": msg.From,
+		"to":   msg.To,
+		"data": common.ToHex(msg.Data),
+	}
+	if msg.Value != nil {
+		arg["value"] = rpc.NewHexNumber(msg.Value)
+	}
+	if msg.Gas != nil {
+		arg["gas"] = rpc.NewHexNumber(msg.Gas)
+	}
+	if msg.GasPrice != nil {
+		arg["gasPrice"] = rpc.NewHexNumber(msg.GasPrice)
+	}
+	return arg
+}
+
+func toFilterArg(q FilterQuery) interface{} {
+	arg := map[string]interface{}{
+		"fromBlock": "0x0",
+		"toBlock":   toBlockNumArg(q.ToBlock),
+		"address":   q.Addresses,
+	}
+	if q.FromBlock != nil {
+		arg["fromBlock"] = toBlockNumArg(q.FromBlock)
+	}
+	if len(q.Topics) > 0 {
+		topics := make([]interface{}, len(q.Topics))
+		for i, t := range q.Topics {
+			if len(t) > 0 {
+				topics[i] = t
+			}
+		}
+		arg["topics"] = topics
+	}
+	return arg
+}
@@ -0,0 +1,415 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethclient
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/state"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/core/vm"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/ethdb"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+// TestEthService serves a single block in the format of the eth RPC API.
+type TestEthService struct {
+	block *types.Block
+}
+
+func (s *TestEthService) outputBlock(b *types.Block, fullTx bool) map[string]interface{} {
+	fields := map[string]interface{}{
+		"number":           rpc.NewHexNumber(b.Number()),
+		"hash":             b.Hash(),
+		"parentHash":       b.ParentHash(),
+		"nonce":            b.Header().Nonce,
+		"mixHash":          b.MixDigest(),
+		"sha3Uncles":       b.UncleHash(),
+		"logsBloom":        b.Bloom(),
+		"stateRoot":        b.Root(),
+		"miner":            b.Coinbase(),
+		"difficulty":       rpc.NewHexNumber(b.Difficulty()),
+		"extraData":        fmt.Sprintf("0x%x", b.Extra()),
+		"gasLimit":         rpc.NewHexNumber(b.GasLimit()),
+		"gasUsed":          rpc.NewHexNumber(b.GasUsed()),
+		"timestamp":        rpc.NewHexNumber(b.Time()),
+		"transactionsRoot": b.TxHash(),
+		"receiptsRoot":     b.ReceiptHash(),
+	}
+	if fullTx {
+		txs := make([]interface{}, len(b.Transactions()))
+		for i, tx := range b.Transactions() {
+			txs[i] = s.outputTransaction(tx, true)
+		}
+		fields["transactions"] = txs
+	}
+	uncles := make([]common.Hash, len(b.Uncles()))
+	for i, uncle := range b.Uncles() {
+		uncles[i] = uncle.Hash()
+	}
+	fields["uncles"] = uncles
+	return fields
+}
+
+func (s *TestEthService) outputTransaction(tx *types.Transaction, mined bool) map[string]interface{} {
+	v, r, sig := tx.RawSignatureValues()
+	fields := map[string]interface{}{
+		"hash":        tx.Hash(),
+		"blockNumber": nil,
+		"nonce":       rpc.NewHexNumber(tx.Nonce()),
+		"gasPrice":    rpc.NewHexNumber(tx.GasPrice()),
+		"gas":         rpc.NewHexNumber(tx.Gas()),
+		"to":          tx.To(),
+		"value":       rpc.NewHexNumber(tx.Value()),
+		"input":       fmt.Sprintf("0x%x", tx.Data()),
+		"v":           rpc.NewHexNumber(v),
+		"r":           rpc.NewHexNumber(r),
+		"s":           rpc.NewHexNumber(sig),
+	}
+	if mined {
+		fields["blockNumber"] = rpc.NewHexNumber(s.block.Number())
+	}
+	return fields
+}
+
+func (s *TestEthService) GetBlockByHash(hash common.Hash, fullTx bool) map[string]interface{} {
+	if hash != s.block.Hash() {
+		return nil
+	}
+	return s.outputBlock(s.block, fullTx)
+}
+
+func (s *TestEthService) GetBlockByNumber(number rpc.BlockNumber, fullTx bool) map[string]interface{} {
+	if number != rpc.LatestBlockNumber && number.Int64() != s.block.Number().Int64() {
+		return nil
+	}
+	return s.outputBlock(s.block, fullTx)
+}
+
+func (s *TestEthService) GetUncleByBlockHashAndIndex(hash common.Hash, index rpc.HexNumber) map[string]interface{} {
+	if hash != s.block.Hash() || index.Int() >= len(s.block.Uncles()) {
+		return nil
+	}
+	return s.outputBlock(types.NewBlockWithHeader(s.block.Uncles()[index.Int()]), false)
+}
+
+func (s *TestEthService) GetTransactionByHash(hash common.Hash) map[string]interface{} {
+	if tx := s.block.Transaction(hash); tx != nil {
+		return s.outputTransaction(tx, true)
+	}
+	return nil
+}
+
+func (s *TestEthService) GetTransactionReceipt(hash common.Hash) map[string]interface{} {
+	return map[string]interface{}{
+		"root":              "0102",
+		"transactionHash":   hash,
+		"gasUsed":           rpc.NewHexNumber(21000),
+		"cumulativeGasUsed": rpc.NewHexNumber(42000),
+		"contractAddress":   nil,
+		"logs": []*vm.Log{{
+			Address:     common.HexToAddress("0x01"),
+			Topics:      []common.Hash{common.HexToHash("0x02")},
+			Data:        []byte{3},
+			BlockNumber: 1,
+			TxHash:      hash,
+		}},
+	}
+}
+
+func (s *TestEthService) GetBalance(account common.Address, number rpc.BlockNumber) *big.Int {
+	return new(big.Int).Lsh(big.NewInt(1), 80)
+}
+
+func (s *TestEthService) GetTransactionCount(account common.Address, number rpc.BlockNumber) *rpc.HexNumber {
+	if number == rpc.PendingBlockNumber {
+		return rpc.NewHexNumber(7)
+	}
+	return rpc.NewHexNumber(5)
+}
+
+func (s *TestEthService) NewBlocks(ctx context.Context, args map[string]bool) (rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return nil, rpc.ErrNotificationsUnsupported
+	}
+	sub, err := notifier.NewSubscription(func(string) {})
+	if err != nil {
+		return nil, err
+	}
+	go sub.Notify(s.outputBlock(s.block, false))
+	return sub, nil
+}
+
+// TestProofService serves account proofs of a state in the format of the eth
+// RPC API.
+type TestProofService struct {
+	state *state.StateDB
+}
+
+func (s *TestProofService) GetProof(account common.Address, keys []string, number rpc.BlockNumber) map[string]interface{} {
+	encode := func(proof []rlp.RawValue) []string {
+		nodes := make([]string, len(proof))
+		for i, node := range proof {
+			nodes[i] = common.ToHex(node)
+		}
+		return nodes
+	}
+	storage := make([]map[string]interface{}, len(keys))
+	for i, key := range keys {
+		slot := common.HexToHash(key)
+		storage[i] = map[string]interface{}{
+			"key":   slot,
+			"value": s.state.GetState(account, slot),
+			"proof": encode(s.state.GetStorageProof(account, slot)),
+		}
+	}
+	return map[string]interface{}{
+		"address":      account,
+		"accountProof": encode(s.state.GetProof(account)),
+		"balance":      rpc.NewHexNumber(s.state.GetBalance(account)),
+		"codeHash":     s.state.GetCodeHash(account),
+		"nonce":        rpc.NewHexNumber(s.state.GetNonce(account)),
+		"storageHash":  s.state.GetStorageRoot(account),
+		"storageProof": storage,
+	}
+}
+
+func newTestClient(t *testing.T) (*Client, *types.Block) {
+	key, _ := crypto.GenerateKey()
+	tx, err := types.NewTransaction(0, common.HexToAddress("0xdead"), big.NewInt(10), big.NewInt(21000), big.NewInt(1), []byte{1, 2}).
+		WithSigner(types.NewChainIdSigner(big.NewInt(61))).SignECDSA(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	create, err := types.NewContractCreation(1, new(big.Int), big.NewInt(100000), big.NewInt(1), []byte{0x60}).SignECDSA(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncle := &types.Header{
+		Number:     big.NewInt(1),
+		Difficulty: big.NewInt(131072),
+		GasLimit:   big.NewInt(4712388),
+		GasUsed:    new(big.Int),
+		Time:       big.NewInt(1000),
+		Extra:      []byte("uncle"),
+	}
+	header := &types.Header{
+		ParentHash: common.HexToHash("0x01"),
+		Coinbase:   common.HexToAddress("0x02"),
+		Number:     big.NewInt(2),
+		Difficulty: big.NewInt(131072),
+		GasLimit:   big.NewInt(4712388),
+		GasUsed:    big.NewInt(42000),
+		Time:       big.NewInt(1010),
+		Extra:      []byte("test"),
+		MixDigest:  common.HexToHash("0x03"),
+		Nonce:      types.EncodeNonce(42),
+	}
+	block := types.NewBlock(header, []*types.Transaction{tx, create}, []*types.Header{uncle}, nil)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &TestEthService{block}); err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(rpc.DialInProc(server)), block
+}
+
+func TestBlockByHash(t *testing.T) {
+	ec, want := newTestClient(t)
+	defer ec.Close()
+
+	block, err := ec.BlockByHash(context.Background(), want.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if block.Hash() != want.Hash() {
+		t.Errorf("block hash mismatch: got %x, want %x", block.Hash(), want.Hash())
+	}
+	if len(block.Transactions()) != 2 || len(block.Uncles()) != 1 {
+		t.Fatalf("got %d transactions and %d uncles, want 2 and 1", len(block.Transactions()), len(block.Uncles()))
+	}
+	if block.Uncles()[0].Hash() != want.Uncles()[0].Hash() {
+		t.Errorf("uncle hash mismatch")
+	}
+	for i, tx := range block.Transactions() {
+		wtx := want.Transactions()[i]
+		from, err := tx.From()
+		if err != nil {
+			t.Errorf("tx %d: sender: %v", i, err)
+		}
+		if wfrom, _ := wtx.From(); from != wfrom {
+			t.Errorf("tx %d: sender mismatch: got %x, want %x", i, from, wfrom)
+		}
+	}
+	if _, err := ec.BlockByHash(context.Background(), common.Hash{}); err != ErrNotFound {
+		t.Errorf("expected %v for unknown block, got %v", ErrNotFound, err)
+	}
+}
+
+func TestHeaderByNumber(t *testing.T) {
+	ec, want := newTestClient(t)
+	defer ec.Close()
+
+	head, err := ec.HeaderByNumber(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if head.Hash() != want.Hash() {
+		t.Errorf("header hash mismatch: got %x, want %x", head.Hash(), want.Hash())
+	}
+	if _, err := ec.HeaderByNumber(context.Background(), big.NewInt(100)); err != ErrNotFound {
+		t.Errorf("expected %v for unknown header, got %v", ErrNotFound, err)
+	}
+}
+
+func TestTransactionAndReceipt(t *testing.T) {
+	ec, block := newTestClient(t)
+	defer ec.Close()
+
+	want := block.Transactions()[0]
+	tx, pending, err := ec.TransactionByHash(context.Background(), want.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx.Hash() != want.Hash() || pending {
+		t.Errorf("got tx %x (pending %v), want %x (mined)", tx.Hash(), pending, want.Hash())
+	}
+	if !tx.Protected() || tx.ChainId().Cmp(big.NewInt(61)) != 0 {
+		t.Errorf("replay protection lost: protected %v, chain id %v", tx.Protected(), tx.ChainId())
+	}
+
+	receipt, err := ec.TransactionReceipt(context.Background(), want.Hash())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if receipt.TxHash != want.Hash() || receipt.GasUsed.Int64() != 21000 || len(receipt.Logs) != 1 {
+		t.Fatalf("unexpected receipt %v", receipt)
+	}
+	if !types.BloomLookup(receipt.Bloom, common.HexToAddress("0x01").Bytes()) {
+		t.Errorf("receipt bloom doesn't contain log address")
+	}
+}
+
+func TestStateAccess(t *testing.T) {
+	ec, _ := newTestClient(t)
+	defer ec.Close()
+
+	balance, err := ec.BalanceAt(context.Background(), common.Address{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := new(big.Int).Lsh(big.NewInt(1), 80); balance.Cmp(want) != 0 {
+		t.Errorf("balance mismatch: got %v, want %v", balance, want)
+	}
+	if nonce, err := ec.NonceAt(context.Background(), common.Address{}, nil); err != nil || nonce != 5 {
+		t.Errorf("nonce: got %d (%v), want 5", nonce, err)
+	}
+	if nonce, err := ec.PendingAccountNonce(common.Address{}); err != nil || nonce != 7 {
+		t.Errorf("pending nonce: got %d (%v), want 7", nonce, err)
+	}
+}
+
+func TestSubscribeNewHead(t *testing.T) {
+	ec, want := newTestClient(t)
+	defer ec.Close()
+
+	ch := make(chan *types.Header)
+	sub, err := ec.SubscribeNewHead(context.Background(), ch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Unsubscribe()
+
+	select {
+	case head := <-ch:
+		if head.Hash() != want.Hash() {
+			t.Errorf("header hash mismatch: got %x, want %x", head.Hash(), want.Hash())
+		}
+	case err := <-sub.Err():
+		t.Fatalf("subscription failed: %v", err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("timeout waiting for new head")
+	}
+}
+
+func TestGetProof(t *testing.T) {
+	db, _ := ethdb.NewMemDatabase()
+	statedb, _ := state.New(common.Hash{}, db)
+	account := common.HexToAddress("0xdead")
+	statedb.AddBalance(account, big.NewInt(1000))
+	statedb.SetNonce(account, 3)
+	statedb.SetCode(account, []byte{0x60})
+	statedb.SetState(account, common.HexToHash("0x01"), common.HexToHash("0x02"))
+	statedb.AddBalance(common.HexToAddress("0xbeef"), big.NewInt(1))
+	root, err := statedb.Commit()
+	if err != nil {
+		t.Fatal(err)
+	}
+	statedb, _ = state.New(root, db)
+
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", &TestProofService{statedb}); err != nil {
+		t.Fatal(err)
+	}
+	ec := NewClient(rpc.DialInProc(server))
+	defer ec.Close()
+
+	header := &types.Header{Root: root}
+	keys := []common.Hash{common.HexToHash("0x01"), common.HexToHash("0x03")}
+	proof, err := ec.GetProof(context.Background(), account, keys, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := proof.Verify(header); err != nil {
+		t.Fatalf("proof verification failed: %v", err)
+	}
+	if proof.Balance.Int64() != 1000 || proof.Nonce != 3 || proof.StorageProof[0].Value != common.HexToHash("0x02") {
+		t.Errorf("unexpected proof content: %+v", proof)
+	}
+
+	// Verification must fail for tampered values and other state roots
+	proof.Balance = big.NewInt(1001)
+	if err := proof.Verify(header); err == nil {
+		t.Error("verification succeeded for a tampered balance")
+	}
+	proof.Balance = big.NewInt(1000)
+	proof.StorageProof[1].Value = common.HexToHash("0x04")
+	if err := proof.Verify(header); err == nil {
+		t.Error("verification succeeded for a tampered storage slot")
+	}
+	proof.StorageProof[1].Value = common.Hash{}
+	if err := proof.Verify(&types.Header{Root: common.HexToHash("0x01")}); err == nil {
+		t.Error("verification succeeded against another state root")
+	}
+
+	// Missing accounts are proven absent
+	missing, err := ec.GetProof(context.Background(), common.HexToAddress("0xcafe"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := missing.Verify(header); err != nil {
+		t.Errorf("absence proof verification failed: %v", err)
+	}
+}
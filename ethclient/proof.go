@@ -0,0 +1,90 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethclient
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/state"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/rlp"
+)
+
+// AccountProof is the merkle proof of an account and of some of its storage
+// slots in the state trie of a block.
+type AccountProof struct {
+	Address      common.Address
+	Balance      *big.Int
+	Nonce        uint64
+	CodeHash     common.Hash
+	StorageHash  common.Hash
+	AccountProof []rlp.RawValue
+	StorageProof []StorageProof
+}
+
+// StorageProof is the merkle proof of a storage slot in the storage trie of
+// an account.
+type StorageProof struct {
+	Key   common.Hash
+	Value common.Hash
+	Proof []rlp.RawValue
+}
+
+// Verify checks the proofs against the state root of the given header, which
+// must be the header of the block the proof was retrieved for. It returns an
+// error unless the proofs show that the account and its storage slots hold
+// exactly the reported values.
+func (p *AccountProof) Verify(header *types.Header) error {
+	account, err := state.VerifyProof(header.Root, p.Address, p.AccountProof)
+	if err != nil {
+		return fmt.Errorf("invalid account proof: %v", err)
+	}
+	if account == nil {
+		// The account doesn't exist, neither do its balance and storage
+		if p.Balance.Sign() != 0 || p.Nonce != 0 {
+			return fmt.Errorf("account %x doesn't exist", p.Address)
+		}
+		for _, s := range p.StorageProof {
+			if s.Value != (common.Hash{}) {
+				return fmt.Errorf("storage slot %x of missing account %x isn't empty", s.Key, p.Address)
+			}
+		}
+		return nil
+	}
+	switch {
+	case account.Balance.Cmp(p.Balance) != 0:
+		return fmt.Errorf("balance mismatch: proven %v, reported %v", account.Balance, p.Balance)
+	case account.Nonce != p.Nonce:
+		return fmt.Errorf("nonce mismatch: proven %d, reported %d", account.Nonce, p.Nonce)
+	case common.BytesToHash(account.CodeHash) != p.CodeHash:
+		return fmt.Errorf("code hash mismatch: proven %x, reported %x", account.CodeHash, p.CodeHash)
+	case account.Root != p.StorageHash:
+		return fmt.Errorf("storage hash mismatch: proven %x, reported %x", account.Root, p.StorageHash)
+	}
+	for _, s := range p.StorageProof {
+		value, err := state.VerifyStorageProof(account.Root, s.Key, s.Proof)
+		if err != nil {
+			return fmt.Errorf("invalid proof of storage slot %x: %v", s.Key, err)
+		}
+		if value != s.Value {
+			return fmt.Errorf("storage slot %x mismatch: proven %x, reported %x", s.Key, value, s.Value)
+		}
+	}
+	return nil
+}
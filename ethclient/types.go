@@ -0,0 +1,241 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package ethclient
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/core/vm"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+var errMissingSignature = errors.New("server returned transaction without signature")
+
+// rpcHeader is the RPC representation of a block, without its body.
+type rpcHeader struct {
+	Hash        common.Hash    `json:"hash"`
+	ParentHash  common.Hash    `json:"parentHash"`
+	UncleHash   common.Hash    `json:"sha3Uncles"`
+	Coinbase    common.Address `json:"miner"`
+	Root        common.Hash    `json:"stateRoot"`
+	TxHash      common.Hash    `json:"transactionsRoot"`
+	ReceiptHash common.Hash    `json:"receiptsRoot"`
+	Bloom       string         `json:"logsBloom"`
+	Difficulty  *rpc.HexNumber `json:"difficulty"`
+	Number      *rpc.HexNumber `json:"number"`
+	GasLimit    *rpc.HexNumber `json:"gasLimit"`
+	GasUsed     *rpc.HexNumber `json:"gasUsed"`
+	Time        *rpc.HexNumber `json:"timestamp"`
+	Extra       string         `json:"extraData"`
+	MixDigest   common.Hash    `json:"mixHash"`
+	Nonce       string         `json:"nonce"`
+}
+
+// header converts the RPC representation into a header, verifying that it
+// hashes to the hash reported by the server.
+func (h *rpcHeader) header() (*types.Header, error) {
+	head := &types.Header{
+		ParentHash:  h.ParentHash,
+		UncleHash:   h.UncleHash,
+		Coinbase:    h.Coinbase,
+		Root:        h.Root,
+		TxHash:      h.TxHash,
+		ReceiptHash: h.ReceiptHash,
+		Bloom:       types.BytesToBloom(common.FromHex(h.Bloom)),
+		Difficulty:  toBig(h.Difficulty),
+		Number:      toBig(h.Number),
+		GasLimit:    toBig(h.GasLimit),
+		GasUsed:     toBig(h.GasUsed),
+		Time:        toBig(h.Time),
+		Extra:       common.FromHex(h.Extra),
+		MixDigest:   h.MixDigest,
+	}
+	copy(head.Nonce[:], common.FromHex(h.Nonce))
+
+	if hash := head.Hash(); hash != h.Hash {
+		return nil, fmt.Errorf("header hash mismatch: server reported %x, got %x", h.Hash, hash)
+	}
+	return head, nil
+}
+
+// rpcTransaction is the RPC representation of a transaction.
+type rpcTransaction struct {
+	Hash        common.Hash     `json:"hash"`
+	BlockNumber *rpc.HexNumber  `json:"blockNumber"` // nil for pending transactions
+	Nonce       *rpc.HexNumber  `json:"nonce"`
+	GasPrice    *rpc.HexNumber  `json:"gasPrice"`
+	Gas         *rpc.HexNumber  `json:"gas"`
+	To          *common.Address `json:"to"`
+	Value       *rpc.HexNumber  `json:"value"`
+	Input       string          `json:"input"`
+	V           *rpc.HexNumber  `json:"v"`
+	R           *rpc.HexNumber  `json:"r"`
+	S           *rpc.HexNumber  `json:"s"`
+}
+
+// txFields mirrors the consensus encoding of a transaction.
+type txFields struct {
+	AccountNonce    uint64
+	Price, GasLimit *big.Int
+	Recipient       *common.Address `rlp:"nil"`
+	Amount          *big.Int
+	Payload         []byte
+	V, R, S         *big.Int
+}
+
+// transaction converts the RPC representation into a signed transaction,
+// verifying that it hashes to the hash reported by the server. The transaction
+// is decoded from its consensus encoding, which sets it up with the signer
+// matching its signature.
+func (tx *rpcTransaction) transaction() (*types.Transaction, error) {
+	if tx.V == nil || tx.R == nil || tx.S == nil {
+		return nil, errMissingSignature
+	}
+	enc, err := rlp.EncodeToBytes(&txFields{
+		AccountNonce: toBig(tx.Nonce).Uint64(),
+		Price:        toBig(tx.GasPrice),
+		GasLimit:     toBig(tx.Gas),
+		Recipient:    tx.To,
+		Amount:       toBig(tx.Value),
+		Payload:      common.FromHex(tx.Input),
+		V:            toBig(tx.V),
+		R:            toBig(tx.R),
+		S:            toBig(tx.S),
+	})
+	if err != nil {
+		return nil, err
+	}
+	t := new(types.Transaction)
+	if err := rlp.DecodeBytes(enc, t); err != nil {
+		return nil, err
+	}
+	if hash := t.Hash(); hash != tx.Hash {
+		return nil, fmt.Errorf("transaction hash mismatch: server reported %x, got %x", tx.Hash, hash)
+	}
+	return t, nil
+}
+
+// rpcReceipt is the RPC representation of a transaction receipt.
+type rpcReceipt struct {
+	Root              string          `json:"root"`
+	TxHash            common.Hash     `json:"transactionHash"`
+	ContractAddress   *common.Address `json:"contractAddress"`
+	GasUsed           *rpc.HexNumber  `json:"gasUsed"`
+	CumulativeGasUsed *rpc.HexNumber  `json:"cumulativeGasUsed"`
+	Logs              []*rpcLog       `json:"logs"`
+}
+
+func (r *rpcReceipt) receipt() *types.Receipt {
+	receipt := &types.Receipt{
+		PostState:         common.FromHex(r.Root),
+		CumulativeGasUsed: toBig(r.CumulativeGasUsed),
+		Logs:              make(vm.Logs, len(r.Logs)),
+		TxHash:            r.TxHash,
+		GasUsed:           toBig(r.GasUsed),
+	}
+	for i, l := range r.Logs {
+		receipt.Logs[i] = l.log()
+	}
+	if r.ContractAddress != nil {
+		receipt.ContractAddress = *r.ContractAddress
+	}
+	// The bloom isn't part of the RPC output, but derives from the logs.
+	receipt.Bloom = types.BytesToBloom(types.LogsBloom(receipt.Logs).Bytes())
+	return receipt
+}
+
+// rpcLog is the RPC representation of a log entry.
+type rpcLog struct {
+	Address     common.Address `json:"address"`
+	Topics      []common.Hash  `json:"topics"`
+	Data        string         `json:"data"`
+	BlockNumber *rpc.HexNumber `json:"blockNumber"`
+	TxHash      common.Hash    `json:"transactionHash"`
+	TxIndex     *rpc.HexNumber `json:"transactionIndex"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	Index       *rpc.HexNumber `json:"logIndex"`
+}
+
+func (l *rpcLog) log() *vm.Log {
+	return &vm.Log{
+		Address:     l.Address,
+		Topics:      l.Topics,
+		Data:        common.FromHex(l.Data),
+		BlockNumber: toBig(l.BlockNumber).Uint64(),
+		TxHash:      l.TxHash,
+		TxIndex:     uint(toBig(l.TxIndex).Uint64()),
+		BlockHash:   l.BlockHash,
+		Index:       uint(toBig(l.Index).Uint64()),
+	}
+}
+
+// rpcAccountResult is the RPC representation of an account proof.
+type rpcAccountResult struct {
+	Address      common.Address     `json:"address"`
+	AccountProof []string           `json:"accountProof"`
+	Balance      *rpc.HexNumber     `json:"balance"`
+	CodeHash     common.Hash        `json:"codeHash"`
+	Nonce        *rpc.HexNumber     `json:"nonce"`
+	StorageHash  common.Hash        `json:"storageHash"`
+	StorageProof []rpcStorageResult `json:"storageProof"`
+}
+
+// rpcStorageResult is the RPC representation of a storage slot proof.
+type rpcStorageResult struct {
+	Key   common.Hash `json:"key"`
+	Value common.Hash `json:"value"`
+	Proof []string    `json:"proof"`
+}
+
+// proof converts the RPC representation into an account proof.
+func (r *rpcAccountResult) proof() *AccountProof {
+	p := &AccountProof{
+		Address:      r.Address,
+		Balance:      toBig(r.Balance),
+		Nonce:        toBig(r.Nonce).Uint64(),
+		CodeHash:     r.CodeHash,
+		StorageHash:  r.StorageHash,
+		AccountProof: toProof(r.AccountProof),
+		StorageProof: make([]StorageProof, len(r.StorageProof)),
+	}
+	for i, s := range r.StorageProof {
+		p.StorageProof[i] = StorageProof{Key: s.Key, Value: s.Value, Proof: toProof(s.Proof)}
+	}
+	return p
+}
+
+// toProof decodes the hex encoded nodes of a merkle proof.
+func toProof(nodes []string) []rlp.RawValue {
+	proof := make([]rlp.RawValue, len(nodes))
+	for i, node := range nodes {
+		proof[i] = common.FromHex(node)
+	}
+	return proof
+}
+
+// toBig copies a number returned by the server, treating null as zero.
+func toBig(n *rpc.HexNumber) *big.Int {
+	if n == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set((*big.Int)(n))
+}
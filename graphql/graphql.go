@@ -0,0 +1,548 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package graphql provides a GraphQL interface to the chain data of a node.
+package graphql
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/state"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/ethdb"
+	"github.com/ethereumproject/go-ethereum/miner"
+)
+
+const (
+	// maxBlockRange is the maximum number of blocks returned by a single
+	// blocks query.
+	maxBlockRange = 1024
+
+	// maxQueryDepth is the maximum nesting of the fields selected by a query,
+	// bounding the work the cyclic schema lets a single query request.
+	maxQueryDepth = 10
+)
+
+var errStateUnavailable = errors.New("state not available")
+
+// Resolver is the root resolver of the GraphQL schema. It resolves queries
+// directly against the blockchain, the transaction pool, the pending block of
+// the miner and the chain database of a node.
+type Resolver struct {
+	chain   *core.BlockChain
+	txPool  *core.TxPool
+	miner   *miner.Miner
+	chainDb ethdb.Database
+}
+
+// Block resolves a block by number or by hash, defaulting to the current
+// head of the chain.
+func (r *Resolver) Block(args struct {
+	Number *Long
+	Hash   *Bytes32
+}) (*Block, error) {
+	var block *types.Block
+	switch {
+	case args.Number != nil && args.Hash != nil:
+		return nil, errors.New("only one of number or hash may be specified")
+	case args.Number != nil:
+		block = r.chain.GetBlockByNumber(uint64(*args.Number))
+	case args.Hash != nil:
+		block = r.chain.GetBlock(args.Hash.Hash)
+	default:
+		block = r.chain.CurrentBlock()
+	}
+	if block == nil {
+		return nil, nil
+	}
+	return &Block{r: r, block: block}, nil
+}
+
+// Blocks resolves the canonical blocks within a range of numbers.
+func (r *Resolver) Blocks(args struct {
+	From Long
+	To   *Long
+}) ([]*Block, error) {
+	to := r.chain.CurrentBlock().NumberU64()
+	if args.To != nil && uint64(*args.To) < to {
+		to = uint64(*args.To)
+	}
+	from := uint64(args.From)
+	if from > to {
+		return []*Block{}, nil
+	}
+	if to-from >= maxBlockRange {
+		return nil, fmt.Errorf("block range too large (%d>%d)", to-from+1, maxBlockRange)
+	}
+	blocks := make([]*Block, 0, to-from+1)
+	for number := from; number <= to; number++ {
+		block := r.chain.GetBlockByNumber(number)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, &Block{r: r, block: block})
+	}
+	return blocks, nil
+}
+
+// Pending resolves the pending state of the transaction pool.
+func (r *Resolver) Pending() *Pending {
+	return &Pending{r: r}
+}
+
+// Transaction resolves a mined or pending transaction by its hash.
+func (r *Resolver) Transaction(args struct{ Hash Bytes32 }) *Transaction {
+	if tx, blockHash, _, index := core.GetTransaction(r.chainDb, args.Hash.Hash); tx != nil {
+		if block := r.chain.GetBlock(blockHash); block != nil {
+			return &Transaction{r: r, tx: tx, block: &Block{r: r, block: block}, index: int(index)}
+		}
+	}
+	if tx := r.txPool.GetTransaction(args.Hash.Hash); tx != nil {
+		return &Transaction{r: r, tx: tx}
+	}
+	return nil
+}
+
+// Account resolves an account at the state with the given root, or at the
+// pending state if the root is empty.
+type Account struct {
+	r       *Resolver
+	address common.Address
+	root    common.Hash
+
+	lock  sync.Mutex // state databases are not safe for concurrent use
+	state *state.StateDB
+}
+
+// getState opens the state of the account, lazily as not all queries need it.
+// The caller must hold the lock.
+func (a *Account) getState() (*state.StateDB, error) {
+	if a.state != nil {
+		return a.state, nil
+	}
+	if a.root == (common.Hash{}) {
+		// Pending state is only known by the miner
+		_, a.state = a.r.miner.Pending()
+		return a.state, nil
+	}
+	var err error
+	if a.state, err = a.r.chain.StateAt(a.root); err != nil {
+		return nil, errStateUnavailable
+	}
+	return a.state, nil
+}
+
+func (a *Account) Address() Address {
+	return Address{a.address}
+}
+
+func (a *Account) Balance() (BigInt, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	statedb, err := a.getState()
+	if err != nil {
+		return BigInt{}, err
+	}
+	return BigInt{statedb.GetBalance(a.address)}, nil
+}
+
+func (a *Account) TransactionCount() (Long, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	statedb, err := a.getState()
+	if err != nil {
+		return 0, err
+	}
+	return Long(statedb.GetNonce(a.address)), nil
+}
+
+func (a *Account) Code() (Bytes, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	statedb, err := a.getState()
+	if err != nil {
+		return nil, err
+	}
+	return Bytes(statedb.GetCode(a.address)), nil
+}
+
+func (a *Account) Storage(args struct{ Slot Bytes32 }) (Bytes32, error) {
+	a.lock.Lock()
+	defer a.lock.Unlock()
+
+	statedb, err := a.getState()
+	if err != nil {
+		return Bytes32{}, err
+	}
+	return Bytes32{statedb.GetState(a.address, args.Slot.Hash)}, nil
+}
+
+// Block resolves a block of the chain.
+type Block struct {
+	r     *Resolver
+	block *types.Block
+
+	receiptsOnce sync.Once
+	receipts     types.Receipts
+}
+
+// getReceipts retrieves the receipts of the transactions in the block.
+func (b *Block) getReceipts() types.Receipts {
+	b.receiptsOnce.Do(func() {
+		b.receipts = core.GetBlockReceipts(b.r.chainDb, b.block.Hash())
+	})
+	return b.receipts
+}
+
+func (b *Block) Number() Long {
+	return Long(b.block.NumberU64())
+}
+
+func (b *Block) Hash() Bytes32 {
+	return Bytes32{b.block.Hash()}
+}
+
+func (b *Block) Parent() *Block {
+	if b.block.NumberU64() == 0 {
+		return nil
+	}
+	parent := b.r.chain.GetBlock(b.block.ParentHash())
+	if parent == nil {
+		return nil
+	}
+	return &Block{r: b.r, block: parent}
+}
+
+func (b *Block) Nonce() Bytes {
+	nonce := b.block.Header().Nonce
+	return Bytes(nonce[:])
+}
+
+func (b *Block) TransactionsRoot() Bytes32 {
+	return Bytes32{b.block.TxHash()}
+}
+
+func (b *Block) TransactionCount() int32 {
+	return int32(len(b.block.Transactions()))
+}
+
+func (b *Block) StateRoot() Bytes32 {
+	return Bytes32{b.block.Root()}
+}
+
+func (b *Block) ReceiptsRoot() Bytes32 {
+	return Bytes32{b.block.ReceiptHash()}
+}
+
+func (b *Block) Miner() *Account {
+	return b.account(b.block.Coinbase())
+}
+
+func (b *Block) ExtraData() Bytes {
+	return Bytes(b.block.Extra())
+}
+
+func (b *Block) GasLimit() Long {
+	return newLong(b.block.GasLimit())
+}
+
+func (b *Block) GasUsed() Long {
+	return newLong(b.block.GasUsed())
+}
+
+func (b *Block) Timestamp() Long {
+	return newLong(b.block.Time())
+}
+
+func (b *Block) LogsBloom() Bytes {
+	return Bytes(b.block.Bloom().Bytes())
+}
+
+func (b *Block) MixHash() Bytes32 {
+	return Bytes32{b.block.MixDigest()}
+}
+
+func (b *Block) Difficulty() BigInt {
+	return BigInt{b.block.Difficulty()}
+}
+
+func (b *Block) TotalDifficulty() (BigInt, error) {
+	td := b.r.chain.GetTd(b.block.Hash())
+	if td == nil {
+		return BigInt{}, fmt.Errorf("total difficulty of block %x not found", b.block.Hash())
+	}
+	return BigInt{td}, nil
+}
+
+func (b *Block) OmmerCount() int32 {
+	return int32(len(b.block.Uncles()))
+}
+
+func (b *Block) OmmerHash() Bytes32 {
+	return Bytes32{b.block.UncleHash()}
+}
+
+func (b *Block) Transactions() []*Transaction {
+	txs := b.block.Transactions()
+	ret := make([]*Transaction, len(txs))
+	for i, tx := range txs {
+		ret[i] = &Transaction{r: b.r, tx: tx, block: b, index: i}
+	}
+	return ret
+}
+
+func (b *Block) TransactionAt(args struct{ Index int32 }) *Transaction {
+	txs := b.block.Transactions()
+	if args.Index < 0 || int(args.Index) >= len(txs) {
+		return nil
+	}
+	return &Transaction{r: b.r, tx: txs[args.Index], block: b, index: int(args.Index)}
+}
+
+func (b *Block) Logs() []*Log {
+	logs := []*Log{}
+	for _, tx := range b.Transactions() {
+		logs = append(logs, tx.logs()...)
+	}
+	return logs
+}
+
+func (b *Block) Account(args struct{ Address Address }) *Account {
+	return b.account(args.Address.Address)
+}
+
+// account resolves an account at the state after the block.
+func (b *Block) account(address common.Address) *Account {
+	return &Account{r: b.r, address: address, root: b.block.Root()}
+}
+
+// Transaction resolves a transaction, which is pending if it has no block.
+type Transaction struct {
+	r     *Resolver
+	tx    *types.Transaction
+	block *Block
+	index int
+}
+
+// getReceipt retrieves the receipt of a mined transaction.
+func (t *Transaction) getReceipt() *types.Receipt {
+	if t.block == nil {
+		return nil
+	}
+	receipts := t.block.getReceipts()
+	if t.index >= len(receipts) {
+		return nil
+	}
+	return receipts[t.index]
+}
+
+// account resolves an account at the state after the block of the
+// transaction, or at the pending state if it has not been mined yet.
+func (t *Transaction) account(address common.Address) *Account {
+	if t.block == nil {
+		return &Account{r: t.r, address: address}
+	}
+	return t.block.account(address)
+}
+
+func (t *Transaction) Hash() Bytes32 {
+	return Bytes32{t.tx.Hash()}
+}
+
+func (t *Transaction) Nonce() Long {
+	return Long(t.tx.Nonce())
+}
+
+func (t *Transaction) Index() *int32 {
+	if t.block == nil {
+		return nil
+	}
+	index := int32(t.index)
+	return &index
+}
+
+func (t *Transaction) From() (*Account, error) {
+	var signer types.Signer = types.BasicSigner{}
+	if t.tx.Protected() {
+		signer = types.NewChainIdSigner(t.tx.ChainId())
+	}
+	from, err := types.Sender(signer, t.tx)
+	if err != nil {
+		return nil, err
+	}
+	return t.account(from), nil
+}
+
+func (t *Transaction) To() *Account {
+	to := t.tx.To()
+	if to == nil {
+		return nil
+	}
+	return t.account(*to)
+}
+
+func (t *Transaction) Value() BigInt {
+	return BigInt{t.tx.Value()}
+}
+
+func (t *Transaction) GasPrice() BigInt {
+	return BigInt{t.tx.GasPrice()}
+}
+
+func (t *Transaction) Gas() Long {
+	return newLong(t.tx.Gas())
+}
+
+func (t *Transaction) InputData() Bytes {
+	return Bytes(t.tx.Data())
+}
+
+func (t *Transaction) Block() *Block {
+	return t.block
+}
+
+func (t *Transaction) Root() *Bytes32 {
+	receipt := t.getReceipt()
+	if receipt == nil {
+		return nil
+	}
+	return &Bytes32{common.BytesToHash(receipt.PostState)}
+}
+
+func (t *Transaction) GasUsed() *Long {
+	receipt := t.getReceipt()
+	if receipt == nil {
+		return nil
+	}
+	gas := newLong(receipt.GasUsed)
+	return &gas
+}
+
+func (t *Transaction) CumulativeGasUsed() *Long {
+	receipt := t.getReceipt()
+	if receipt == nil {
+		return nil
+	}
+	gas := newLong(receipt.CumulativeGasUsed)
+	return &gas
+}
+
+func (t *Transaction) CreatedContract() *Account {
+	receipt := t.getReceipt()
+	if receipt == nil || t.tx.To() != nil {
+		return nil
+	}
+	return t.account(receipt.ContractAddress)
+}
+
+func (t *Transaction) Logs() *[]*Log {
+	if t.getReceipt() == nil {
+		return nil
+	}
+	logs := t.logs()
+	return &logs
+}
+
+// logs resolves the logs of a mined transaction.
+func (t *Transaction) logs() []*Log {
+	receipt := t.getReceipt()
+	if receipt == nil {
+		return nil
+	}
+	logs := make([]*Log, len(receipt.Logs))
+	for i, log := range receipt.Logs {
+		logs[i] = &Log{tx: t, address: log.Address, topics: log.Topics, data: log.Data, index: log.Index}
+	}
+	return logs
+}
+
+func (t *Transaction) R() BigInt {
+	_, r, _ := t.tx.RawSignatureValues()
+	return BigInt{r}
+}
+
+func (t *Transaction) S() BigInt {
+	_, _, s := t.tx.RawSignatureValues()
+	return BigInt{s}
+}
+
+func (t *Transaction) V() BigInt {
+	v, _, _ := t.tx.RawSignatureValues()
+	return BigInt{v}
+}
+
+// Log resolves a log emitted by a mined transaction.
+type Log struct {
+	tx      *Transaction
+	address common.Address
+	topics  []common.Hash
+	data    []byte
+	index   uint
+}
+
+func (l *Log) Index() int32 {
+	return int32(l.index)
+}
+
+func (l *Log) Account() *Account {
+	return l.tx.account(l.address)
+}
+
+func (l *Log) Topics() []Bytes32 {
+	topics := make([]Bytes32, len(l.topics))
+	for i, topic := range l.topics {
+		topics[i] = Bytes32{topic}
+	}
+	return topics
+}
+
+func (l *Log) Data() Bytes {
+	return Bytes(l.data)
+}
+
+func (l *Log) Transaction() *Transaction {
+	return l.tx
+}
+
+// Pending resolves the pending state of the transaction pool.
+type Pending struct {
+	r *Resolver
+}
+
+func (p *Pending) TransactionCount() int32 {
+	pending, _ := p.r.txPool.Stats()
+	return int32(pending)
+}
+
+func (p *Pending) Transactions() []*Transaction {
+	txs := p.r.txPool.GetTransactions()
+	ret := make([]*Transaction, len(txs))
+	for i, tx := range txs {
+		ret[i] = &Transaction{r: p.r, tx: tx}
+	}
+	return ret
+}
+
+func (p *Pending) Account(args struct{ Address Address }) *Account {
+	return &Account{r: p.r, address: args.Address.Address}
+}
@@ -0,0 +1,294 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/ethdb"
+	"github.com/ethereumproject/go-ethereum/event"
+	"github.com/ethereumproject/go-ethereum/miner"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+	testBalance = new(big.Int).Mul(big.NewInt(1000), common.Ether)
+)
+
+// testBackend implements core.Backend for the miner resolving the pending
+// state.
+type testBackend struct {
+	am      *accounts.Manager
+	chain   *core.BlockChain
+	txPool  *core.TxPool
+	chainDb ethdb.Database
+	mux     *event.TypeMux
+}
+
+func (b *testBackend) AccountManager() *accounts.Manager { return b.am }
+func (b *testBackend) BlockChain() *core.BlockChain      { return b.chain }
+func (b *testBackend) TxPool() *core.TxPool              { return b.txPool }
+func (b *testBackend) ChainDb() ethdb.Database           { return b.chainDb }
+func (b *testBackend) DappDb() ethdb.Database            { return b.chainDb }
+func (b *testBackend) EventMux() *event.TypeMux          { return b.mux }
+
+// newTestServer creates a GraphQL endpoint over a chain of three blocks, the
+// second one holding a transfer, and a pending transfer in the pool and the
+// pending block of the miner.
+func newTestServer(t *testing.T) (*httptest.Server, *types.Transaction, *types.Transaction, func()) {
+	dir, err := ioutil.TempDir("", "graphql-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	am, err := accounts.NewManager(dir, accounts.LightScryptN, accounts.LightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := ethdb.NewMemDatabase()
+	genesis := core.WriteGenesisBlockForTesting(db, core.GenesisAccount{Address: testAddress, Balance: testBalance})
+
+	mux := new(event.TypeMux)
+	config := core.MakeChainConfig()
+	chain, err := core.NewBlockChain(db, config, core.FakePow{}, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mined, _ := types.NewTransaction(0, common.Address{1}, big.NewInt(1000), big.NewInt(21000), big.NewInt(1), nil).SignECDSA(testKey)
+	blocks, _ := core.GenerateChain(config, genesis, db, 3, func(i int, b *core.BlockGen) {
+		if i == 1 {
+			b.AddTx(mined)
+		}
+	})
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatal(err)
+	}
+
+	pool := core.NewTxPool(config, mux, chain.State, chain.GasLimit)
+	pending, _ := types.NewTransaction(1, common.Address{2}, big.NewInt(2000), big.NewInt(21000), big.NewInt(1), nil).SignECDSA(testKey)
+	if err := pool.Add(pending); err != nil {
+		t.Fatal(err)
+	}
+
+	backend := &testBackend{am: am, chain: chain, txPool: pool, chainDb: db, mux: mux}
+	handler, err := NewHandler(chain, pool, miner.New(backend, config, mux, core.FakePow{}), db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(handler)
+	return server, mined, pending, func() {
+		server.Close()
+		mux.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+// post executes a GraphQL query and returns the data and errors of the
+// response.
+func post(t *testing.T, server *httptest.Server, q string) (json.RawMessage, []string) {
+	body, _ := json.Marshal(map[string]string{"query": q})
+	resp, err := http.Post(server.URL, "application/json", strings.NewReader(string(body)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var response struct {
+		Data   json.RawMessage
+		Errors []struct{ Message string }
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	var errs []string
+	for _, e := range response.Errors {
+		errs = append(errs, e.Message)
+	}
+	return response.Data, errs
+}
+
+// query executes a GraphQL query and decodes its data into result.
+func query(t *testing.T, server *httptest.Server, q string, result interface{}) {
+	data, errs := post(t, server, q)
+	if len(errs) > 0 {
+		t.Fatalf("query %s failed: %v", q, errs)
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestGraphQLBlock(t *testing.T) {
+	server, tx, _, teardown := newTestServer(t)
+	defer teardown()
+
+	var result struct {
+		Block struct {
+			Number           uint64
+			TransactionCount int
+			Parent           struct{ Number uint64 }
+			Transactions     []struct {
+				Hash    string
+				Index   int
+				From    struct{ Address string }
+				To      struct{ Address, Balance string }
+				Value   string
+				GasUsed uint64
+			}
+			Account struct {
+				Balance          string
+				TransactionCount uint64
+			}
+		}
+	}
+	query(t, server, `{ block(number: 2) { number transactionCount parent { number }
+		transactions { hash index from { address } to { address balance } value gasUsed }
+		account(address: "`+testAddress.Hex()+`") { balance transactionCount } } }`, &result)
+
+	block := result.Block
+	if block.Number != 2 || block.Parent.Number != 1 || block.TransactionCount != 1 {
+		t.Fatalf("block mismatch: %+v", block)
+	}
+	mined := block.Transactions[0]
+	if mined.Hash != tx.Hash().Hex() || mined.Index != 0 || mined.GasUsed != 21000 {
+		t.Errorf("transaction mismatch: %+v", mined)
+	}
+	if mined.From.Address != testAddress.Hex() || mined.To.Address != (common.Address{1}).Hex() {
+		t.Errorf("transaction accounts mismatch: %+v", mined)
+	}
+	if mined.Value != "0x3e8" || mined.To.Balance != "0x3e8" {
+		t.Errorf("value mismatch: have %s, balance %s, want 0x3e8", mined.Value, mined.To.Balance)
+	}
+	if block.Account.TransactionCount != 1 {
+		t.Errorf("account nonce mismatch: have %d, want 1", block.Account.TransactionCount)
+	}
+}
+
+func TestGraphQLTransactionAndPending(t *testing.T) {
+	server, mined, pending, teardown := newTestServer(t)
+	defer teardown()
+
+	var result struct {
+		Mined struct {
+			Block struct{ Number uint64 }
+			Index *int
+		}
+		Queued struct {
+			Block *struct{ Number uint64 }
+			Index *int
+			Nonce uint64
+		}
+		Pending struct {
+			TransactionCount int
+			Transactions     []struct{ Hash string }
+			Account          struct{ TransactionCount uint64 }
+		}
+	}
+	query(t, server, `{
+		mined: transaction(hash: "`+mined.Hash().Hex()+`") { block { number } index }
+		queued: transaction(hash: "`+pending.Hash().Hex()+`") { block { number } index nonce }
+		pending { transactionCount transactions { hash } account(address: "`+testAddress.Hex()+`") { transactionCount } } }`, &result)
+
+	if result.Mined.Block.Number != 2 || result.Mined.Index == nil || *result.Mined.Index != 0 {
+		t.Errorf("mined transaction mismatch: %+v", result.Mined)
+	}
+	if result.Queued.Block != nil || result.Queued.Index != nil || result.Queued.Nonce != 1 {
+		t.Errorf("pending transaction mismatch: %+v", result.Queued)
+	}
+	if result.Pending.TransactionCount != 1 || len(result.Pending.Transactions) != 1 || result.Pending.Transactions[0].Hash != pending.Hash().Hex() {
+		t.Errorf("pending state mismatch: %+v", result.Pending)
+	}
+	if result.Pending.Account.TransactionCount != 2 {
+		t.Errorf("pending nonce mismatch: have %d, want 2", result.Pending.Account.TransactionCount)
+	}
+}
+
+func TestGraphQLBlocks(t *testing.T) {
+	server, _, _, teardown := newTestServer(t)
+	defer teardown()
+
+	var result struct {
+		Blocks []struct{ Number uint64 }
+	}
+	query(t, server, `{ blocks(from: 1) { number } }`, &result)
+	if len(result.Blocks) != 3 || result.Blocks[0].Number != 1 || result.Blocks[2].Number != 3 {
+		t.Errorf("blocks mismatch: %+v", result.Blocks)
+	}
+}
+
+func TestGraphQLPendingState(t *testing.T) {
+	server, _, pending, teardown := newTestServer(t)
+	defer teardown()
+
+	var result struct {
+		Block struct {
+			Account struct{ Balance string }
+		}
+		Pending struct {
+			Account struct{ Balance string }
+		}
+		Transaction struct {
+			To struct{ Balance string }
+		}
+	}
+	query(t, server, `{
+		block { account(address: "`+testAddress.Hex()+`") { balance } }
+		pending { account(address: "`+testAddress.Hex()+`") { balance } }
+		transaction(hash: "`+pending.Hash().Hex()+`") { to { balance } } }`, &result)
+
+	head := new(big.Int).Sub(testBalance, big.NewInt(1000+21000))
+	if have, want := result.Block.Account.Balance, "0x"+head.Text(16); have != want {
+		t.Errorf("head balance mismatch: have %s, want %s", have, want)
+	}
+	if have, want := result.Pending.Account.Balance, "0x"+new(big.Int).Sub(head, big.NewInt(2000+21000)).Text(16); have != want {
+		t.Errorf("pending balance mismatch: have %s, want %s", have, want)
+	}
+	if have, want := result.Transaction.To.Balance, "0x7d0"; have != want {
+		t.Errorf("pending recipient balance mismatch: have %s, want %s", have, want)
+	}
+}
+
+func TestGraphQLMaxDepth(t *testing.T) {
+	server, _, _, teardown := newTestServer(t)
+	defer teardown()
+
+	// nested selects the number of an ancestor of the head, depth fields deep
+	nested := func(depth int) string {
+		q := "number"
+		for i := 2; i < depth; i++ {
+			q = "parent { " + q + " }"
+		}
+		return "{ block { " + q + " } }"
+	}
+	var result struct{ Block json.RawMessage }
+	query(t, server, nested(maxQueryDepth), &result)
+	if _, errs := post(t, server, nested(maxQueryDepth+1)); len(errs) == 0 {
+		t.Errorf("no error for query nested deeper than %d fields", maxQueryDepth)
+	}
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"net/http"
+
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/ethdb"
+	"github.com/ethereumproject/go-ethereum/miner"
+	"github.com/ethereumproject/go-ethereum/p2p"
+	"github.com/ethereumproject/go-ethereum/rpc"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+)
+
+// Path is the URL path under which the GraphQL endpoint is served on the HTTP
+// RPC listener of the node.
+const Path = "/graphql"
+
+// Service is a node service serving GraphQL queries over the chain data next
+// to the HTTP RPC interface of the node.
+type Service struct {
+	handler http.Handler
+}
+
+// New creates a GraphQL service resolving queries against the given chain,
+// transaction pool, miner and chain database.
+func New(chain *core.BlockChain, txPool *core.TxPool, miner *miner.Miner, chainDb ethdb.Database) (*Service, error) {
+	handler, err := NewHandler(chain, txPool, miner, chainDb)
+	if err != nil {
+		return nil, err
+	}
+	return &Service{handler: handler}, nil
+}
+
+// NewHandler creates a HTTP handler executing the GraphQL queries POSTed to
+// it as JSON objects with a query, an optional operationName and variables.
+// Queries nesting their fields deeper than maxQueryDepth are rejected.
+func NewHandler(chain *core.BlockChain, txPool *core.TxPool, miner *miner.Miner, chainDb ethdb.Database) (http.Handler, error) {
+	resolver := &Resolver{chain: chain, txPool: txPool, miner: miner, chainDb: chainDb}
+	s, err := graphql.ParseSchema(schema, resolver, graphql.MaxDepth(maxQueryDepth))
+	if err != nil {
+		return nil, err
+	}
+	return &relay.Handler{Schema: s}, nil
+}
+
+// Protocols implements node.Service, returning no P2P protocols.
+func (s *Service) Protocols() []p2p.Protocol { return nil }
+
+// APIs implements node.Service, returning no RPC APIs.
+func (s *Service) APIs() []rpc.API { return nil }
+
+// Handlers implements node.HandlerService, returning the GraphQL endpoint.
+func (s *Service) Handlers() map[string]http.Handler {
+	return map[string]http.Handler{Path: s.handler}
+}
+
+// Start implements node.Service.
+func (s *Service) Start(server *p2p.Server) error { return nil }
+
+// Stop implements node.Service.
+func (s *Service) Stop() error { return nil }
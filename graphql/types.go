@@ -0,0 +1,160 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package graphql
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereumproject/go-ethereum/common"
+)
+
+// Bytes32 is the Bytes32 scalar of the schema, a 0x-prefixed hex string.
+type Bytes32 struct{ common.Hash }
+
+func (Bytes32) ImplementsGraphQLType(name string) bool { return name == "Bytes32" }
+
+func (b *Bytes32) UnmarshalGraphQL(input interface{}) error {
+	data, err := unmarshalHex(input, common.HashLength)
+	if err != nil {
+		return err
+	}
+	b.Hash = common.BytesToHash(data)
+	return nil
+}
+
+// Address is the Address scalar of the schema, a 0x-prefixed hex string.
+type Address struct{ common.Address }
+
+func (Address) ImplementsGraphQLType(name string) bool { return name == "Address" }
+
+func (a *Address) UnmarshalGraphQL(input interface{}) error {
+	data, err := unmarshalHex(input, common.AddressLength)
+	if err != nil {
+		return err
+	}
+	a.Address = common.BytesToAddress(data)
+	return nil
+}
+
+// Bytes is the Bytes scalar of the schema, a 0x-prefixed hex string.
+type Bytes []byte
+
+func (Bytes) ImplementsGraphQLType(name string) bool { return name == "Bytes" }
+
+func (b *Bytes) UnmarshalGraphQL(input interface{}) error {
+	data, err := unmarshalHex(input, -1)
+	if err != nil {
+		return err
+	}
+	*b = data
+	return nil
+}
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal("0x" + hex.EncodeToString(b))
+}
+
+// BigInt is the BigInt scalar of the schema. It is encoded as a 0x-prefixed
+// hex string and decoded from a number, a decimal or a hex string.
+type BigInt struct{ *big.Int }
+
+func (BigInt) ImplementsGraphQLType(name string) bool { return name == "BigInt" }
+
+func (b *BigInt) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		n, ok := new(big.Int).SetString(input, 0)
+		if !ok || n.Sign() < 0 {
+			return fmt.Errorf("invalid BigInt %q", input)
+		}
+		b.Int = n
+	case int32:
+		b.Int = big.NewInt(int64(input))
+	case float64:
+		b.Int, _ = big.NewFloat(input).Int(nil)
+	default:
+		return fmt.Errorf("unexpected type %T for BigInt", input)
+	}
+	return nil
+}
+
+func (b BigInt) MarshalJSON() ([]byte, error) {
+	if b.Int == nil {
+		return json.Marshal("0x0")
+	}
+	return json.Marshal(fmt.Sprintf("%#x", b.Int))
+}
+
+// Long is the Long scalar of the schema, a 64 bit unsigned integer encoded
+// as a JSON number and decoded from a number, a decimal or a hex string.
+type Long uint64
+
+func (Long) ImplementsGraphQLType(name string) bool { return name == "Long" }
+
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch input := input.(type) {
+	case string:
+		n, err := strconv.ParseUint(input, 0, 64)
+		if err != nil {
+			return fmt.Errorf("invalid Long %q", input)
+		}
+		*l = Long(n)
+	case int32:
+		if input < 0 {
+			return fmt.Errorf("invalid Long %d", input)
+		}
+		*l = Long(input)
+	case float64:
+		if input < 0 {
+			return fmt.Errorf("invalid Long %v", input)
+		}
+		*l = Long(input)
+	default:
+		return fmt.Errorf("unexpected type %T for Long", input)
+	}
+	return nil
+}
+
+// newLong converts a non-negative big integer into a Long.
+func newLong(n *big.Int) Long {
+	return Long(n.Uint64())
+}
+
+// unmarshalHex decodes a 0x-prefixed hex string input of the given length in
+// bytes, or of any length if negative.
+func unmarshalHex(input interface{}, length int) ([]byte, error) {
+	s, ok := input.(string)
+	if !ok {
+		return nil, fmt.Errorf("unexpected type %T for hex string", input)
+	}
+	if !strings.HasPrefix(s, "0x") && !strings.HasPrefix(s, "0X") {
+		return nil, fmt.Errorf("hex string %q without 0x prefix", s)
+	}
+	data, err := hex.DecodeString(s[2:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid hex string %q", s)
+	}
+	if length >= 0 && len(data) != length {
+		return nil, fmt.Errorf("hex string %q has length %d, want %d", s, len(data), length)
+	}
+	return data, nil
+}
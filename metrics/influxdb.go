@@ -0,0 +1,149 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/rcrowley/go-metrics"
+)
+
+// InfluxDBConfig configures the push of metrics to an InfluxDB compatible
+// HTTP write endpoint.
+type InfluxDBConfig struct {
+	Endpoint string            // Base URL of the database, e.g. http://localhost:8086
+	Database string            // Database to write the metrics to
+	Username string            // Username for basic authentication (empty = none)
+	Password string            // Password for basic authentication
+	Interval time.Duration     // Interval between two pushes
+	Tags     map[string]string // Tags attached to every point, e.g. host and chain
+}
+
+// ReportToInfluxDB periodically pushes all registered metrics to the
+// configured InfluxDB endpoint. Failed pushes are logged and retried with the
+// next interval. It never returns.
+func ReportToInfluxDB(config InfluxDBConfig) {
+	glog.V(logger.Info).Infof("Pushing metrics to InfluxDB at %s every %v", config.Endpoint, config.Interval)
+	for range time.Tick(config.Interval) {
+		if err := PushInfluxDB(config); err != nil {
+			glog.V(logger.Warn).Infof("metrics: push to InfluxDB at %s failed: %v", config.Endpoint, err)
+		}
+	}
+}
+
+// PushInfluxDB writes the current values of all registered metrics to the
+// configured InfluxDB endpoint once.
+func PushInfluxDB(config InfluxDBConfig) error {
+	var body bytes.Buffer
+	if err := WriteInfluxDB(&body, config.Tags, time.Now()); err != nil {
+		return err
+	}
+
+	query := url.Values{"db": {config.Database}, "precision": {"s"}}
+	req, err := http.NewRequest("POST", strings.TrimSuffix(config.Endpoint, "/")+"/write?"+query.Encode(), &body)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	if config.Username != "" {
+		req.SetBasicAuth(config.Username, config.Password)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s: %s", resp.Status, bytes.TrimSpace(msg))
+	}
+	return nil
+}
+
+// WriteInfluxDB writes all registered metrics in the InfluxDB line protocol
+// with the given tags and timestamp. Each metric is a measurement named after
+// it, e.g. geth.msg.txn.in, with fields depending on its type.
+func WriteInfluxDB(w io.Writer, tags map[string]string, now time.Time) error {
+	UpdateSysMetrics()
+
+	var keys []string
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var tagSet string
+	for _, k := range keys {
+		tagSet += "," + influxEscape(k) + "=" + influxEscape(tags[k])
+	}
+	timestamp := now.Unix()
+
+	var err error
+	reg.Each(func(name string, i interface{}) {
+		var fields string
+		switch m := i.(type) {
+		case metrics.Counter:
+			fields = fmt.Sprintf("count=%di", m.Count())
+		case metrics.Gauge:
+			fields = fmt.Sprintf("value=%di", m.Value())
+		case metrics.GaugeFloat64:
+			fields = fmt.Sprintf("value=%v", m.Value())
+		case metrics.Meter:
+			s := m.Snapshot()
+			fields = fmt.Sprintf("count=%di,m1=%v,m5=%v,m15=%v,mean=%v", s.Count(), s.Rate1(), s.Rate5(), s.Rate15(), s.RateMean())
+		case metrics.Timer:
+			s := m.Snapshot()
+			ps := s.Percentiles([]float64{0.5, 0.95, 0.99})
+			fields = fmt.Sprintf("count=%di,min=%di,max=%di,mean=%v,p50=%v,p95=%v,p99=%v,m1=%v,m5=%v,m15=%v",
+				s.Count(), s.Min(), s.Max(), s.Mean(), ps[0], ps[1], ps[2], s.Rate1(), s.Rate5(), s.Rate15())
+		case metrics.Histogram:
+			s := m.Snapshot()
+			ps := s.Percentiles([]float64{0.5, 0.95, 0.99})
+			fields = fmt.Sprintf("count=%di,min=%di,max=%di,mean=%v,p50=%v,p95=%v,p99=%v",
+				s.Count(), s.Min(), s.Max(), s.Mean(), ps[0], ps[1], ps[2])
+		default:
+			return
+		}
+		if _, e := fmt.Fprintf(w, "%s%s %s %d\n", influxEscape(influxMeasurement(name)), tagSet, fields, timestamp); e != nil && err == nil {
+			err = e
+		}
+	})
+	return err
+}
+
+// influxMeasurement converts a slash separated metric name into a dot
+// separated measurement name, e.g. msg/txn/in becomes geth.msg.txn.in.
+func influxMeasurement(name string) string {
+	return namespace + "." + strings.Replace(name, "/", ".", -1)
+}
+
+// influxEscape escapes the characters with a special meaning in measurement
+// names, tag keys and tag values of the line protocol.
+func influxEscape(s string) string {
+	return strings.NewReplacer(",", `\,`, " ", `\ `, "=", `\=`).Replace(s)
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestPushInfluxDB(t *testing.T) {
+	P2POut.Mark(5)
+
+	var body, query, user string
+	db := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := ioutil.ReadAll(r.Body)
+		body, query = string(data), r.URL.RawQuery
+		user, _, _ = r.BasicAuth()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer db.Close()
+
+	config := InfluxDBConfig{
+		Endpoint: db.URL,
+		Database: "fleet",
+		Username: "reporter",
+		Tags:     map[string]string{"host": "node 1", "chain": "mainnet"},
+	}
+	if err := PushInfluxDB(config); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if query != "db=fleet&precision=s" {
+		t.Errorf("query mismatch: have %q", query)
+	}
+	if user != "reporter" {
+		t.Errorf("username mismatch: have %q", user)
+	}
+	if !strings.Contains(body, "geth.p2p.out,chain=mainnet,host=node\\ 1 count=5i,") {
+		t.Errorf("meter point missing from body:\n%s", body)
+	}
+	if !strings.Contains(body, "geth.runtime.goroutines,chain=mainnet,host=node\\ 1 value=") {
+		t.Errorf("gauge point missing from body:\n%s", body)
+	}
+}
+
+func TestPushInfluxDBError(t *testing.T) {
+	db := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "database not found", http.StatusNotFound)
+	}))
+	defer db.Close()
+
+	err := PushInfluxDB(InfluxDBConfig{Endpoint: db.URL, Database: "missing"})
+	if err == nil || !strings.Contains(err.Error(), "database not found") {
+		t.Fatalf("expected database error, got %v", err)
+	}
+}
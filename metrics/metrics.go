@@ -119,6 +119,26 @@ var (
 	P2POutBytes = metrics.NewRegisteredMeter("p2p/out/bytes", reg)
 )
 
+var (
+	RPCCalls    = metrics.NewRegisteredMeter("rpc/call", reg)
+	RPCErrors   = metrics.NewRegisteredMeter("rpc/error", reg)
+	RPCDuration = metrics.NewRegisteredTimer("rpc/duration", reg)
+)
+
+// MarkRPCCall records an executed RPC method call, its duration and whether it
+// failed, both in total and for the given method. Methods are expected to be
+// known to the server, which bounds the number of registered metrics.
+func MarkRPCCall(method string, d time.Duration, failed bool) {
+	RPCCalls.Mark(1)
+	RPCDuration.Update(d)
+	metrics.GetOrRegisterMeter("rpc/call/"+method, reg).Mark(1)
+	metrics.GetOrRegisterTimer("rpc/duration/"+method, reg).Update(d)
+	if failed {
+		RPCErrors.Mark(1)
+		metrics.GetOrRegisterMeter("rpc/error/"+method, reg).Mark(1)
+	}
+}
+
 var (
 	MemAllocs = metrics.GetOrRegisterGauge("memory/allocs", reg)
 	MemFrees  = metrics.GetOrRegisterGauge("memory/frees", reg)
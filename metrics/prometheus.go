@@ -0,0 +1,126 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/rcrowley/go-metrics"
+)
+
+// namespace prefixes the names of all metrics exported to monitoring systems.
+const namespace = "geth"
+
+// prometheusQuantiles are the quantiles exposed for timers and histograms.
+var prometheusQuantiles = []float64{0.5, 0.75, 0.95, 0.99, 0.999}
+
+// WritePrometheus writes all registered metrics in the Prometheus text
+// exposition format. Meters are exposed as counters, timers as summaries in
+// seconds and histograms as summaries of their raw values.
+func WritePrometheus(w io.Writer) error {
+	UpdateSysMetrics()
+
+	var names []string
+	metricsByName := make(map[string]interface{})
+	reg.Each(func(name string, i interface{}) {
+		names = append(names, name)
+		metricsByName[name] = i
+	})
+	sort.Strings(names)
+
+	buf := bufio.NewWriter(w)
+	for _, name := range names {
+		id := prometheusName(name)
+		switch m := metricsByName[name].(type) {
+		case metrics.Counter:
+			writePrometheusValue(buf, id, "counter", float64(m.Count()))
+		case metrics.Gauge:
+			writePrometheusValue(buf, id, "gauge", float64(m.Value()))
+		case metrics.GaugeFloat64:
+			writePrometheusValue(buf, id, "gauge", m.Value())
+		case metrics.Meter:
+			writePrometheusValue(buf, id+"_total", "counter", float64(m.Count()))
+		case metrics.Timer:
+			t := m.Snapshot()
+			seconds := float64(time.Second)
+			ps := t.Percentiles(prometheusQuantiles)
+			for i := range ps {
+				ps[i] /= seconds
+			}
+			writePrometheusSummary(buf, id+"_seconds", ps, float64(t.Sum())/seconds, t.Count())
+		case metrics.Histogram:
+			h := m.Snapshot()
+			writePrometheusSummary(buf, id, h.Percentiles(prometheusQuantiles), float64(h.Sum()), h.Count())
+		}
+	}
+	return buf.Flush()
+}
+
+// prometheusName converts a slash separated metric name into a valid
+// Prometheus metric name, e.g. msg/txn/in becomes geth_msg_txn_in.
+func prometheusName(name string) string {
+	name = strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_':
+			return r
+		}
+		return '_'
+	}, name)
+	return namespace + "_" + name
+}
+
+func writePrometheusValue(w io.Writer, name, kind string, value float64) {
+	fmt.Fprintf(w, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(w, "%s %v\n", name, value)
+}
+
+func writePrometheusSummary(w io.Writer, name string, quantiles []float64, sum float64, count int64) {
+	fmt.Fprintf(w, "# TYPE %s summary\n", name)
+	for i, q := range prometheusQuantiles {
+		fmt.Fprintf(w, "%s{quantile=\"%v\"} %v\n", name, q, quantiles[i])
+	}
+	fmt.Fprintf(w, "%s_sum %v\n", name, sum)
+	fmt.Fprintf(w, "%s_count %d\n", name, count)
+}
+
+// PrometheusHandler returns a HTTP handler rendering all registered metrics
+// in the Prometheus text exposition format.
+func PrometheusHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		if err := WritePrometheus(w); err != nil {
+			glog.V(logger.Error).Infof("metrics: failed to write Prometheus metrics: %v", err)
+		}
+	})
+}
+
+// ServePrometheus serves the Prometheus metrics on the /metrics path of the
+// given address. It only returns when the listener fails.
+func ServePrometheus(addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", PrometheusHandler())
+	glog.V(logger.Info).Infof("Prometheus metrics endpoint opened: http://%s/metrics", addr)
+	return http.ListenAndServe(addr, mux)
+}
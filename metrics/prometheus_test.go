@@ -0,0 +1,47 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package metrics
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWritePrometheus(t *testing.T) {
+	P2PIn.Mark(3)
+	MarkRPCCall("eth_blockNumber", 2*time.Second, false)
+
+	var buf bytes.Buffer
+	if err := WritePrometheus(&buf); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+
+	for _, want := range []string{
+		"# TYPE geth_p2p_in_total counter\ngeth_p2p_in_total 3\n",
+		"# TYPE geth_runtime_goroutines gauge\n",
+		"# TYPE geth_rpc_duration_eth_blockNumber_seconds summary\n",
+		"geth_rpc_duration_eth_blockNumber_seconds{quantile=\"0.5\"} 2\n",
+		"geth_rpc_duration_eth_blockNumber_seconds_count 1\n",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output misses %q", want)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/state"
+	"github.com/ethereumproject/go-ethereum/core/types"
+)
+
+// BuildResult is a block assembled the same way as mining work, but which
+// is neither sealed nor written to the chain.
+type BuildResult struct {
+	Block    *types.Block
+	Receipts types.Receipts
+	State    *state.StateDB     // state after applying the block, including rewards
+	Fees     *big.Int           // sum of gas used times gas price of all included transactions
+	Skipped  types.Transactions // candidate transactions which were not included
+}
+
+// buildBlock assembles a block on top of parent. When txs is nil the pending
+// transactions of the pool are ordered by the configured selector, otherwise
+// the given transactions are applied in order. The pool, the current mining
+// work and the known uncles are left untouched.
+func (self *worker) buildBlock(parent *types.Block, txs types.Transactions) (*BuildResult, error) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.uncleMu.Lock()
+	defer self.uncleMu.Unlock()
+
+	tstamp := time.Now().Unix()
+	if parent.Time().Cmp(big.NewInt(tstamp)) >= 0 {
+		tstamp = parent.Time().Int64() + 1
+	}
+	header := self.makeHeader(parent, self.coinbase, tstamp)
+	work, err := self.makeWork(parent, header)
+	if err != nil {
+		return nil, err
+	}
+	if txs == nil {
+		txs = self.selector.Select(self.eth.TxPool().GetTransactions())
+	}
+	work.commitTransactions(nil, txs, self.gasPrice, self.chain)
+
+	uncles, _ := self.commitUncles(work)
+	core.AccumulateRewards(work.config, work.state, header, uncles)
+	header.Root = work.state.IntermediateRoot()
+
+	result := &BuildResult{
+		Block:    types.NewBlock(header, work.txs, uncles, work.receipts),
+		Receipts: work.receipts,
+		State:    work.state,
+		Fees:     new(big.Int),
+	}
+	included := make(map[*types.Transaction]bool, len(work.txs))
+	for i, tx := range work.txs {
+		included[tx] = true
+		result.Fees.Add(result.Fees, new(big.Int).Mul(work.receipts[i].GasUsed, tx.GasPrice()))
+	}
+	for _, tx := range txs {
+		if !included[tx] {
+			result.Skipped = append(result.Skipped, tx)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/event"
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+)
+
+// InstantSealer is an agent for development chains which seals blocks
+// without doing any proof-of-work. With a zero period a block is sealed as
+// soon as a transaction enters the pool, otherwise a block (which may be
+// empty) is sealed once per period. The chain must be configured with a
+// proof-of-work which accepts any nonce, such as core.FakePow.
+type InstantSealer struct {
+	mu sync.Mutex
+
+	workCh   chan *Work
+	quit     chan struct{}
+	returnCh chan<- *Result
+
+	mux      *event.TypeMux
+	period   time.Duration
+	recommit func() // requests new work including the latest pending transactions
+
+	isSealing int32
+}
+
+// NewInstantSealer creates an agent sealing blocks when transactions arrive
+// on mux, or every period if it is non-zero. recommit is called to request
+// fresh work from the worker.
+func NewInstantSealer(mux *event.TypeMux, period time.Duration, recommit func()) *InstantSealer {
+	return &InstantSealer{
+		mux:      mux,
+		period:   period,
+		recommit: recommit,
+	}
+}
+
+func (self *InstantSealer) Work() chan<- *Work            { return self.workCh }
+func (self *InstantSealer) SetReturnCh(ch chan<- *Result) { self.returnCh = ch }
+func (self *InstantSealer) GetHashRate() int64            { return 0 }
+
+func (self *InstantSealer) Start() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if !atomic.CompareAndSwapInt32(&self.isSealing, 0, 1) {
+		return // agent already started
+	}
+	self.quit = make(chan struct{})
+	self.workCh = make(chan *Work, 1)
+
+	go self.update(self.quit)
+}
+
+func (self *InstantSealer) Stop() {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+
+	if self.quit != nil {
+		close(self.quit)
+		self.quit = nil
+	}
+}
+
+func (self *InstantSealer) update(quit chan struct{}) {
+	var (
+		txs    event.Subscription
+		txCh   <-chan *event.Event
+		tick   <-chan time.Time
+		ticker *time.Ticker
+
+		sealNext   bool        // seal the next work even if it is empty
+		sealedOn   common.Hash // parent of the most recently sealed block
+		recommitCh = make(chan struct{}, 1)
+	)
+	if self.period > 0 {
+		ticker = time.NewTicker(self.period)
+		defer ticker.Stop()
+		tick = ticker.C
+	} else {
+		txs = self.mux.Subscribe(core.TxPreEvent{})
+		defer txs.Unsubscribe()
+		txCh = txs.Chan()
+	}
+	// The worker pushes new work synchronously, so it must never be asked
+	// for work from the loop which receives it.
+	go func() {
+		for {
+			select {
+			case <-recommitCh:
+				self.recommit()
+			case <-quit:
+				return
+			}
+		}
+	}()
+	requestWork := func() {
+		select {
+		case recommitCh <- struct{}{}:
+		default:
+		}
+	}
+
+out:
+	for {
+		select {
+		case work := <-self.workCh:
+			// Seal at most one block on each parent to avoid producing side chains
+			// out of work requested before the previous block was imported.
+			if work.header.ParentHash == sealedOn {
+				continue
+			}
+			if !sealNext && (self.period > 0 || len(work.txs) == 0) {
+				continue
+			}
+			sealNext = false
+			sealedOn = work.header.ParentHash
+
+			block := work.Block.WithMiningResult(0, common.Hash{})
+			glog.V(logger.Debug).Infof("Instantly sealed block #%v with %d txs", block.Number(), len(block.Transactions()))
+			self.returnCh <- &Result{work, block}
+
+		case ev, ok := <-txCh:
+			if ok && ev != nil {
+				requestWork()
+			}
+		case <-tick:
+			sealNext = true
+			requestWork()
+
+		case <-quit:
+			break out
+		}
+	}
+
+done:
+	// Empty work channel
+	for {
+		select {
+		case <-self.workCh:
+		default:
+			close(self.workCh)
+			break done
+		}
+	}
+	atomic.StoreInt32(&self.isSealing, 0)
+}
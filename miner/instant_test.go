@@ -0,0 +1,66 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/types"
+)
+
+func TestInstantSealer(t *testing.T) {
+	backend, teardown := newTestBackend(t)
+	defer teardown()
+
+	heads := backend.mux.Subscribe(core.ChainHeadEvent{})
+	defer heads.Unsubscribe()
+
+	w := newWorker(backend.chain.Config(), common.Address{0xc0}, backend)
+	sealer := NewInstantSealer(backend.mux, 0, w.commitNewWork)
+	w.register(sealer)
+	w.start()
+	defer w.stop()
+	w.commitNewWork()
+
+	// No block may be sealed without pending transactions
+	select {
+	case ev := <-heads.Chan():
+		t.Fatalf("sealed empty block #%v", ev.Data.(core.ChainHeadEvent).Block.Number())
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	tx, _ := types.NewTransaction(0, common.Address{1}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil).SignECDSA(testBankKey)
+	if err := backend.txPool.Add(tx); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case ev := <-heads.Chan():
+		block := ev.Data.(core.ChainHeadEvent).Block
+		if block.NumberU64() != 1 || len(block.Transactions()) != 1 {
+			t.Fatalf("sealed block #%v with %d txs, want #1 with 1 tx", block.Number(), len(block.Transactions()))
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("transaction was not sealed")
+	}
+	if current := backend.chain.CurrentBlock(); current.NumberU64() != 1 {
+		t.Errorf("chain head is #%v, want #1", current.Number())
+	}
+}
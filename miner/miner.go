@@ -21,6 +21,7 @@ import (
 	"errors"
 	"math/big"
 	"sync/atomic"
+	"time"
 
 	"github.com/ethereumproject/go-ethereum/common"
 	"github.com/ethereumproject/go-ethereum/core"
@@ -108,6 +109,22 @@ func (m *Miner) SetGasPrice(price *big.Int) error {
 	return nil
 }
 
+// SetTransactionSelector sets the policy used to order and filter pending
+// transactions for new blocks. It takes effect with the next block template.
+func (m *Miner) SetTransactionSelector(selector TransactionSelector) {
+	if selector == nil {
+		selector = PriceSelector{}
+	}
+	m.worker.setSelector(selector)
+	glog.V(logger.Info).Infof("Transaction selection policy set to %q", selector.Name())
+}
+
+// TransactionSelector returns the policy used to order and filter pending
+// transactions for new blocks.
+func (m *Miner) TransactionSelector() TransactionSelector {
+	return m.worker.transactionSelector()
+}
+
 func (self *Miner) Start(coinbase common.Address, threads int) {
 	atomic.StoreInt32(&self.shouldStart, 1)
 	self.threads = threads
@@ -155,6 +172,13 @@ func (self *Miner) Register(agent Agent) {
 	self.worker.register(agent)
 }
 
+// RegisterInstantSealer adds an agent sealing blocks without proof-of-work,
+// either as soon as transactions are pending (period 0) or once per period.
+// It is meant for development chains using a fake proof-of-work.
+func (self *Miner) RegisterInstantSealer(period time.Duration) {
+	self.Register(NewInstantSealer(self.mux, period, self.worker.commitNewWork))
+}
+
 func (self *Miner) Unregister(agent Agent) {
 	self.worker.unregister(agent)
 }
@@ -179,6 +203,13 @@ func (self *Miner) Pending() (*types.Block, *state.StateDB) {
 	return self.worker.pending()
 }
 
+// BuildBlock assembles a block on top of parent without sealing it or
+// starting any agents. When txs is nil the block is filled from the
+// transaction pool, the same way the next mining work would be.
+func (self *Miner) BuildBlock(parent *types.Block, txs types.Transactions) (*BuildResult, error) {
+	return self.worker.buildBlock(parent, txs)
+}
+
 func (self *Miner) SetEtherbase(addr common.Address) {
 	self.coinbase = addr
 	self.worker.setEtherbase(addr)
@@ -0,0 +1,256 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"container/heap"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+)
+
+// Names of the built-in transaction ordering policies.
+const (
+	PriceSelectorName     = "price"
+	FIFOSelectorName      = "fifo"
+	WhitelistSelectorName = "whitelist"
+	NoCreateSelectorName  = "no-create"
+)
+
+// DefaultSelectorName is the policy used when none is configured.
+const DefaultSelectorName = PriceSelectorName
+
+// SelectorNames lists the policies accepted by NewTransactionSelector.
+var SelectorNames = []string{PriceSelectorName, FIFOSelectorName, WhitelistSelectorName, NoCreateSelectorName}
+
+// TransactionSelector decides which of the pending transactions the worker
+// tries to include in a new block, and in which order.
+type TransactionSelector interface {
+	// Name returns the policy identifier used by flags and the RPC API.
+	Name() string
+
+	// Select returns the transactions to commit, in commit order. Transactions
+	// of a single sender must be kept in nonce order; transactions left out are
+	// ignored for this block only and stay in the pool.
+	Select(txs types.Transactions) types.Transactions
+
+	// Exempt reports whether transactions of the sender are accepted below
+	// the miner's gas price floor.
+	Exempt(addr common.Address) bool
+}
+
+// NewTransactionSelector creates the named built-in policy. The whitelist is
+// only used by the whitelist policy, which requires at least one address.
+func NewTransactionSelector(name string, whitelist []common.Address) (TransactionSelector, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", PriceSelectorName:
+		return PriceSelector{}, nil
+	case FIFOSelectorName:
+		return NewFIFOSelector(), nil
+	case WhitelistSelectorName:
+		if len(whitelist) == 0 {
+			return nil, fmt.Errorf("transaction policy %q requires at least one whitelisted sender", WhitelistSelectorName)
+		}
+		return NewWhitelistSelector(whitelist), nil
+	case NoCreateSelectorName:
+		return NoCreateSelector{}, nil
+	}
+	return nil, fmt.Errorf("unknown transaction policy %q (available: %s)", name, strings.Join(SelectorNames, ", "))
+}
+
+// PriceSelector orders transactions by gas price while keeping the nonce
+// order of each sender. This is the default policy.
+type PriceSelector struct{}
+
+func (PriceSelector) Name() string                    { return PriceSelectorName }
+func (PriceSelector) Exempt(addr common.Address) bool { return false }
+
+func (PriceSelector) Select(txs types.Transactions) types.Transactions {
+	types.SortByPriceAndNonce(txs)
+	return txs
+}
+
+// fifoRetention is how long the FIFO selector remembers when it first saw a
+// transaction that is no longer offered for selection.
+const fifoRetention = time.Hour
+
+// FIFOSelector orders transactions by the time the selector first saw them,
+// regardless of gas price, while keeping the nonce order of each sender.
+type FIFOSelector struct {
+	mu   sync.Mutex
+	seen map[common.Hash]time.Time
+}
+
+// NewFIFOSelector creates a first-in, first-out transaction selector.
+func NewFIFOSelector() *FIFOSelector {
+	return &FIFOSelector{seen: make(map[common.Hash]time.Time)}
+}
+
+func (s *FIFOSelector) Name() string                    { return FIFOSelectorName }
+func (s *FIFOSelector) Exempt(addr common.Address) bool { return false }
+
+func (s *FIFOSelector) Select(txs types.Transactions) types.Transactions {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	current := make(map[common.Hash]bool, len(txs))
+	for _, tx := range txs {
+		hash := tx.Hash()
+		current[hash] = true
+		if _, ok := s.seen[hash]; !ok {
+			s.seen[hash] = now
+		}
+	}
+	// Forget about transactions which are long gone from the pool. Recently
+	// seen ones are kept since the worker may select from a partial list.
+	for hash, t := range s.seen {
+		if !current[hash] && now.Sub(t) > fifoRetention {
+			delete(s.seen, hash)
+		}
+	}
+	seen := s.seen
+
+	return mergeByNonce(txs, func(a, b *types.Transaction) bool {
+		ta, tb := seen[a.Hash()], seen[b.Hash()]
+		if ta.Equal(tb) {
+			return a.GasPrice().Cmp(b.GasPrice()) > 0
+		}
+		return ta.Before(tb)
+	})
+}
+
+// WhitelistSelector commits transactions of the configured senders first,
+// followed by all others, each group ordered by price. Whitelisted senders are
+// exempt from the gas price floor.
+type WhitelistSelector struct {
+	senders map[common.Address]bool
+}
+
+// NewWhitelistSelector creates a selector prioritising the given senders.
+func NewWhitelistSelector(senders []common.Address) *WhitelistSelector {
+	s := &WhitelistSelector{senders: make(map[common.Address]bool, len(senders))}
+	for _, addr := range senders {
+		s.senders[addr] = true
+	}
+	return s
+}
+
+func (s *WhitelistSelector) Name() string                    { return WhitelistSelectorName }
+func (s *WhitelistSelector) Exempt(addr common.Address) bool { return s.senders[addr] }
+
+// Senders returns the whitelisted addresses.
+func (s *WhitelistSelector) Senders() []common.Address {
+	addrs := make([]common.Address, 0, len(s.senders))
+	for addr := range s.senders {
+		addrs = append(addrs, addr)
+	}
+	sort.Sort(addressesByHex(addrs))
+	return addrs
+}
+
+func (s *WhitelistSelector) Select(txs types.Transactions) types.Transactions {
+	var priority, others types.Transactions
+	for _, tx := range txs {
+		from, _ := tx.From() // only valid txs are selected
+		if s.senders[from] {
+			priority = append(priority, tx)
+		} else {
+			others = append(others, tx)
+		}
+	}
+	types.SortByPriceAndNonce(priority)
+	types.SortByPriceAndNonce(others)
+	return append(priority, others...)
+}
+
+// NoCreateSelector orders transactions like PriceSelector, but leaves out
+// contract creations.
+type NoCreateSelector struct{}
+
+func (NoCreateSelector) Name() string                    { return NoCreateSelectorName }
+func (NoCreateSelector) Exempt(addr common.Address) bool { return false }
+
+func (NoCreateSelector) Select(txs types.Transactions) types.Transactions {
+	selected := make(types.Transactions, 0, len(txs))
+	for _, tx := range txs {
+		if tx.To() != nil {
+			selected = append(selected, tx)
+		}
+	}
+	types.SortByPriceAndNonce(selected)
+	return selected
+}
+
+// mergeByNonce sorts the transactions of each sender by nonce and merges the
+// heads of all senders using the given ordering.
+func mergeByNonce(txs types.Transactions, less func(a, b *types.Transaction) bool) types.Transactions {
+	byNonce := make(map[common.Address]types.Transactions)
+	for _, tx := range txs {
+		acc, _ := tx.From()
+		byNonce[acc] = append(byNonce[acc], tx)
+	}
+	heads := &txHeads{less: less}
+	for acc, accTxs := range byNonce {
+		sort.Sort(types.TxByNonce(accTxs))
+		heads.txs = append(heads.txs, accTxs[0])
+		byNonce[acc] = accTxs[1:]
+	}
+	heap.Init(heads)
+
+	merged := make(types.Transactions, 0, len(txs))
+	for heads.Len() > 0 {
+		best := heap.Pop(heads).(*types.Transaction)
+		acc, _ := best.From()
+		if accTxs := byNonce[acc]; len(accTxs) > 0 {
+			heap.Push(heads, accTxs[0])
+			byNonce[acc] = accTxs[1:]
+		}
+		merged = append(merged, best)
+	}
+	return merged
+}
+
+// txHeads is a heap of transactions ordered by an arbitrary function.
+type txHeads struct {
+	txs  types.Transactions
+	less func(a, b *types.Transaction) bool
+}
+
+func (h *txHeads) Len() int           { return len(h.txs) }
+func (h *txHeads) Less(i, j int) bool { return h.less(h.txs[i], h.txs[j]) }
+func (h *txHeads) Swap(i, j int)      { h.txs[i], h.txs[j] = h.txs[j], h.txs[i] }
+func (h *txHeads) Push(x interface{}) { h.txs = append(h.txs, x.(*types.Transaction)) }
+
+func (h *txHeads) Pop() interface{} {
+	old := h.txs
+	n := len(old)
+	x := old[n-1]
+	h.txs = old[:n-1]
+	return x
+}
+
+type addressesByHex []common.Address
+
+func (a addressesByHex) Len() int           { return len(a) }
+func (a addressesByHex) Less(i, j int) bool { return a[i].Hex() < a[j].Hex() }
+func (a addressesByHex) Swap(i, j int)      { a[i], a[j] = a[j], a[i] }
@@ -0,0 +1,148 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/crypto"
+)
+
+func selectorTx(t *testing.T, key *ecdsa.PrivateKey, nonce uint64, price int64, create bool) *types.Transaction {
+	var tx *types.Transaction
+	if create {
+		tx = types.NewContractCreation(nonce, big.NewInt(0), big.NewInt(100000), big.NewInt(price), nil)
+	} else {
+		tx = types.NewTransaction(nonce, common.Address{1}, big.NewInt(0), big.NewInt(21000), big.NewInt(price), nil)
+	}
+	signed, err := tx.SignECDSA(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signed
+}
+
+func checkNonceOrder(t *testing.T, txs types.Transactions) {
+	next := make(map[common.Address]uint64)
+	for i, tx := range txs {
+		from, _ := tx.From()
+		if n, ok := next[from]; ok && tx.Nonce() < n {
+			t.Errorf("tx #%d: nonce %d out of order for %x", i, tx.Nonce(), from[:4])
+		}
+		next[from] = tx.Nonce()
+	}
+}
+
+func TestNewTransactionSelector(t *testing.T) {
+	for _, name := range SelectorNames {
+		sel, err := NewTransactionSelector(name, []common.Address{{1}})
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if sel.Name() != name {
+			t.Errorf("got policy %q, want %q", sel.Name(), name)
+		}
+	}
+	if _, err := NewTransactionSelector(WhitelistSelectorName, nil); err == nil {
+		t.Error("expected error for whitelist policy without senders")
+	}
+	if _, err := NewTransactionSelector("random", nil); err == nil {
+		t.Error("expected error for unknown policy")
+	}
+}
+
+func TestWhitelistSelector(t *testing.T) {
+	partner, _ := crypto.GenerateKey()
+	other, _ := crypto.GenerateKey()
+	partnerAddr := crypto.PubkeyToAddress(partner.PublicKey)
+
+	txs := types.Transactions{
+		selectorTx(t, other, 0, 100, false),
+		selectorTx(t, partner, 1, 1, false),
+		selectorTx(t, other, 1, 100, false),
+		selectorTx(t, partner, 0, 1, false),
+	}
+	sel := NewWhitelistSelector([]common.Address{partnerAddr})
+	selected := sel.Select(txs)
+	if len(selected) != 4 {
+		t.Fatalf("selected %d txs, want 4", len(selected))
+	}
+	for i, tx := range selected[:2] {
+		if from, _ := tx.From(); from != partnerAddr {
+			t.Errorf("tx #%d: expected whitelisted sender first, got %x", i, from[:4])
+		}
+	}
+	checkNonceOrder(t, selected)
+
+	if !sel.Exempt(partnerAddr) {
+		t.Error("whitelisted sender should be exempt from the price floor")
+	}
+	if sel.Exempt(crypto.PubkeyToAddress(other.PublicKey)) {
+		t.Error("other sender should not be exempt from the price floor")
+	}
+}
+
+func TestNoCreateSelector(t *testing.T) {
+	key, _ := crypto.GenerateKey()
+	txs := types.Transactions{
+		selectorTx(t, key, 0, 1, false),
+		selectorTx(t, key, 1, 1, true),
+		selectorTx(t, key, 2, 1, false),
+	}
+	selected := NoCreateSelector{}.Select(txs)
+	if len(selected) != 2 {
+		t.Fatalf("selected %d txs, want 2", len(selected))
+	}
+	for _, tx := range selected {
+		if tx.To() == nil {
+			t.Error("contract creation was selected")
+		}
+	}
+}
+
+func TestFIFOSelector(t *testing.T) {
+	first, _ := crypto.GenerateKey()
+	second, _ := crypto.GenerateKey()
+
+	sel := NewFIFOSelector()
+	early := types.Transactions{selectorTx(t, first, 0, 1, false), selectorTx(t, first, 1, 1, false)}
+	sel.Select(append(types.Transactions{}, early...))
+
+	// Later, better paying transactions must not overtake the earlier ones
+	sel.seen[early[0].Hash()] = sel.seen[early[0].Hash()].Add(-fifoRetention / 2)
+	sel.seen[early[1].Hash()] = sel.seen[early[1].Hash()].Add(-fifoRetention / 2)
+	late := selectorTx(t, second, 0, 1000, false)
+
+	selected := sel.Select(types.Transactions{late, early[1], early[0]})
+	if len(selected) != 3 {
+		t.Fatalf("selected %d txs, want 3", len(selected))
+	}
+	if selected[2] != late {
+		t.Errorf("late transaction was not selected last")
+	}
+	checkNonceOrder(t, selected)
+
+	// Selecting from a partial list must not forget recent transactions
+	sel.Select(types.Transactions{late})
+	if _, ok := sel.seen[early[0].Hash()]; !ok {
+		t.Error("recently seen transaction was forgotten")
+	}
+}
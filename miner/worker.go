@@ -70,6 +70,7 @@ type Work struct {
 	ignoredTransactors *set.Set
 	lowGasTransactors  *set.Set
 	ownedAccounts      *set.Set
+	selector           TransactionSelector
 	lowGasTxs          types.Transactions
 	localMinedBlocks   *uint64RingBuffer // the most recent block numbers that were mined locally (used to check block inclusion)
 
@@ -108,6 +109,7 @@ type worker struct {
 
 	coinbase common.Address
 	gasPrice *big.Int
+	selector TransactionSelector
 
 	currentMu sync.Mutex
 	current   *Work
@@ -132,6 +134,7 @@ func newWorker(config *core.ChainConfig, coinbase common.Address, eth core.Backe
 		chainDb:        eth.ChainDb(),
 		recv:           make(chan *Result, resultQueueSize),
 		gasPrice:       new(big.Int),
+		selector:       PriceSelector{},
 		chain:          eth.BlockChain(),
 		proc:           eth.BlockChain().Validator(),
 		possibleUncles: make(map[common.Hash]*types.Block),
@@ -155,6 +158,20 @@ func (self *worker) setEtherbase(addr common.Address) {
 	self.coinbase = addr
 }
 
+// setSelector replaces the transaction ordering policy used for new work.
+func (self *worker) setSelector(selector TransactionSelector) {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	self.selector = selector
+}
+
+// transactionSelector returns the current transaction ordering policy.
+func (self *worker) transactionSelector() TransactionSelector {
+	self.mu.Lock()
+	defer self.mu.Unlock()
+	return self.selector
+}
+
 func (self *worker) pending() (*types.Block, *state.StateDB) {
 	self.currentMu.Lock()
 	defer self.currentMu.Unlock()
@@ -229,8 +246,12 @@ func (self *worker) update() {
 		case core.TxPreEvent:
 			// Apply transaction to the pending state if we're not mining
 			if atomic.LoadInt32(&self.mining) == 0 {
+				self.mu.Lock()
+				selector, gasPrice := self.selector, self.gasPrice
+				self.mu.Unlock()
+
 				self.currentMu.Lock()
-				self.current.commitTransactions(self.mux, types.Transactions{ev.Tx}, self.gasPrice, self.chain)
+				self.current.commitTransactions(self.mux, selector.Select(types.Transactions{ev.Tx}), gasPrice, self.chain)
 				self.currentMu.Unlock()
 			}
 		}
@@ -360,11 +381,11 @@ func (self *worker) push(work *Work) {
 	}
 }
 
-// makeCurrent creates a new environment for the current cycle.
-func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error {
+// makeWork creates a new environment for a block on top of parent.
+func (self *worker) makeWork(parent *types.Block, header *types.Header) (*Work, error) {
 	state, err := self.chain.StateAt(parent.Root())
 	if err != nil {
-		return err
+		return nil, err
 	}
 	work := &Work{
 		config:    self.config,
@@ -393,6 +414,16 @@ func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error
 	work.ignoredTransactors = set.New()
 	work.lowGasTransactors = set.New()
 	work.ownedAccounts = accountAddressesSet(accounts)
+	work.selector = self.selector
+	return work, nil
+}
+
+// makeCurrent creates a new environment for the current cycle.
+func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error {
+	work, err := self.makeWork(parent, header)
+	if err != nil {
+		return err
+	}
 	if self.current != nil {
 		work.localMinedBlocks = self.current.localMinedBlocks
 	}
@@ -400,6 +431,44 @@ func (self *worker) makeCurrent(parent *types.Block, header *types.Header) error
 	return nil
 }
 
+// makeHeader creates the header of a block on top of parent, sealed at the
+// given time and paying rewards to coinbase.
+func (self *worker) makeHeader(parent *types.Block, coinbase common.Address, tstamp int64) *types.Header {
+	num := parent.Number()
+	return &types.Header{
+		ParentHash: parent.Hash(),
+		Number:     num.Add(num, common.Big1),
+		Difficulty: core.CalcDifficulty(self.config, uint64(tstamp), parent.Time().Uint64(), parent.Number(), parent.Difficulty()),
+		GasLimit:   core.CalcGasLimit(parent),
+		GasUsed:    new(big.Int),
+		Coinbase:   coinbase,
+		Extra:      HeaderExtra,
+		Time:       big.NewInt(tstamp),
+	}
+}
+
+// commitUncles selects at most two of the known side chain blocks as uncles
+// of work, returning the chosen headers and the blocks which can never be
+// uncles of it. The caller must hold uncleMu.
+func (self *worker) commitUncles(work *Work) (uncles []*types.Header, badUncles []common.Hash) {
+	for hash, uncle := range self.possibleUncles {
+		if len(uncles) == 2 {
+			break
+		}
+		if err := self.commitUncle(work, uncle.Header()); err != nil {
+			if glog.V(logger.Ridiculousness) {
+				glog.V(logger.Detail).Infof("Bad uncle found and will be removed (%x)\n", hash[:4])
+				glog.V(logger.Detail).Infoln(uncle)
+			}
+			badUncles = append(badUncles, hash)
+		} else {
+			glog.V(logger.Debug).Infof("commiting %x as uncle\n", hash[:4])
+			uncles = append(uncles, uncle.Header())
+		}
+	}
+	return uncles, badUncles
+}
+
 func (w *worker) setGasPrice(p *big.Int) {
 	w.mu.Lock()
 	defer w.mu.Unlock()
@@ -469,17 +538,7 @@ func (self *worker) commitNewWork() {
 		time.Sleep(wait)
 	}
 
-	num := parent.Number()
-	header := &types.Header{
-		ParentHash: parent.Hash(),
-		Number:     num.Add(num, common.Big1),
-		Difficulty: core.CalcDifficulty(self.config, uint64(tstamp), parent.Time().Uint64(), parent.Number(), parent.Difficulty()),
-		GasLimit:   core.CalcGasLimit(parent),
-		GasUsed:    new(big.Int),
-		Coinbase:   self.coinbase,
-		Extra:      HeaderExtra,
-		Time:       big.NewInt(tstamp),
-	}
+	header := self.makeHeader(parent, self.coinbase, tstamp)
 	previous := self.current
 	// Could potentially happen if starting to mine in an odd state.
 	err := self.makeCurrent(parent, header)
@@ -490,65 +549,14 @@ func (self *worker) commitNewWork() {
 	// Create the current work task and check any fork transitions needed
 	work := self.current
 
-	/* //approach 1
-	transactions := self.eth.TxPool().GetTransactions()
-	sort.Sort(types.TxByNonce(transactions))
-	*/
-
-	//approach 2
-	transactions := self.eth.TxPool().GetTransactions()
-	types.SortByPriceAndNonce(transactions)
-
-	/* // approach 3
-	// commit transactions for this run.
-	txPerOwner := make(map[common.Address]types.Transactions)
-	// Sort transactions by owner
-	for _, tx := range self.eth.TxPool().GetTransactions() {
-		from, _ := tx.From() // we can ignore the sender error
-		txPerOwner[from] = append(txPerOwner[from], tx)
-	}
-	var (
-		singleTxOwner types.Transactions
-		multiTxOwner  types.Transactions
-	)
-	// Categorise transactions by
-	// 1. 1 owner tx per block
-	// 2. multi txs owner per block
-	for _, txs := range txPerOwner {
-		if len(txs) == 1 {
-			singleTxOwner = append(singleTxOwner, txs[0])
-		} else {
-			multiTxOwner = append(multiTxOwner, txs...)
-		}
-	}
-	sort.Sort(types.TxByPrice(singleTxOwner))
-	sort.Sort(types.TxByNonce(multiTxOwner))
-	transactions := append(singleTxOwner, multiTxOwner...)
-	*/
+	// Order the pending transactions according to the configured policy.
+	transactions := self.selector.Select(self.eth.TxPool().GetTransactions())
 
 	work.commitTransactions(self.mux, transactions, self.gasPrice, self.chain)
 	self.eth.TxPool().RemoveTransactions(work.lowGasTxs)
 
 	// compute uncles for the new block.
-	var (
-		uncles    []*types.Header
-		badUncles []common.Hash
-	)
-	for hash, uncle := range self.possibleUncles {
-		if len(uncles) == 2 {
-			break
-		}
-		if err := self.commitUncle(work, uncle.Header()); err != nil {
-			if glog.V(logger.Ridiculousness) {
-				glog.V(logger.Detail).Infof("Bad uncle found and will be removed (%x)\n", hash[:4])
-				glog.V(logger.Detail).Infoln(uncle)
-			}
-			badUncles = append(badUncles, hash)
-		} else {
-			glog.V(logger.Debug).Infof("commiting %x as uncle\n", hash[:4])
-			uncles = append(uncles, uncle.Header())
-		}
-	}
+	uncles, badUncles := self.commitUncles(work)
 	for _, hash := range badUncles {
 		delete(self.possibleUncles, hash)
 	}
@@ -624,8 +632,10 @@ func (env *Work) commitTransactions(mux *event.TypeMux, transactions types.Trans
 			continue
 		}
 
-		// Check if it falls within margin. Txs from owned accounts are always processed.
-		if tx.GasPrice().Cmp(gasPrice) < 0 && !env.ownedAccounts.Has(from) {
+		// Check if it falls within margin. Txs from owned accounts and senders
+		// exempted by the selection policy are always processed.
+		exempt := env.ownedAccounts.Has(from) || env.selector.Exempt(from)
+		if tx.GasPrice().Cmp(gasPrice) < 0 && !exempt {
 			// ignore the transaction and transactor. We ignore the transactor
 			// because nonce will fail after ignoring this transaction so there's
 			// no point
@@ -640,7 +650,7 @@ func (env *Work) commitTransactions(mux *event.TypeMux, transactions types.Trans
 		if env.lowGasTransactors.Has(from) {
 			// add tx to the low gas set. This will be removed at the end of the run
 			// owned accounts are ignored
-			if !env.ownedAccounts.Has(from) {
+			if !exempt {
 				env.lowGasTxs = append(env.lowGasTxs, tx)
 			}
 			continue
@@ -677,7 +687,7 @@ func (env *Work) commitTransactions(mux *event.TypeMux, transactions types.Trans
 		}
 
 	}
-	if len(coalescedLogs) > 0 || env.tcount > 0 {
+	if mux != nil && (len(coalescedLogs) > 0 || env.tcount > 0) {
 		go func(logs vm.Logs, tcount int) {
 			if len(logs) > 0 {
 				mux.Post(core.PendingLogsEvent{Logs: logs})
@@ -0,0 +1,131 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package miner
+
+import (
+	"io/ioutil"
+	"math/big"
+	"os"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/ethdb"
+	"github.com/ethereumproject/go-ethereum/event"
+)
+
+var (
+	testBankKey, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testBank       = core.GenesisAccount{
+		Address: crypto.PubkeyToAddress(testBankKey.PublicKey),
+		Balance: new(big.Int).Mul(big.NewInt(1000), common.Ether),
+	}
+)
+
+// testBackend implements core.Backend on top of an in-memory chain.
+type testBackend struct {
+	am      *accounts.Manager
+	chain   *core.BlockChain
+	txPool  *core.TxPool
+	chainDb ethdb.Database
+	mux     *event.TypeMux
+}
+
+func (b *testBackend) AccountManager() *accounts.Manager { return b.am }
+func (b *testBackend) BlockChain() *core.BlockChain      { return b.chain }
+func (b *testBackend) TxPool() *core.TxPool              { return b.txPool }
+func (b *testBackend) ChainDb() ethdb.Database           { return b.chainDb }
+func (b *testBackend) DappDb() ethdb.Database            { return b.chainDb }
+func (b *testBackend) EventMux() *event.TypeMux          { return b.mux }
+
+func newTestBackend(t *testing.T) (*testBackend, func()) {
+	dir, err := ioutil.TempDir("", "miner-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	am, err := accounts.NewManager(dir, accounts.LightScryptN, accounts.LightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, _ := ethdb.NewMemDatabase()
+	core.WriteGenesisBlockForTesting(db, testBank)
+
+	mux := new(event.TypeMux)
+	config := core.MakeChainConfig()
+	chain, err := core.NewBlockChain(db, config, core.FakePow{}, mux)
+	if err != nil {
+		t.Fatal(err)
+	}
+	backend := &testBackend{
+		am:      am,
+		chain:   chain,
+		chainDb: db,
+		mux:     mux,
+		txPool:  core.NewTxPool(config, mux, chain.State, chain.GasLimit),
+	}
+	return backend, func() {
+		mux.Stop()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestBuildBlock(t *testing.T) {
+	backend, teardown := newTestBackend(t)
+	defer teardown()
+
+	w := newWorker(backend.chain.Config(), common.Address{0xc0}, backend)
+	head := backend.chain.CurrentBlock()
+
+	var txs types.Transactions
+	for nonce := uint64(0); nonce < 3; nonce++ {
+		tx, _ := types.NewTransaction(nonce, common.Address{1}, big.NewInt(1), big.NewInt(21000), big.NewInt(2), nil).SignECDSA(testBankKey)
+		txs = append(txs, tx)
+	}
+	// Transactions must not collide with the nonce of an included one
+	dup, _ := types.NewTransaction(0, common.Address{2}, big.NewInt(1), big.NewInt(21000), big.NewInt(2), nil).SignECDSA(testBankKey)
+
+	result, err := w.buildBlock(head, append(txs, dup))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := result.Block
+	if block.ParentHash() != head.Hash() {
+		t.Errorf("parent mismatch: have %x, want %x", block.ParentHash(), head.Hash())
+	}
+	if len(block.Transactions()) != len(txs) {
+		t.Fatalf("included %d transactions, want %d", len(block.Transactions()), len(txs))
+	}
+	if len(result.Skipped) != 1 || result.Skipped[0] != dup {
+		t.Errorf("skipped transactions mismatch: %v", result.Skipped)
+	}
+	if want := big.NewInt(3 * 21000); block.GasUsed().Cmp(want) != 0 {
+		t.Errorf("gas used mismatch: have %v, want %v", block.GasUsed(), want)
+	}
+	if want := big.NewInt(3 * 21000 * 2); result.Fees.Cmp(want) != 0 {
+		t.Errorf("fees mismatch: have %v, want %v", result.Fees, want)
+	}
+	if block.Root() != result.State.IntermediateRoot() {
+		t.Errorf("state root mismatch")
+	}
+	// Nothing may have been written to the chain
+	if current := backend.chain.CurrentBlock(); current.Hash() != head.Hash() {
+		t.Errorf("chain head moved to %x", current.Hash())
+	}
+}
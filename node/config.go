@@ -26,6 +26,7 @@ import (
 	"path/filepath"
 	"runtime"
 	"strings"
+	"time"
 
 	"github.com/ethereumproject/go-ethereum/common"
 	"github.com/ethereumproject/go-ethereum/crypto"
@@ -33,6 +34,7 @@ import (
 	"github.com/ethereumproject/go-ethereum/logger/glog"
 	"github.com/ethereumproject/go-ethereum/p2p/discover"
 	"github.com/ethereumproject/go-ethereum/p2p/nat"
+	"github.com/ethereumproject/go-ethereum/rpc"
 )
 
 var (
@@ -117,13 +119,29 @@ type Config struct {
 	// exposed.
 	HTTPModules []string
 
+	// HTTPVirtualHosts is the list of virtual hostnames which are allowed on
+	// incoming requests, which protects against DNS rebinding attacks. Requests
+	// by IP address are always accepted, '*' accepts all hosts and an empty list
+	// disables the check.
+	HTTPVirtualHosts []string
+
+	// HTTPPathPrefix is the URL path under which the HTTP RPC interface is
+	// served, e.g. "/rpc". If empty, it is served on all paths.
+	HTTPPathPrefix string
+
+	// HTTPTLSCert and HTTPTLSKey are the paths of a PEM encoded certificate and
+	// private key. If set, the HTTP RPC server only accepts HTTPS connections.
+	HTTPTLSCert string
+	HTTPTLSKey  string
+
 	// WSHost is the host interface on which to start the websocket RPC server. If
 	// this field is empty, no websocket API endpoint will be started.
 	WSHost string
 
 	// WSPort is the TCP port number on which to start the websocket RPC server. The
 	// default zero value is/ valid and will pick a port number randomly (useful for
-	// ephemeral nodes).
+	// ephemeral nodes). If the websocket endpoint equals the HTTP one, both RPC
+	// interfaces are served on a single listener.
 	WSPort int
 
 	// WSOrigins is the list of domain to accept websocket requests from. Please be
@@ -131,10 +149,43 @@ type Config struct {
 	// cannot verify the validity of the request header.
 	WSOrigins string
 
+	// WSVirtualHosts is the list of virtual hostnames which are allowed on
+	// incoming websocket handshakes, see HTTPVirtualHosts.
+	WSVirtualHosts []string
+
+	// WSPathPrefix is the URL path under which the websocket RPC interface is
+	// served, e.g. "/ws". If empty, it is served on all paths.
+	WSPathPrefix string
+
+	// WSTLSCert and WSTLSKey are the paths of a PEM encoded certificate and
+	// private key. If set, the websocket RPC server only accepts TLS connections.
+	WSTLSCert string
+	WSTLSKey  string
+
 	// WSModules is a list of API modules to expose via the websocket RPC interface.
 	// If the module list is empty, all RPC API endpoints designated public will be
 	// exposed.
 	WSModules []string
+
+	// IPCAccess restricts the methods callable via the IPC interface.
+	IPCAccess rpc.AccessPolicy
+
+	// HTTPAccess restricts the methods callable via the HTTP RPC interface and
+	// optionally requires clients to authenticate with a bearer token.
+	HTTPAccess rpc.AccessPolicy
+
+	// WSAccess restricts the methods callable via the websocket RPC interface and
+	// optionally requires clients to authenticate with a bearer token.
+	WSAccess rpc.AccessPolicy
+
+	// RPCLimits bounds the time, request size and concurrency the HTTP and
+	// websocket RPC servers spend on their clients. If nil, the defaults of the
+	// rpc package are used.
+	RPCLimits *rpc.Limits
+
+	// RPCSlowRequestThreshold is the execution time from which on IPC, HTTP and
+	// websocket RPC method calls are logged as slow. Zero disables logging.
+	RPCSlowRequestThreshold time.Duration
 }
 
 // IPCEndpoint resolves an IPC endpoint based on a configured value, taking into
@@ -0,0 +1,141 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package node
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+// rpcEndpoint is a listener serving the HTTP and the websocket RPC interfaces.
+// Both interfaces can share a single endpoint, in which case requests asking
+// for a websocket upgrade are dispatched to the websocket interface and all
+// others to the HTTP interface.
+type rpcEndpoint struct {
+	listener net.Listener
+	server   *http.Server
+	tlsCert  string // TLS certificate file of the listener (empty = plain TCP)
+
+	lock       sync.RWMutex
+	http       http.Handler            // HTTP RPC handler (nil = not served)
+	httpPrefix string                  // Path prefix of the HTTP RPC interface
+	handlers   map[string]http.Handler // Service HTTP handlers served along the HTTP RPC, by path
+	ws         http.Handler            // Websocket RPC handler (nil = not served)
+	wsPrefix   string                  // Path prefix of the websocket RPC interface
+}
+
+// ServeHTTP dispatches a request to the interface it is addressed to.
+func (e *rpcEndpoint) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	e.lock.RLock()
+	httpHandler, httpPrefix, handlers := e.http, e.httpPrefix, e.handlers
+	wsHandler, wsPrefix := e.ws, e.wsPrefix
+	e.lock.RUnlock()
+
+	if wsHandler != nil && rpc.IsWebsocket(r) && hasPathPrefix(r.URL.Path, wsPrefix) {
+		wsHandler.ServeHTTP(w, r)
+		return
+	}
+	for path, handler := range handlers {
+		if hasPathPrefix(r.URL.Path, path) {
+			handler.ServeHTTP(w, r)
+			return
+		}
+	}
+	if httpHandler != nil && hasPathPrefix(r.URL.Path, httpPrefix) {
+		httpHandler.ServeHTTP(w, r)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// idle returns whether the endpoint serves neither of the interfaces.
+func (e *rpcEndpoint) idle() bool {
+	e.lock.RLock()
+	defer e.lock.RUnlock()
+
+	return e.http == nil && e.ws == nil
+}
+
+// hasPathPrefix returns whether the path lies within the given prefix, which
+// must match whole path segments.
+func hasPathPrefix(path, prefix string) bool {
+	prefix = strings.TrimSuffix(prefix, "/")
+	if prefix == "" {
+		return true
+	}
+	return path == prefix || strings.HasPrefix(path, prefix+"/")
+}
+
+// openRPCEndpoint returns the listener of the given endpoint, starting it if
+// neither the HTTP nor the websocket interface is served on it yet. Sharing
+// an endpoint requires both interfaces to use the same TLS certificate.
+func (n *Node) openRPCEndpoint(endpoint, certFile, keyFile string) (*rpcEndpoint, error) {
+	if e, ok := n.rpcEndpoints[endpoint]; ok {
+		if e.tlsCert != certFile {
+			return nil, fmt.Errorf("conflicting TLS configuration on shared RPC endpoint %s", endpoint)
+		}
+		return e, nil
+	}
+	listener, err := listenRPC(endpoint, certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	e := &rpcEndpoint{listener: listener, tlsCert: certFile}
+	e.server = &http.Server{
+		Handler:      e,
+		ReadTimeout:  n.rpcLimits.ReadTimeout,
+		WriteTimeout: n.rpcLimits.WriteTimeout,
+		IdleTimeout:  n.rpcLimits.IdleTimeout,
+	}
+	go e.server.Serve(listener)
+
+	if n.rpcEndpoints == nil {
+		n.rpcEndpoints = make(map[string]*rpcEndpoint)
+	}
+	n.rpcEndpoints[endpoint] = e
+	return e, nil
+}
+
+// closeRPCEndpoint stops the listener of the given endpoint once neither the
+// HTTP nor the websocket interface is served on it anymore.
+func (n *Node) closeRPCEndpoint(endpoint string) {
+	e, ok := n.rpcEndpoints[endpoint]
+	if !ok || !e.idle() {
+		return
+	}
+	e.listener.Close()
+	delete(n.rpcEndpoints, endpoint)
+}
+
+// listenRPC opens a TCP listener for an RPC endpoint. If a certificate and key
+// file are given, the listener only accepts TLS connections.
+func listenRPC(endpoint, certFile, keyFile string) (net.Listener, error) {
+	if certFile == "" && keyFile == "" {
+		return net.Listen("tcp", endpoint)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	return tls.Listen("tcp", endpoint, &tls.Config{Certificates: []tls.Certificate{cert}})
+}
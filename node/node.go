@@ -19,12 +19,16 @@ package node
 
 import (
 	"errors"
+	"fmt"
 	"net"
+	"net/http"
 	"os"
 	"path/filepath"
 	"reflect"
+	"strings"
 	"sync"
 	"syscall"
+	"time"
 
 	"github.com/ethereumproject/go-ethereum/event"
 	"github.com/ethereumproject/go-ethereum/logger"
@@ -54,28 +58,44 @@ type Node struct {
 	serviceFuncs []ServiceConstructor     // Service constructors (in dependency order)
 	services     map[reflect.Type]Service // Currently running services
 
-	rpcAPIs       []rpc.API   // List of APIs currently provided by the node
-	inprocHandler *rpc.Server // In-process RPC request handler to process the API requests
-
-	ipcEndpoint string       // IPC endpoint to listen at (empty = IPC disabled)
-	ipcListener net.Listener // IPC RPC listener socket to serve API requests
-	ipcHandler  *rpc.Server  // IPC RPC request handler to process the API requests
-
-	httpHost      string       // HTTP hostname
-	httpPort      int          // HTTP post
-	httpEndpoint  string       // HTTP endpoint (interface + port) to listen at (empty = HTTP disabled)
-	httpWhitelist []string     // HTTP RPC modules to allow through this endpoint
-	httpCors      string       // HTTP RPC Cross-Origin Resource Sharing header
-	httpListener  net.Listener // HTTP RPC listener socket to server API requests
-	httpHandler   *rpc.Server  // HTTP RPC request handler to process the API requests
-
-	wsHost      string       // Websocket host
-	wsPort      int          // Websocket post
-	wsEndpoint  string       // Websocket endpoint (interface + port) to listen at (empty = websocket disabled)
-	wsWhitelist []string     // Websocket RPC modules to allow through this endpoint
-	wsOrigins   string       // Websocket RPC allowed origin domains
-	wsListener  net.Listener // Websocket RPC listener socket to server API requests
-	wsHandler   *rpc.Server  // Websocket RPC request handler to process the API requests
+	rpcAPIs       []rpc.API               // List of APIs currently provided by the node
+	rpcHandlers   map[string]http.Handler // HTTP handlers of the services served along the HTTP RPC
+	inprocHandler *rpc.Server             // In-process RPC request handler to process the API requests
+
+	ipcEndpoint string           // IPC endpoint to listen at (empty = IPC disabled)
+	ipcListener net.Listener     // IPC RPC listener socket to serve API requests
+	ipcHandler  *rpc.Server      // IPC RPC request handler to process the API requests
+	ipcAccess   rpc.AccessPolicy // IPC RPC methods allowed through this endpoint
+
+	httpHost      string           // HTTP hostname
+	httpPort      int              // HTTP post
+	httpEndpoint  string           // HTTP endpoint (interface + port) to listen at (empty = HTTP disabled)
+	httpWhitelist []string         // HTTP RPC modules to allow through this endpoint
+	httpCors      string           // HTTP RPC Cross-Origin Resource Sharing header
+	httpVhosts    []string         // HTTP RPC virtual hostnames accepted in the Host header
+	httpTLSCert   string           // HTTP RPC TLS certificate file (empty = plain HTTP)
+	httpTLSKey    string           // HTTP RPC TLS private key file
+	httpPrefix    string           // HTTP RPC path prefix (empty = all paths)
+	httpListener  *rpcEndpoint     // HTTP RPC listener, possibly shared with the websocket RPC
+	httpHandler   *rpc.Server      // HTTP RPC request handler to process the API requests
+	httpAccess    rpc.AccessPolicy // HTTP RPC methods and clients allowed through this endpoint
+
+	wsHost      string           // Websocket host
+	wsPort      int              // Websocket post
+	wsEndpoint  string           // Websocket endpoint (interface + port) to listen at (empty = websocket disabled)
+	wsWhitelist []string         // Websocket RPC modules to allow through this endpoint
+	wsOrigins   string           // Websocket RPC allowed origin domains
+	wsVhosts    []string         // Websocket RPC virtual hostnames accepted in the Host header
+	wsTLSCert   string           // Websocket RPC TLS certificate file (empty = plain websocket)
+	wsTLSKey    string           // Websocket RPC TLS private key file
+	wsPrefix    string           // Websocket RPC path prefix (empty = all paths)
+	wsListener  *rpcEndpoint     // Websocket RPC listener, possibly shared with the HTTP RPC
+	wsHandler   *rpc.Server      // Websocket RPC request handler to process the API requests
+	wsAccess    rpc.AccessPolicy // Websocket RPC methods and clients allowed through this endpoint
+
+	rpcLimits        rpc.Limits              // Resource limits of the HTTP and websocket RPC servers
+	rpcSlowThreshold time.Duration           // Execution time from which on RPC calls are logged as slow
+	rpcEndpoints     map[string]*rpcEndpoint // Listeners of the HTTP and websocket RPC interfaces by endpoint
 
 	stop chan struct{} // Channel to wait for termination notifications
 	lock sync.RWMutex
@@ -94,6 +114,10 @@ func New(conf *Config) (*Node, error) {
 	if conf.DataDir != "" {
 		nodeDbPath = filepath.Join(conf.DataDir, datadirNodeDatabase)
 	}
+	rpcLimits := rpc.DefaultLimits
+	if conf.RPCLimits != nil {
+		rpcLimits = *conf.RPCLimits
+	}
 	return &Node{
 		datadir: conf.DataDir,
 		serverConfig: p2p.Config{
@@ -111,19 +135,32 @@ func New(conf *Config) (*Node, error) {
 			MaxPeers:        conf.MaxPeers,
 			MaxPendingPeers: conf.MaxPendingPeers,
 		},
-		serviceFuncs:  []ServiceConstructor{},
-		ipcEndpoint:   conf.IPCEndpoint(),
-		httpHost:      conf.HTTPHost,
-		httpPort:      conf.HTTPPort,
-		httpEndpoint:  conf.HTTPEndpoint(),
-		httpWhitelist: conf.HTTPModules,
-		httpCors:      conf.HTTPCors,
-		wsHost:        conf.WSHost,
-		wsPort:        conf.WSPort,
-		wsEndpoint:    conf.WSEndpoint(),
-		wsWhitelist:   conf.WSModules,
-		wsOrigins:     conf.WSOrigins,
-		eventmux:      new(event.TypeMux),
+		serviceFuncs:     []ServiceConstructor{},
+		ipcEndpoint:      conf.IPCEndpoint(),
+		ipcAccess:        conf.IPCAccess,
+		httpHost:         conf.HTTPHost,
+		httpPort:         conf.HTTPPort,
+		httpEndpoint:     conf.HTTPEndpoint(),
+		httpWhitelist:    conf.HTTPModules,
+		httpCors:         conf.HTTPCors,
+		httpAccess:       conf.HTTPAccess,
+		httpVhosts:       conf.HTTPVirtualHosts,
+		httpTLSCert:      conf.HTTPTLSCert,
+		httpTLSKey:       conf.HTTPTLSKey,
+		httpPrefix:       conf.HTTPPathPrefix,
+		wsHost:           conf.WSHost,
+		wsPort:           conf.WSPort,
+		wsEndpoint:       conf.WSEndpoint(),
+		wsWhitelist:      conf.WSModules,
+		wsOrigins:        conf.WSOrigins,
+		wsAccess:         conf.WSAccess,
+		wsVhosts:         conf.WSVirtualHosts,
+		wsTLSCert:        conf.WSTLSCert,
+		wsTLSKey:         conf.WSTLSKey,
+		wsPrefix:         conf.WSPathPrefix,
+		rpcLimits:        rpcLimits,
+		rpcSlowThreshold: conf.RPCSlowRequestThreshold,
+		eventmux:         new(event.TypeMux),
 	}, nil
 }
 
@@ -220,9 +257,20 @@ func (n *Node) Start() error {
 func (n *Node) startRPC(services map[reflect.Type]Service) error {
 	// Gather all the possible APIs to surface
 	apis := n.apis()
+	handlers := make(map[string]http.Handler)
 	for _, service := range services {
 		apis = append(apis, service.APIs()...)
+		if service, ok := service.(HandlerService); ok {
+			for path, handler := range service.Handlers() {
+				if _, exists := handlers[path]; exists {
+					return fmt.Errorf("duplicate HTTP handler for path %s", path)
+				}
+				handlers[path] = handler
+			}
+		}
 	}
+	n.rpcHandlers = handlers
+
 	// Start the various API endpoints, terminating all in case of errors
 	if err := n.startInProc(apis); err != nil {
 		return err
@@ -231,12 +279,12 @@ func (n *Node) startRPC(services map[reflect.Type]Service) error {
 		n.stopInProc()
 		return err
 	}
-	if err := n.startHTTP(n.httpEndpoint, apis, n.httpWhitelist, n.httpCors); err != nil {
+	if err := n.startHTTP(n.httpEndpoint, apis, n.httpWhitelist, n.httpCors, n.httpVhosts); err != nil {
 		n.stopIPC()
 		n.stopInProc()
 		return err
 	}
-	if err := n.startWS(n.wsEndpoint, apis, n.wsWhitelist, n.wsOrigins); err != nil {
+	if err := n.startWS(n.wsEndpoint, apis, n.wsWhitelist, n.wsOrigins, n.wsVhosts); err != nil {
 		n.stopHTTP()
 		n.stopIPC()
 		n.stopInProc()
@@ -277,6 +325,8 @@ func (n *Node) startIPC(apis []rpc.API) error {
 	}
 	// Register all the APIs exposed by the services
 	handler := rpc.NewServer()
+	handler.SetAccessPolicy(n.ipcAccess)
+	handler.SetSlowRequestThreshold(n.rpcSlowThreshold)
 	for _, api := range apis {
 		if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
 			return err
@@ -333,7 +383,7 @@ func (n *Node) stopIPC() {
 }
 
 // startHTTP initializes and starts the HTTP RPC endpoint.
-func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors string) error {
+func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors string, vhosts []string) error {
 	// Short circuit if the HTTP endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
@@ -345,6 +395,9 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 	}
 	// Register all the APIs exposed by the services
 	handler := rpc.NewServer()
+	handler.SetLimits(n.rpcLimits)
+	handler.SetAccessPolicy(n.httpAccess)
+	handler.SetSlowRequestThreshold(n.rpcSlowThreshold)
 	for _, api := range apis {
 		if whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
@@ -353,22 +406,30 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 			glog.V(logger.Debug).Infof("HTTP registered %T under '%s'", api.Service, api.Namespace)
 		}
 	}
-	// All APIs registered, start the HTTP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
+	// All APIs registered, start the HTTP listener unless shared with the websocket one
+	listener, err := n.openRPCEndpoint(endpoint, n.httpTLSCert, n.httpTLSKey)
+	if err != nil {
 		return err
 	}
-	go rpc.NewHTTPServer(cors, handler).Serve(listener)
-	glog.V(logger.Info).Infof("HTTP endpoint opened: http://%s", endpoint)
+	handlers := make(map[string]http.Handler)
+	for path, h := range n.rpcHandlers {
+		handlers[path] = rpc.WrapHTTPHandler(cors, vhosts, handler, strings.Trim(path, "/"), h)
+	}
+	listener.lock.Lock()
+	listener.http, listener.httpPrefix = rpc.NewHTTPHandler(cors, vhosts, handler), n.httpPrefix
+	listener.handlers = handlers
+	listener.lock.Unlock()
+	glog.V(logger.Info).Infof("HTTP endpoint opened: %s", n.httpURL(endpoint))
+	for path := range handlers {
+		glog.V(logger.Info).Infof("HTTP handler opened: %s", n.httpBaseURL(endpoint)+path)
+	}
 
 	// All listeners booted successfully
 	n.httpEndpoint = endpoint
 	n.httpListener = listener
 	n.httpHandler = handler
 	n.httpCors = cors
+	n.httpVhosts = vhosts
 
 	return nil
 }
@@ -376,10 +437,13 @@ func (n *Node) startHTTP(endpoint string, apis []rpc.API, modules []string, cors
 // stopHTTP terminates the HTTP RPC endpoint.
 func (n *Node) stopHTTP() {
 	if n.httpListener != nil {
-		n.httpListener.Close()
+		n.httpListener.lock.Lock()
+		n.httpListener.http, n.httpListener.handlers = nil, nil
+		n.httpListener.lock.Unlock()
+		n.closeRPCEndpoint(n.httpEndpoint)
 		n.httpListener = nil
 
-		glog.V(logger.Info).Infof("HTTP endpoint closed: http://%s", n.httpEndpoint)
+		glog.V(logger.Info).Infof("HTTP endpoint closed: %s", n.httpURL(n.httpEndpoint))
 	}
 	if n.httpHandler != nil {
 		n.httpHandler.Stop()
@@ -388,7 +452,7 @@ func (n *Node) stopHTTP() {
 }
 
 // startWS initializes and starts the websocket RPC endpoint.
-func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrigins string) error {
+func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrigins string, vhosts []string) error {
 	// Short circuit if the WS endpoint isn't being exposed
 	if endpoint == "" {
 		return nil
@@ -400,6 +464,9 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 	}
 	// Register all the APIs exposed by the services
 	handler := rpc.NewServer()
+	handler.SetLimits(n.rpcLimits)
+	handler.SetAccessPolicy(n.wsAccess)
+	handler.SetSlowRequestThreshold(n.rpcSlowThreshold)
 	for _, api := range apis {
 		if whitelist[api.Namespace] || (len(whitelist) == 0 && api.Public) {
 			if err := handler.RegisterName(api.Namespace, api.Service); err != nil {
@@ -408,22 +475,22 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 			glog.V(logger.Debug).Infof("WebSocket registered %T under '%s'", api.Service, api.Namespace)
 		}
 	}
-	// All APIs registered, start the HTTP listener
-	var (
-		listener net.Listener
-		err      error
-	)
-	if listener, err = net.Listen("tcp", endpoint); err != nil {
+	// All APIs registered, start the HTTP listener unless shared with the HTTP RPC one
+	listener, err := n.openRPCEndpoint(endpoint, n.wsTLSCert, n.wsTLSKey)
+	if err != nil {
 		return err
 	}
-	go rpc.NewWSServer(wsOrigins, handler).Serve(listener)
-	glog.V(logger.Info).Infof("WebSocket endpoint opened: ws://%s", endpoint)
+	listener.lock.Lock()
+	listener.ws, listener.wsPrefix = rpc.NewWSHandler(wsOrigins, vhosts, handler), n.wsPrefix
+	listener.lock.Unlock()
+	glog.V(logger.Info).Infof("WebSocket endpoint opened: %s", n.wsURL(endpoint))
 
 	// All listeners booted successfully
 	n.wsEndpoint = endpoint
 	n.wsListener = listener
 	n.wsHandler = handler
 	n.wsOrigins = wsOrigins
+	n.wsVhosts = vhosts
 
 	return nil
 }
@@ -431,10 +498,13 @@ func (n *Node) startWS(endpoint string, apis []rpc.API, modules []string, wsOrig
 // stopWS terminates the websocket RPC endpoint.
 func (n *Node) stopWS() {
 	if n.wsListener != nil {
-		n.wsListener.Close()
+		n.wsListener.lock.Lock()
+		n.wsListener.ws = nil
+		n.wsListener.lock.Unlock()
+		n.closeRPCEndpoint(n.wsEndpoint)
 		n.wsListener = nil
 
-		glog.V(logger.Info).Infof("WebSocket endpoint closed: ws://%s", n.wsEndpoint)
+		glog.V(logger.Info).Infof("WebSocket endpoint closed: %s", n.wsURL(n.wsEndpoint))
 	}
 	if n.wsHandler != nil {
 		n.wsHandler.Stop()
@@ -442,6 +512,28 @@ func (n *Node) stopWS() {
 	}
 }
 
+// httpURL returns the URL of the HTTP RPC endpoint, respecting its TLS setting.
+func (n *Node) httpURL(endpoint string) string {
+	return n.httpBaseURL(endpoint) + n.httpPrefix
+}
+
+// httpBaseURL returns the URL of the root path of the HTTP RPC listener.
+func (n *Node) httpBaseURL(endpoint string) string {
+	if n.httpTLSCert != "" {
+		return "https://" + endpoint
+	}
+	return "http://" + endpoint
+}
+
+// wsURL returns the URL of the websocket RPC endpoint, respecting its TLS
+// setting.
+func (n *Node) wsURL(endpoint string) string {
+	if n.wsTLSCert != "" {
+		return "wss://" + endpoint + n.wsPrefix
+	}
+	return "ws://" + endpoint + n.wsPrefix
+}
+
 // Stop terminates a running node along with all it's services. In the node was
 // not started, an error is returned.
 func (n *Node) Stop() error {
@@ -457,6 +549,7 @@ func (n *Node) Stop() error {
 	n.stopHTTP()
 	n.stopIPC()
 	n.rpcAPIs = nil
+	n.rpcHandlers = nil
 
 	failure := &StopError{
 		Services: make(map[reflect.Type]error),
@@ -17,10 +17,13 @@
 package node
 
 import (
+	"context"
 	"errors"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 
@@ -591,3 +594,91 @@ func TestAPIGather(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the HTTP and websocket RPC interfaces can share a single endpoint
+// and path prefix.
+func TestNodeSharedRPCEndpoint(t *testing.T) {
+	config := testNodeConfig()
+	config.HTTPHost, config.WSHost = "127.0.0.1", "127.0.0.1"
+	config.HTTPPathPrefix, config.WSPathPrefix = "/rpc", "/rpc"
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	if len(stack.rpcEndpoints) != 1 {
+		t.Fatalf("RPC listener count mismatch: have %d, want 1", len(stack.rpcEndpoints))
+	}
+	addr := stack.httpListener.listener.Addr().String()
+
+	httpClient, _ := rpc.DialHTTP("http://" + addr + "/rpc")
+	defer httpClient.Close()
+	if _, err := httpClient.SupportedModules(); err != nil {
+		t.Errorf("HTTP request failed: %v", err)
+	}
+	wsClient, err := rpc.DialWebsocket(context.Background(), "ws://"+addr+"/rpc", "")
+	if err != nil {
+		t.Fatalf("failed to dial websocket: %v", err)
+	}
+	defer wsClient.Close()
+	if _, err := wsClient.SupportedModules(); err != nil {
+		t.Errorf("websocket request failed: %v", err)
+	}
+
+	resp, err := http.Post("http://"+addr+"/other", "application/json", strings.NewReader(`{"id":1,"method":"rpc_modules","version":"2.0"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("status mismatch outside of path prefix: have %d, want %d", resp.StatusCode, http.StatusNotFound)
+	}
+
+	// Stopping one interface must keep the other one alive
+	stack.stopWS()
+	if _, err := httpClient.SupportedModules(); err != nil {
+		t.Errorf("HTTP request failed after stopping websocket: %v", err)
+	}
+}
+
+// Tests that the HTTP handlers of services are served on the HTTP RPC listener
+// next to the RPC interface.
+func TestNodeServiceHandlers(t *testing.T) {
+	config := testNodeConfig()
+	config.HTTPHost = "127.0.0.1"
+	config.HTTPPathPrefix = "/rpc"
+
+	stack, err := New(config)
+	if err != nil {
+		t.Fatalf("failed to create protocol stack: %v", err)
+	}
+	if err := stack.Register(func(*ServiceContext) (Service, error) { return new(HandlerTestService), nil }); err != nil {
+		t.Fatalf("failed to register handler service: %v", err)
+	}
+	if err := stack.Start(); err != nil {
+		t.Fatalf("failed to start protocol stack: %v", err)
+	}
+	defer stack.Stop()
+
+	addr := stack.httpListener.listener.Addr().String()
+	resp, err := http.Get("http://" + addr + "/test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if string(body) != "handled" {
+		t.Errorf("handler response mismatch: have %q, want %q", body, "handled")
+	}
+
+	httpClient, _ := rpc.DialHTTP("http://" + addr + "/rpc")
+	defer httpClient.Close()
+	if _, err := httpClient.SupportedModules(); err != nil {
+		t.Errorf("HTTP request failed: %v", err)
+	}
+}
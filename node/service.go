@@ -17,6 +17,7 @@
 package node
 
 import (
+	"net/http"
 	"path/filepath"
 	"reflect"
 
@@ -82,3 +83,16 @@ type Service interface {
 	// are all terminated.
 	Stop() error
 }
+
+// HandlerService is a service which additionally serves HTTP handlers next to
+// the HTTP RPC interface of the node, e.g. to offer other query languages over
+// the same listener.
+type HandlerService interface {
+	Service
+
+	// Handlers retrieves the HTTP handlers the service wishes to serve, keyed
+	// by the absolute URL path they are served under, e.g. /graphql. The path
+	// without slashes names the handler in the HTTP RPC access policy and its
+	// requests count against the limits of the RPC method calls.
+	Handlers() map[string]http.Handler
+}
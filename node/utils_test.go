@@ -20,6 +20,7 @@
 package node
 
 import (
+	"net/http"
 	"reflect"
 
 	"github.com/ethereumproject/go-ethereum/p2p"
@@ -36,6 +37,15 @@ func (s *NoopService) Stop() error               { return nil }
 
 func NewNoopService(*ServiceContext) (Service, error) { return new(NoopService), nil }
 
+// HandlerTestService is a service serving a fixed HTTP handler at /test.
+type HandlerTestService struct{ NoopService }
+
+func (s *HandlerTestService) Handlers() map[string]http.Handler {
+	return map[string]http.Handler{"/test": http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("handled"))
+	})}
+}
+
 // Set of services all wrapping the base NoopService resulting in the same method
 // signatures but different outer types.
 type NoopServiceA struct{ NoopService }
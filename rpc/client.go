@@ -0,0 +1,761 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+)
+
+var (
+	// ErrClientQuit is returned when a call is made on a closed client.
+	ErrClientQuit = errors.New("client is closed")
+
+	// ErrNoResult is returned when the server responded without a result.
+	ErrNoResult = errors.New("no result in JSON-RPC response")
+
+	// ErrSubscriptionQueueOverflow is returned when a subscription was dropped
+	// because the consumer didn't keep up with the notifications.
+	ErrSubscriptionQueueOverflow = errors.New("subscription queue overflow")
+)
+
+const (
+	// maxClientSubscriptionBuffer is the number of notifications buffered for a
+	// subscription before it is dropped.
+	maxClientSubscriptionBuffer = 8000
+
+	// unsubscribeTimeout limits the time spent telling the server that a
+	// subscription is no longer wanted.
+	unsubscribeTimeout = 5 * time.Second
+)
+
+// BatchElem is an element in a batch request.
+type BatchElem struct {
+	Method string
+	Args   []interface{}
+	// Result is the value the response is unmarshaled into. It must be a non-nil
+	// pointer or the response is discarded.
+	Result interface{}
+	// Error is set when the server returned an error for this request, or when
+	// the result could not be unmarshaled. It is not set for I/O errors.
+	Error error
+}
+
+// jsonrpcMessage is any message a client sends or receives: a request, a
+// response or a subscription notification.
+type jsonrpcMessage struct {
+	Version string          `json:"jsonrpc"`
+	Id      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method,omitempty"`
+	Params  json.RawMessage `json:"params,omitempty"`
+	Error   *JSONError      `json:"error,omitempty"`
+	Result  json.RawMessage `json:"result,omitempty"`
+}
+
+func (msg *jsonrpcMessage) isNotification() bool {
+	return len(msg.Id) == 0 && msg.Method == notificationMethod
+}
+
+func (msg *jsonrpcMessage) isResponse() bool {
+	return len(msg.Id) > 0 && msg.Method == ""
+}
+
+// requestOp is a request (or batch of requests) waiting for its responses.
+type requestOp struct {
+	ids  []string
+	conn io.ReadWriteCloser   // connection the request was sent on
+	resp chan *jsonrpcMessage // receives a message per id
+	fail chan error           // receives an error when the connection is lost
+	sub  *ClientSubscription  // set for subscribe requests
+}
+
+func newRequestOp(msgs ...*jsonrpcMessage) *requestOp {
+	op := &requestOp{
+		resp: make(chan *jsonrpcMessage, len(msgs)),
+		fail: make(chan error, 1),
+	}
+	for _, msg := range msgs {
+		op.ids = append(op.ids, string(msg.Id))
+	}
+	return op
+}
+
+// ClientConn is a JSON-RPC client connected to a server over HTTP, websockets,
+// IPC or in-process. Requests are multiplexed by id, so a single connection can
+// be used concurrently. For the stream based transports (websockets, IPC and
+// in-process) the client reconnects on the next request after the connection
+// was lost; subscriptions don't survive a lost connection and report an error
+// instead.
+type ClientConn struct {
+	idCounter uint32
+
+	// HTTP transport
+	isHTTP   bool
+	endpoint string
+	http     *http.Client
+
+	// stream transports
+	connect func(ctx context.Context) (io.ReadWriteCloser, error)
+	writeMu sync.Mutex // serialises writes and (re)connects
+
+	mu      sync.Mutex // guards the fields below
+	conn    io.ReadWriteCloser
+	pending map[string]*requestOp
+	subs    map[string]*ClientSubscription
+	closed  bool
+}
+
+// Dial connects to the server at the given endpoint. The transport is selected
+// by the endpoint: http:// and https:// use HTTP, ws:// and wss:// websockets
+// and anything else is taken as the path of an IPC socket. The ipc: and rpc:
+// prefixes accepted by NewClient are supported as well.
+func Dial(endpoint string) (*ClientConn, error) {
+	return DialContext(context.Background(), endpoint)
+}
+
+// DialContext is like Dial, but the context bounds the initial connect.
+func DialContext(ctx context.Context, endpoint string) (*ClientConn, error) {
+	switch {
+	case strings.HasPrefix(endpoint, "http://"), strings.HasPrefix(endpoint, "https://"):
+		return DialHTTP(endpoint)
+	case strings.HasPrefix(endpoint, "rpc:"):
+		return DialHTTP(endpoint[4:])
+	case strings.HasPrefix(endpoint, "ws://"), strings.HasPrefix(endpoint, "wss://"):
+		return DialWebsocket(ctx, endpoint, "")
+	case strings.HasPrefix(endpoint, "ipc:"):
+		return DialIPC(ctx, endpoint[4:])
+	}
+	return DialIPC(ctx, endpoint)
+}
+
+// NewClientConn creates a ClientConn on top of a request-response Client, as
+// returned by NewClient. Every request waits for its response before the next
+// one is sent, and subscriptions are not supported. Closing the ClientConn
+// leaves the client open.
+func NewClientConn(client Client) *ClientConn {
+	return &ClientConn{
+		connect: func(context.Context) (io.ReadWriteCloser, error) {
+			return &clientStream{client: client, resp: make(chan []byte, 1), closed: make(chan struct{})}, nil
+		},
+		pending: make(map[string]*requestOp),
+		subs:    make(map[string]*ClientSubscription),
+	}
+}
+
+// clientStream presents a request-response Client as the stream of a ClientConn.
+type clientStream struct {
+	client Client
+	resp   chan []byte
+	buf    []byte
+	once   sync.Once
+	closed chan struct{}
+}
+
+// Write sends the request in p and queues its response for Read.
+func (s *clientStream) Write(p []byte) (int, error) {
+	var (
+		req  = json.RawMessage(p)
+		resp json.RawMessage
+	)
+	if err := s.client.Send(req); err != nil {
+		return 0, err
+	}
+	if err := s.client.Recv(&resp); err != nil {
+		return 0, err
+	}
+	select {
+	case s.resp <- resp:
+	case <-s.closed:
+		return 0, ErrClientQuit
+	}
+	return len(p), nil
+}
+
+func (s *clientStream) Read(p []byte) (int, error) {
+	for len(s.buf) == 0 {
+		select {
+		case s.buf = <-s.resp:
+		case <-s.closed:
+			return 0, io.EOF
+		}
+	}
+	n := copy(p, s.buf)
+	s.buf = s.buf[n:]
+	return n, nil
+}
+
+func (s *clientStream) Close() error {
+	s.once.Do(func() { close(s.closed) })
+	return nil
+}
+
+// newStreamClient creates a client for a stream transport and establishes the
+// initial connection.
+func newStreamClient(ctx context.Context, connect func(context.Context) (io.ReadWriteCloser, error)) (*ClientConn, error) {
+	c := &ClientConn{
+		connect: connect,
+		pending: make(map[string]*requestOp),
+		subs:    make(map[string]*ClientSubscription),
+	}
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	if _, err := c.connection(ctx); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// Close closes the client, aborting any in-flight requests and subscriptions.
+func (c *ClientConn) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.closed = true
+	if c.conn != nil {
+		c.conn.Close()
+		c.conn = nil
+	}
+}
+
+// SupportedModules returns the collection of API's the server offers.
+func (c *ClientConn) SupportedModules() (map[string]string, error) {
+	var modules map[string]string
+	err := c.Call(&modules, MetadataApi+"_modules")
+	return modules, err
+}
+
+// Call performs a JSON-RPC call with the given arguments and unmarshals the
+// result into result, which must be a pointer or nil.
+func (c *ClientConn) Call(result interface{}, method string, args ...interface{}) error {
+	return c.CallContext(context.Background(), result, method, args...)
+}
+
+// CallContext performs a JSON-RPC call with the given arguments. If the context
+// is canceled before the call has returned, CallContext returns immediately
+// with the context error. A nil result discards the response.
+func (c *ClientConn) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	msg, err := c.newMessage(method, args...)
+	if err != nil {
+		return err
+	}
+	op := newRequestOp(msg)
+	if err := c.send(ctx, op, msg); err != nil {
+		return err
+	}
+	resp, err := c.wait(ctx, op)
+	if err != nil {
+		return err
+	}
+	return unmarshalResult(resp, result)
+}
+
+// BatchCall sends all given requests as a single batch and waits for the server
+// to return a response for all of them.
+func (c *ClientConn) BatchCall(b []BatchElem) error {
+	return c.BatchCallContext(context.Background(), b)
+}
+
+// BatchCallContext sends all given requests as a single batch and waits for the
+// server to return a response for all of them. The returned error only reports
+// I/O and context errors; errors of individual requests are set in the Error
+// field of their BatchElem.
+func (c *ClientConn) BatchCallContext(ctx context.Context, b []BatchElem) error {
+	msgs := make([]*jsonrpcMessage, len(b))
+	byId := make(map[string]int, len(b))
+	for i, elem := range b {
+		msg, err := c.newMessage(elem.Method, elem.Args...)
+		if err != nil {
+			return err
+		}
+		msgs[i] = msg
+		byId[string(msg.Id)] = i
+	}
+	op := newRequestOp(msgs...)
+	if err := c.send(ctx, op, msgs); err != nil {
+		return err
+	}
+	for n := 0; n < len(b); n++ {
+		resp, err := c.wait(ctx, op)
+		if err != nil {
+			return err
+		}
+		i, ok := byId[string(resp.Id)]
+		if !ok {
+			continue
+		}
+		b[i].Error = unmarshalResult(resp, b[i].Result)
+	}
+	return nil
+}
+
+// unmarshalResult decodes the result of resp into result, or returns the error
+// the server responded with.
+func unmarshalResult(resp *jsonrpcMessage, result interface{}) error {
+	switch {
+	case resp.Error != nil:
+		return resp.Error
+	case result == nil:
+		return nil
+	case len(resp.Result) == 0:
+		return ErrNoResult
+	}
+	return json.Unmarshal(resp.Result, result)
+}
+
+// EthSubscribe registers a subscription in the "eth" namespace. See Subscribe.
+func (c *ClientConn) EthSubscribe(ctx context.Context, channel interface{}, args ...interface{}) (*ClientSubscription, error) {
+	return c.Subscribe(ctx, "eth", channel, args...)
+}
+
+// Subscribe calls the <namespace>_subscribe method with the given arguments,
+// which start with the name of the subscription, and delivers notifications to
+// channel. The channel must be a writable channel of the notification type.
+//
+// Notifications are buffered while the channel isn't read from. When the buffer
+// is full, or the connection is lost, the subscription ends with an error on
+// its Err channel. Subscriptions are not supported over HTTP.
+func (c *ClientConn) Subscribe(ctx context.Context, namespace string, channel interface{}, args ...interface{}) (*ClientSubscription, error) {
+	chanVal := reflect.ValueOf(channel)
+	if chanVal.Kind() != reflect.Chan || chanVal.Type().ChanDir()&reflect.SendDir == 0 {
+		panic("first argument to Subscribe must be a writable channel")
+	}
+	if chanVal.IsNil() {
+		panic("channel given to Subscribe must not be nil")
+	}
+	if c.isHTTP {
+		return nil, ErrNotificationsUnsupported
+	}
+
+	msg, err := c.newMessage(namespace+serviceMethodSeparator+"subscribe", args...)
+	if err != nil {
+		return nil, err
+	}
+	sub := newClientSubscription(c, namespace, chanVal)
+	op := newRequestOp(msg)
+	op.sub = sub
+
+	if err := c.send(ctx, op, msg); err != nil {
+		sub.quitWithError(err, false)
+		return nil, err
+	}
+	resp, err := c.wait(ctx, op)
+	if err == nil && resp.Error != nil {
+		err = resp.Error
+	}
+	if err != nil {
+		sub.quitWithError(err, true)
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (c *ClientConn) nextId() json.RawMessage {
+	id := atomic.AddUint32(&c.idCounter, 1)
+	return []byte(strconv.FormatUint(uint64(id), 10))
+}
+
+func (c *ClientConn) newMessage(method string, args ...interface{}) (*jsonrpcMessage, error) {
+	msg := &jsonrpcMessage{Version: JSONRPCVersion, Id: c.nextId(), Method: method}
+	if len(args) > 0 {
+		params, err := json.Marshal(args)
+		if err != nil {
+			return nil, err
+		}
+		msg.Params = params
+	}
+	return msg, nil
+}
+
+// send writes msg, which is either a message or a batch of messages, and
+// registers op to receive the responses.
+func (c *ClientConn) send(ctx context.Context, op *requestOp, msg interface{}) error {
+	if c.isHTTP {
+		return c.sendHTTP(ctx, op, msg)
+	}
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	conn, err := c.connection(ctx)
+	if err != nil {
+		return err
+	}
+	c.mu.Lock()
+	op.conn = conn
+	for _, id := range op.ids {
+		c.pending[id] = op
+	}
+	c.mu.Unlock()
+
+	if d, ok := conn.(interface {
+		SetWriteDeadline(time.Time) error
+	}); ok {
+		deadline, _ := ctx.Deadline()
+		d.SetWriteDeadline(deadline)
+	}
+	if err := json.NewEncoder(conn).Encode(msg); err != nil {
+		c.forget(op)
+		c.mu.Lock()
+		if c.conn == conn {
+			c.conn = nil
+		}
+		c.mu.Unlock()
+		conn.Close()
+		return err
+	}
+	return nil
+}
+
+// sendHTTP posts msg to the server and queues the responses on op.
+func (c *ClientConn) sendHTTP(ctx context.Context, op *requestOp, msg interface{}) error {
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest("POST", c.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("request failed: %s", resp.Status)
+	}
+
+	var raw json.RawMessage
+	if err := json.NewDecoder(resp.Body).Decode(&raw); err != nil {
+		return err
+	}
+	msgs, err := parseMessages(raw)
+	if err != nil {
+		return err
+	}
+	for _, m := range msgs {
+		select {
+		case op.resp <- m:
+		default: // more responses than requests
+		}
+	}
+	return nil
+}
+
+// wait returns the next response for op.
+func (c *ClientConn) wait(ctx context.Context, op *requestOp) (*jsonrpcMessage, error) {
+	select {
+	case resp := <-op.resp:
+		return resp, nil
+	case err := <-op.fail:
+		return nil, err
+	case <-ctx.Done():
+		c.forget(op)
+		return nil, ctx.Err()
+	}
+}
+
+// forget stops waiting for responses to op.
+func (c *ClientConn) forget(op *requestOp) {
+	if c.isHTTP {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, id := range op.ids {
+		delete(c.pending, id)
+	}
+}
+
+// connection returns the current connection, dialing a new one if the last was
+// lost. The caller must hold writeMu.
+func (c *ClientConn) connection(ctx context.Context) (io.ReadWriteCloser, error) {
+	c.mu.Lock()
+	conn, closed := c.conn, c.closed
+	c.mu.Unlock()
+
+	if closed {
+		return nil, ErrClientQuit
+	}
+	if conn != nil {
+		return conn, nil
+	}
+	conn, err := c.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	c.mu.Lock()
+	if c.closed {
+		c.mu.Unlock()
+		conn.Close()
+		return nil, ErrClientQuit
+	}
+	c.conn = conn
+	c.mu.Unlock()
+
+	go c.read(conn)
+	return conn, nil
+}
+
+// read dispatches the messages received on conn until it fails, after which
+// the requests and subscriptions using conn are aborted.
+func (c *ClientConn) read(conn io.ReadWriteCloser) {
+	dec := json.NewDecoder(conn)
+
+	var err error
+	for {
+		var raw json.RawMessage
+		if err = dec.Decode(&raw); err != nil {
+			break
+		}
+		msgs, perr := parseMessages(raw)
+		if perr != nil {
+			glog.V(logger.Debug).Infof("dropping invalid RPC message: %v", perr)
+			continue
+		}
+		for _, msg := range msgs {
+			c.dispatch(conn, msg)
+		}
+	}
+	conn.Close()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		err = ErrClientQuit
+	} else {
+		glog.V(logger.Debug).Infof("RPC connection lost: %v", err)
+	}
+	if c.conn == conn {
+		c.conn = nil
+	}
+	for id, op := range c.pending {
+		if op.conn == conn {
+			delete(c.pending, id)
+			select {
+			case op.fail <- err:
+			default: // batch request already failed
+			}
+		}
+	}
+	for id, sub := range c.subs {
+		if sub.conn == conn {
+			delete(c.subs, id)
+			sub.quitWithError(err, false)
+		}
+	}
+}
+
+// dispatch delivers a received message to the request or subscription it is
+// meant for.
+func (c *ClientConn) dispatch(conn io.ReadWriteCloser, msg *jsonrpcMessage) {
+	switch {
+	case msg.isNotification():
+		var params struct {
+			Subscription string          `json:"subscription"`
+			Result       json.RawMessage `json:"result"`
+		}
+		if err := json.Unmarshal(msg.Params, &params); err != nil {
+			glog.V(logger.Debug).Infof("dropping invalid subscription notification: %v", err)
+			return
+		}
+		c.mu.Lock()
+		sub := c.subs[params.Subscription]
+		c.mu.Unlock()
+		if sub != nil {
+			sub.deliver(params.Result)
+		}
+
+	case msg.isResponse():
+		c.mu.Lock()
+		op := c.pending[string(msg.Id)]
+		delete(c.pending, string(msg.Id))
+		// Register subscriptions before any further message is read, as the
+		// first notifications follow right after the response.
+		if op != nil && op.sub != nil && msg.Error == nil {
+			if err := json.Unmarshal(msg.Result, &op.sub.id); err == nil {
+				op.sub.conn = conn
+				c.subs[op.sub.id] = op.sub
+			}
+		}
+		c.mu.Unlock()
+		if op != nil {
+			op.resp <- msg
+		}
+
+	default:
+		glog.V(logger.Debug).Infof("dropping unexpected RPC message: %s", msg.Method)
+	}
+}
+
+// parseMessages decodes a single message or a batch of messages.
+func parseMessages(raw json.RawMessage) ([]*jsonrpcMessage, error) {
+	if !isBatch(raw) {
+		msg := new(jsonrpcMessage)
+		if err := json.Unmarshal(raw, msg); err != nil {
+			return nil, err
+		}
+		return []*jsonrpcMessage{msg}, nil
+	}
+	var msgs []*jsonrpcMessage
+	if err := json.Unmarshal(raw, &msgs); err != nil {
+		return nil, err
+	}
+	return msgs, nil
+}
+
+// ClientSubscription is a subscription established through ClientConn.
+type ClientSubscription struct {
+	client    *ClientConn
+	namespace string
+	channel   reflect.Value
+	etype     reflect.Type
+
+	id   string             // assigned by the server, guarded by client.mu
+	conn io.ReadWriteCloser // guarded by client.mu
+
+	in       chan json.RawMessage
+	err      chan error
+	quit     chan struct{}
+	quitOnce sync.Once
+}
+
+func newClientSubscription(c *ClientConn, namespace string, channel reflect.Value) *ClientSubscription {
+	sub := &ClientSubscription{
+		client:    c,
+		namespace: namespace,
+		channel:   channel,
+		etype:     channel.Type().Elem(),
+		in:        make(chan json.RawMessage),
+		err:       make(chan error, 1),
+		quit:      make(chan struct{}),
+	}
+	go sub.forward()
+	return sub
+}
+
+// Err returns the subscription error channel. It receives a value if the
+// subscription ends because of an error and is closed when the subscription
+// ends, either way.
+func (sub *ClientSubscription) Err() <-chan error {
+	return sub.err
+}
+
+// Unsubscribe stops the delivery of notifications and tells the server to
+// cancel the subscription. It can be called more than once.
+func (sub *ClientSubscription) Unsubscribe() {
+	sub.quitWithError(nil, true)
+}
+
+// quitWithError ends the subscription, reporting err if it isn't nil. With
+// unsubscribe set the server is told to cancel the subscription.
+func (sub *ClientSubscription) quitWithError(err error, unsubscribe bool) {
+	sub.quitOnce.Do(func() {
+		if err != nil {
+			sub.err <- err
+		}
+		close(sub.err)
+		close(sub.quit)
+		if unsubscribe {
+			sub.requestUnsubscribe()
+		}
+	})
+}
+
+func (sub *ClientSubscription) requestUnsubscribe() {
+	c := sub.client
+	c.mu.Lock()
+	id := sub.id
+	if id != "" && c.subs[id] == sub {
+		delete(c.subs, id)
+	}
+	c.mu.Unlock()
+	if id == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), unsubscribeTimeout)
+	defer cancel()
+	c.CallContext(ctx, nil, sub.namespace+serviceMethodSeparator+"unsubscribe", id)
+}
+
+// deliver hands a notification to the forwarding loop.
+func (sub *ClientSubscription) deliver(result json.RawMessage) {
+	select {
+	case sub.in <- result:
+	case <-sub.quit:
+	}
+}
+
+// forward decodes notifications and sends them to the subscription channel,
+// buffering them while the channel isn't read from.
+func (sub *ClientSubscription) forward() {
+	var (
+		queue []reflect.Value
+		cases = []reflect.SelectCase{
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(sub.quit)},
+			{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(sub.in)},
+			{Dir: reflect.SelectSend, Chan: sub.channel},
+		}
+	)
+	for {
+		n := 2
+		if len(queue) > 0 {
+			cases[2].Send = queue[0]
+			n = 3
+		}
+		chosen, recv, _ := reflect.Select(cases[:n])
+		switch chosen {
+		case 0: // subscription ended
+			return
+		case 1: // notification received
+			val := reflect.New(sub.etype)
+			if err := json.Unmarshal(recv.Interface().(json.RawMessage), val.Interface()); err != nil {
+				sub.quitWithError(err, true)
+				return
+			}
+			if len(queue) >= maxClientSubscriptionBuffer {
+				sub.quitWithError(ErrSubscriptionQueueOverflow, true)
+				return
+			}
+			queue = append(queue, val.Elem())
+		case 2: // notification sent
+			queue[0] = reflect.Value{}
+			queue = queue[1:]
+		}
+	}
+}
@@ -0,0 +1,330 @@
+// Copyright 2016 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package rpc
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+type ClientTestService struct{}
+
+func (s *ClientTestService) Echo(str string, i int, args *Args) Result {
+	return Result{str, i, args}
+}
+
+func (s *ClientTestService) Fail() (string, error) {
+	return "", ErrNoResult
+}
+
+func (s *ClientTestService) Sleep(ctx context.Context, d time.Duration) {
+	time.Sleep(d)
+}
+
+func newClientTestServer(t *testing.T) *Server {
+	server := NewServer()
+	if err := server.RegisterName("test", new(ClientTestService)); err != nil {
+		t.Fatal(err)
+	}
+	if err := server.RegisterName("eth", new(NotificationTestService)); err != nil {
+		t.Fatal(err)
+	}
+	return server
+}
+
+func TestClientCall(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var result Result
+	if err := client.Call(&result, "test_echo", "hello", 10, &Args{"world"}); err != nil {
+		t.Fatal(err)
+	}
+	if want := (Result{"hello", 10, &Args{"world"}}); !reflect.DeepEqual(result, want) {
+		t.Errorf("result mismatch: got %#v, want %#v", result, want)
+	}
+	if err := client.Call(nil, "test_fail"); err == nil || err.Error() != ErrNoResult.Error() {
+		t.Errorf("expected server error %q, got %v", ErrNoResult, err)
+	}
+}
+
+func TestClientConnFromClient(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	inproc := NewInProcRPCClient(server)
+	defer inproc.Close()
+	client := NewClientConn(inproc)
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		var result Result
+		if err := client.Call(&result, "test_echo", "hello", i, &Args{"world"}); err != nil {
+			t.Fatal(err)
+		}
+		if want := (Result{"hello", i, &Args{"world"}}); !reflect.DeepEqual(result, want) {
+			t.Errorf("result mismatch: got %#v, want %#v", result, want)
+		}
+	}
+	batch := []BatchElem{
+		{Method: "test_echo", Args: []interface{}{"hello", 10, &Args{"world"}}, Result: new(Result)},
+		{Method: "test_noSuchMethod", Result: new(int)},
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	if r := batch[0].Result.(*Result); batch[0].Error != nil || r.Int != 10 || batch[1].Error == nil {
+		t.Errorf("batch mismatch: result %#v, errors %v, %v", r, batch[0].Error, batch[1].Error)
+	}
+}
+
+func TestClientConcurrentCalls(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var result Result
+			if err := client.Call(&result, "test_echo", "x", i, nil); err != nil {
+				t.Error(err)
+				return
+			}
+			if result.Int != i {
+				t.Errorf("response mixed up: got %d, want %d", result.Int, i)
+			}
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestClientBatchCall(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	batch := []BatchElem{
+		{Method: "test_echo", Args: []interface{}{"hello", 10, &Args{"world"}}, Result: new(Result)},
+		{Method: "test_echo", Args: []interface{}{"hello2", 11, &Args{"world"}}, Result: new(Result)},
+		{Method: "test_noSuchMethod", Args: []interface{}{1, 2, 3}, Result: new(int)},
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	if r := batch[0].Result.(*Result); batch[0].Error != nil || r.String != "hello" || r.Int != 10 {
+		t.Errorf("first element: result %#v, error %v", r, batch[0].Error)
+	}
+	if r := batch[1].Result.(*Result); batch[1].Error != nil || r.String != "hello2" || r.Int != 11 {
+		t.Errorf("second element: result %#v, error %v", r, batch[1].Error)
+	}
+	if batch[2].Error == nil {
+		t.Errorf("expected error for unknown method")
+	}
+}
+
+func TestClientCallTimeout(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := client.CallContext(ctx, nil, "test_sleep", time.Second); err != context.DeadlineExceeded {
+		t.Fatalf("expected %v, got %v", context.DeadlineExceeded, err)
+	}
+	// the client must stay usable after a timed out call
+	var result Result
+	if err := client.Call(&result, "test_echo", "hello", 1, nil); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestClientSubscribe(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	n, val := 10, 1000
+	ch := make(chan int)
+	sub, err := client.EthSubscribe(context.Background(), ch, "someSubscription", n, val)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case v := <-ch:
+			if v != val+i {
+				t.Fatalf("notification %d: got %d, want %d", i, v, val+i)
+			}
+		case err := <-sub.Err():
+			t.Fatalf("subscription failed: %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for notification %d", i)
+		}
+	}
+	sub.Unsubscribe()
+	if _, ok := <-sub.Err(); ok {
+		t.Errorf("expected closed error channel after unsubscribe")
+	}
+}
+
+func TestClientSubscribeConnectionLoss(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	client := DialInProc(server)
+	defer client.Close()
+
+	ch := make(chan int)
+	sub, err := client.EthSubscribe(context.Background(), ch, "someSubscription", 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// drop the connection underneath the client
+	client.mu.Lock()
+	client.conn.Close()
+	client.mu.Unlock()
+
+	select {
+	case err := <-sub.Err():
+		if err == nil {
+			t.Fatal("expected an error for the lost connection")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscription not ended after connection loss")
+	}
+	// the next call reconnects
+	var result Result
+	if err := client.Call(&result, "test_echo", "hello", 1, nil); err != nil {
+		t.Fatalf("call after connection loss: %v", err)
+	}
+}
+
+func TestClientHTTP(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	httpsrv := httptest.NewServer(NewHTTPServer("*", nil, server).Handler)
+	defer httpsrv.Close()
+
+	client, err := Dial(httpsrv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result Result
+	if err := client.Call(&result, "test_echo", "hello", 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if result.String != "hello" || result.Int != 10 {
+		t.Errorf("unexpected result %#v", result)
+	}
+	batch := []BatchElem{
+		{Method: "test_echo", Args: []interface{}{"a", 1, nil}, Result: new(Result)},
+		{Method: "test_echo", Args: []interface{}{"b", 2, nil}, Result: new(Result)},
+	}
+	if err := client.BatchCall(batch); err != nil {
+		t.Fatal(err)
+	}
+	for i, elem := range batch {
+		if elem.Error != nil || elem.Result.(*Result).Int != i+1 {
+			t.Errorf("batch element %d: result %#v, error %v", i, elem.Result, elem.Error)
+		}
+	}
+	if _, err := client.EthSubscribe(context.Background(), make(chan int), "someSubscription", 1, 1); err != ErrNotificationsUnsupported {
+		t.Errorf("expected %v, got %v", ErrNotificationsUnsupported, err)
+	}
+}
+
+func TestClientWebsocket(t *testing.T) {
+	server := newClientTestServer(t)
+	defer server.Stop()
+	wssrv := httptest.NewServer(NewWSServer("*", nil, server).Handler)
+	defer wssrv.Close()
+
+	client, err := Dial("ws" + wssrv.URL[len("http"):])
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result Result
+	if err := client.Call(&result, "test_echo", "hello", 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if result.String != "hello" || result.Int != 10 {
+		t.Errorf("unexpected result %#v", result)
+	}
+}
+
+func TestClientIPC(t *testing.T) {
+	dir, err := ioutil.TempDir("", "rpc-client-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	endpoint := filepath.Join(dir, "test.ipc")
+
+	server := newClientTestServer(t)
+	defer server.Stop()
+	listener, err := CreateIPCListener(endpoint)
+	if err != nil {
+		t.Skipf("can't listen on IPC endpoint: %v", err)
+	}
+	defer listener.Close()
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.ServeCodec(NewJSONCodec(conn), OptionMethodInvocation|OptionSubscriptions)
+		}
+	}()
+
+	client, err := Dial(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	var result Result
+	if err := client.Call(&result, "test_echo", "hello", 10, nil); err != nil {
+		t.Fatal(err)
+	}
+	if result.String != "hello" || result.Int != 10 {
+		t.Errorf("unexpected result %#v", result)
+	}
+	client.Close()
+	if err := client.Call(&result, "test_echo", "hello", 10, nil); err != ErrClientQuit {
+		t.Errorf("expected %v after close, got %v", ErrClientQuit, err)
+	}
+}
@@ -32,6 +32,20 @@ func (e *methodNotFoundError) Error() string {
 	return fmt.Sprintf("The method %s%s%s does not exist/is not available", e.service, serviceMethodSeparator, e.method)
 }
 
+// request is for a method the access policy of the server denies
+type methodNotAllowedError struct {
+	service string
+	method  string
+}
+
+func (e *methodNotAllowedError) Code() int {
+	return -32006
+}
+
+func (e *methodNotAllowedError) Error() string {
+	return fmt.Sprintf("The method %s%s%s is not allowed", e.service, serviceMethodSeparator, e.method)
+}
+
 // received message isn't a valid request
 type invalidRequestError struct {
 	message string
@@ -95,3 +109,27 @@ func (e *shutdownError) Code() int {
 func (e *shutdownError) Error() string {
 	return "server is shutting down"
 }
+
+// issued when a request didn't complete within the handler timeout of the server.
+type requestTimeoutError struct {
+}
+
+func (e *requestTimeoutError) Code() int {
+	return -32002
+}
+
+func (e *requestTimeoutError) Error() string {
+	return "request timed out"
+}
+
+// issued when the server is already processing its maximum number of requests.
+type limitExceededError struct {
+}
+
+func (e *limitExceededError) Code() int {
+	return -32005
+}
+
+func (e *limitExceededError) Error() string {
+	return "too many requests in flight"
+}
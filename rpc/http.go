@@ -18,20 +18,18 @@ package rpc
 
 import (
 	"bytes"
+	"crypto/subtle"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
+	"net"
 	"net/http"
 	"strings"
 
 	"github.com/rs/cors"
 )
 
-const (
-	maxHTTPRequestContentLength = 1024 * 128
-)
-
 // httpClient connects to a geth RPC server over HTTP.
 type httpClient struct {
 	endpoint   string      // HTTP-RPC server endpoint
@@ -64,6 +62,12 @@ func (client *httpClient) Send(msg interface{}) error {
 	return fmt.Errorf("request failed: %s", resp.Status)
 }
 
+// DialHTTP creates a client which sends its requests to the given HTTP-RPC
+// endpoint. No connection is made until the first request.
+func DialHTTP(endpoint string) (*ClientConn, error) {
+	return &ClientConn{isHTTP: true, endpoint: endpoint, http: new(http.Client)}, nil
+}
+
 // Recv will try to deserialize the last received response into the given msg.
 func (client *httpClient) Recv(msg interface{}) error {
 	return json.Unmarshal(client.lastRes, &msg)
@@ -89,17 +93,85 @@ func (t *httpReadWriteNopCloser) Close() error {
 	return nil
 }
 
-// newJSONHTTPHandler creates a HTTP handler that will parse incoming JSON requests,
-// send the request to the given API provider and sends the response back to the caller.
-func newJSONHTTPHandler(srv *Server) http.HandlerFunc {
+// authorized returns whether the HTTP request carries one of the bearer
+// tokens of the access policy, or whether authentication is disabled.
+func (s *Server) authorized(r *http.Request) bool {
+	if len(s.access.Tokens) == 0 {
+		return true
+	}
+	header := r.Header.Get("Authorization")
+	if !strings.HasPrefix(header, "Bearer ") {
+		return false
+	}
+	token := []byte(strings.TrimPrefix(header, "Bearer "))
+	for _, t := range s.access.Tokens {
+		if subtle.ConstantTimeCompare(token, []byte(t)) == 1 {
+			return true
+		}
+	}
+	return false
+}
+
+// newLimitedHandler rejects requests lacking a bearer token of the access
+// policy of the API provider and stops reading request bodies at its maximum
+// request size before passing them on to the given handler.
+func newLimitedHandler(srv *Server, next http.Handler) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		if r.ContentLength > maxHTTPRequestContentLength {
+		if !srv.authorized(r) {
+			http.Error(w, "missing or invalid bearer token", http.StatusUnauthorized)
+			return
+		}
+
+		maxSize := srv.limits.MaxRequestSize
+		if maxSize > 0 && r.ContentLength > maxSize {
 			http.Error(w,
-				fmt.Sprintf("content length too large (%d>%d)", r.ContentLength, maxHTTPRequestContentLength),
+				fmt.Sprintf("content length too large (%d>%d)", r.ContentLength, maxSize),
 				http.StatusRequestEntityTooLarge)
 			return
 		}
+		// the content length can be omitted or lie, stop reading at the limit
+		if maxSize > 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxSize)
+		}
+		next.ServeHTTP(w, r)
+	}
+}
 
+// newBoundedHandler serves the requests to a HTTP handler the way the API
+// provider executes method calls of the given namespace: requests are rejected
+// when the access policy denies the namespace or when all requests in flight
+// are taken, and are answered with an error once they exceed the handler
+// timeout. A request which timed out holds its slot until the handler returns.
+func newBoundedHandler(srv *Server, namespace string, next http.Handler) http.Handler {
+	limited := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if srv.inflight != nil {
+			select {
+			case srv.inflight <- struct{}{}:
+				defer func() { <-srv.inflight }()
+			default:
+				http.Error(w, (&limitExceededError{}).Error(), http.StatusServiceUnavailable)
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+	var handler http.Handler = limited
+	if srv.limits.HandlerTimeout > 0 {
+		handler = http.TimeoutHandler(limited, srv.limits.HandlerTimeout, (&requestTimeoutError{}).Error())
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !srv.allowed(namespace, "") {
+			http.Error(w, fmt.Sprintf("namespace %s is not allowed", namespace), http.StatusForbidden)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// newJSONHTTPHandler creates a HTTP handler that will parse incoming JSON requests,
+// send the request to the given API provider and sends the response back to the caller.
+func newJSONHTTPHandler(srv *Server) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("content-type", "application/json")
 
 		// create a codec that reads direct from the request body until
@@ -111,8 +183,65 @@ func newJSONHTTPHandler(srv *Server) http.HandlerFunc {
 	}
 }
 
-// NewHTTPServer creates a new HTTP RPC server around an API provider.
-func NewHTTPServer(corsString string, srv *Server) *http.Server {
+// virtualHostHandler rejects requests whose Host header doesn't name one of
+// the allowed virtual hosts, which protects against DNS rebinding attacks.
+// Requests addressing the server by IP address are always accepted.
+type virtualHostHandler struct {
+	vhosts  map[string]bool
+	allowed bool // whether all hosts are allowed
+	next    http.Handler
+}
+
+// newVirtualHostHandler wraps the given handler in a virtual host filter. An
+// empty list of virtual hosts disables the filter, a '*' allows all hosts.
+func newVirtualHostHandler(vhosts []string, next http.Handler) http.Handler {
+	h := &virtualHostHandler{vhosts: make(map[string]bool), next: next}
+	for _, vhost := range vhosts {
+		vhost = strings.ToLower(strings.TrimSpace(vhost))
+		if vhost == "*" {
+			h.allowed = true
+		}
+		if vhost != "" {
+			h.vhosts[vhost] = true
+		}
+	}
+	if len(h.vhosts) == 0 {
+		h.allowed = true
+	}
+	return h
+}
+
+// ServeHTTP serves the request if its Host header is allowed.
+func (h *virtualHostHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	host, _, err := net.SplitHostPort(r.Host)
+	if err != nil {
+		// the host header doesn't contain a port
+		host = r.Host
+	}
+	if h.allowed || net.ParseIP(host) != nil || h.vhosts[strings.ToLower(host)] {
+		h.next.ServeHTTP(w, r)
+		return
+	}
+	http.Error(w, "invalid host specified", http.StatusForbidden)
+}
+
+// NewHTTPHandler creates a HTTP handler serving JSON-RPC requests to an API
+// provider, see WrapHTTPHandler.
+func NewHTTPHandler(corsString string, vhosts []string, srv *Server) http.Handler {
+	return wrapHTTPHandler(corsString, vhosts, srv, newJSONHTTPHandler(srv))
+}
+
+// WrapHTTPHandler subjects a HTTP handler to the same checks as the JSON-RPC
+// requests to an API provider: requests are only accepted from the given CORS
+// origins and for the given virtual hosts, see newVirtualHostHandler, and must
+// carry a bearer token and respect the maximum request size of the provider.
+// The handler is treated like the methods of the given namespace, see
+// newBoundedHandler.
+func WrapHTTPHandler(corsString string, vhosts []string, srv *Server, namespace string, handler http.Handler) http.Handler {
+	return wrapHTTPHandler(corsString, vhosts, srv, newBoundedHandler(srv, namespace, handler))
+}
+
+func wrapHTTPHandler(corsString string, vhosts []string, srv *Server, handler http.Handler) http.Handler {
 	var allowedOrigins []string
 	for _, domain := range strings.Split(corsString, ",") {
 		allowedOrigins = append(allowedOrigins, strings.TrimSpace(domain))
@@ -121,11 +250,21 @@ func NewHTTPServer(corsString string, srv *Server) *http.Server {
 	c := cors.New(cors.Options{
 		AllowedOrigins: allowedOrigins,
 		AllowedMethods: []string{"POST", "GET"},
+		AllowedHeaders: []string{"Origin", "Accept", "Content-Type", "Authorization"},
 	})
 
-	handler := c.Handler(newJSONHTTPHandler(srv))
+	return newVirtualHostHandler(vhosts, c.Handler(newLimitedHandler(srv, handler)))
+}
 
+// NewHTTPServer creates a new HTTP RPC server around an API provider, see
+// NewHTTPHandler. The connection timeouts are taken from the limits of the API
+// provider.
+func NewHTTPServer(corsString string, vhosts []string, srv *Server) *http.Server {
+	limits := srv.Limits()
 	return &http.Server{
-		Handler: handler,
+		Handler:      NewHTTPHandler(corsString, vhosts, srv),
+		ReadTimeout:  limits.ReadTimeout,
+		WriteTimeout: limits.WriteTimeout,
+		IdleTimeout:  limits.IdleTimeout,
 	}
 }
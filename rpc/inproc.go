@@ -17,6 +17,7 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
 	"io"
 	"net"
@@ -43,6 +44,17 @@ func NewInProcRPCClient(handler *Server) Client {
 	return &inProcClient{handler, p2, json.NewEncoder(p2), json.NewDecoder(p2)}
 }
 
+// DialInProc creates a client attached to the given RPC server within the
+// same process.
+func DialInProc(handler *Server) *ClientConn {
+	c, _ := newStreamClient(context.Background(), func(context.Context) (io.ReadWriteCloser, error) {
+		p1, p2 := net.Pipe()
+		go handler.ServeCodec(NewJSONCodec(p1), OptionMethodInvocation|OptionSubscriptions)
+		return p2, nil
+	})
+	return c
+}
+
 // Send marshals a message into a json format and injects in into the client
 // request channel.
 func (c *inProcClient) Send(msg interface{}) error {
@@ -17,7 +17,9 @@
 package rpc
 
 import (
+	"context"
 	"encoding/json"
+	"io"
 	"net"
 )
 
@@ -46,6 +48,14 @@ func newIPCClient(endpoint string) (Client, error) {
 	return &ipcClient{endpoint: endpoint, conn: conn, in: json.NewDecoder(conn), out: json.NewEncoder(conn)}, nil
 }
 
+// DialIPC creates a client which connects to the given IPC endpoint. It
+// reconnects on the next request when the connection is lost.
+func DialIPC(ctx context.Context, endpoint string) (*ClientConn, error) {
+	return newStreamClient(ctx, func(ctx context.Context) (io.ReadWriteCloser, error) {
+		return newIPCConnection(endpoint)
+	})
+}
+
 // Send will serialize the given message and send it to the server.
 // When sending the message fails it will try to reconnect once and send the message again.
 func (client *ipcClient) Send(msg interface{}) error {
@@ -60,6 +60,14 @@ type JSONError struct {
 	Data    interface{} `json:"data,omitempty"`
 }
 
+// Error implements the error interface for errors returned by a server.
+func (err *JSONError) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("json-rpc error %d", err.Code)
+	}
+	return err.Message
+}
+
 // JSON-RPC notification payload
 type jsonSubscription struct {
 	Subscription string      `json:"subscription"`
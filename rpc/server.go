@@ -28,6 +28,7 @@ import (
 
 	"github.com/ethereumproject/go-ethereum/logger"
 	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/ethereumproject/go-ethereum/metrics"
 )
 
 const (
@@ -35,11 +36,21 @@ const (
 
 	notificationBufferSize = 10000 // max buffered notifications before codec is closed
 
+	defaultMaxRequestSize = 128 * 1024 // default maximum size of HTTP request bodies and websocket messages
+
 	MetadataApi     = "rpc"
-	DefaultIPCApis  = "admin,debug,eth,miner,net,personal,shh,txpool,web3"
+	DefaultIPCApis  = "admin,debug,eth,miner,net,personal,shh,txpool,wallet,web3"
 	DefaultHTTPApis = "eth,net,web3"
 )
 
+// DefaultLimits are the limits of a new server. Requests aren't bounded in
+// time or concurrency unless configured otherwise.
+var DefaultLimits = Limits{
+	ReadTimeout:    30 * time.Second,
+	IdleTimeout:    120 * time.Second,
+	MaxRequestSize: defaultMaxRequestSize,
+}
+
 // CodecOption specifies which type of messages this codec supports
 type CodecOption int
 
@@ -58,6 +69,7 @@ func NewServer() *Server {
 		subscriptions: make(subscriptionRegistry),
 		codecs:        set.New(),
 		run:           1,
+		limits:        DefaultLimits,
 	}
 
 	// register a default service which will provide meta information about the RPC service such as the services and
@@ -68,6 +80,58 @@ func NewServer() *Server {
 	return server
 }
 
+// SetLimits configures the resource limits of the server. It must be called
+// before the server starts serving requests.
+func (s *Server) SetLimits(limits Limits) {
+	s.limits = limits
+	s.inflight = nil
+	if limits.MaxInFlight > 0 {
+		s.inflight = make(chan struct{}, limits.MaxInFlight)
+	}
+}
+
+// Limits returns the resource limits of the server.
+func (s *Server) Limits() Limits {
+	return s.limits
+}
+
+// SetSlowRequestThreshold configures the execution time from which on method
+// calls are logged as slow, zero disables logging. It must be called before
+// the server starts serving requests.
+func (s *Server) SetSlowRequestThreshold(threshold time.Duration) {
+	s.slowThreshold = threshold
+}
+
+// SetAccessPolicy restricts the methods the server executes and the clients
+// the HTTP and websocket servers around it accept. It must be called before
+// the server starts serving requests.
+func (s *Server) SetAccessPolicy(policy AccessPolicy) {
+	s.access = policy
+}
+
+// allowed returns whether the access policy permits calling the given method
+// or subscription of a service.
+func (s *Server) allowed(service, method string) bool {
+	if service == MetadataApi {
+		return true
+	}
+	name := service + serviceMethodSeparator + method
+	for _, denied := range s.access.Deny {
+		if denied == service || denied == name {
+			return false
+		}
+	}
+	if len(s.access.Allow) == 0 {
+		return true
+	}
+	for _, allowed := range s.access.Allow {
+		if allowed == service || allowed == name {
+			return true
+		}
+	}
+	return false
+}
+
 // RPCService gives meta information about the server.
 // e.g. gives information about the loaded modules.
 type RPCService struct {
@@ -170,6 +234,12 @@ func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecO
 	s.codecs.Add(codec)
 	s.codecsMu.Unlock()
 
+	// the per connection limit stops reading requests while it is reached
+	var connSlots chan struct{}
+	if s.limits.MaxConnInFlight > 0 && !singleShot {
+		connSlots = make(chan struct{}, s.limits.MaxConnInFlight)
+	}
+
 	// test if the server is ordered to stop
 	for atomic.LoadInt32(&s.run) == 1 {
 		reqs, batch, err := s.readRequest(codec)
@@ -201,11 +271,25 @@ func (s *Server) serveRequest(codec ServerCodec, singleShot bool, options CodecO
 		} else if singleShot && !batch {
 			s.exec(ctx, codec, reqs[0])
 			return nil
-		} else if !singleShot && batch {
-			go s.execBatch(ctx, codec, reqs)
-		} else {
-			go s.exec(ctx, codec, reqs[0])
 		}
+
+		if connSlots != nil {
+			select {
+			case connSlots <- struct{}{}:
+			case <-codec.Closed():
+				return nil
+			}
+		}
+		go func(reqs []*serverRequest, batch bool) {
+			if batch {
+				s.execBatch(ctx, codec, reqs)
+			} else {
+				s.exec(ctx, codec, reqs[0])
+			}
+			if connSlots != nil {
+				<-connSlots
+			}
+		}(reqs, batch)
 	}
 
 	return nil
@@ -262,6 +346,10 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 		return codec.CreateErrorResponse(&req.id, req.err), nil
 	}
 
+	if !req.isUnsubscribe && !s.allowed(req.svcname, req.method) {
+		return codec.CreateErrorResponse(&req.id, &methodNotAllowedError{req.svcname, req.method}), nil
+	}
+
 	if req.isUnsubscribe { // cancel subscription, first param must be the subscription id
 		if len(req.args) >= 1 && req.args[0].Kind() == reflect.String {
 			notifier, supported := NotifierFromContext(ctx)
@@ -326,15 +414,84 @@ func (s *Server) handle(ctx context.Context, codec ServerCodec, req *serverReque
 	return codec.CreateResponse(req.id, reply[0].Interface()), nil
 }
 
+// handleLimited executes a request within the limits of the server. Method
+// calls take a slot of the requests in flight and are answered with an error
+// when none is free, or when they exceed the handler timeout. A call which
+// timed out holds its slot until the method returns. (Un)subscriptions are
+// exempt from the timeout.
+func (s *Server) handleLimited(ctx context.Context, codec ServerCodec, req *serverRequest) (interface{}, func()) {
+	if req.err != nil || req.isUnsubscribe || req.callb.isSubscribe {
+		return s.handle(ctx, codec, req)
+	}
+	if s.inflight != nil {
+		select {
+		case s.inflight <- struct{}{}:
+		default:
+			return codec.CreateErrorResponse(&req.id, &limitExceededError{}), nil
+		}
+	}
+	release := func() {
+		if s.inflight != nil {
+			<-s.inflight
+		}
+	}
+	if s.limits.HandlerTimeout <= 0 {
+		defer release()
+		return s.handle(ctx, codec, req)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.limits.HandlerTimeout)
+	defer cancel()
+
+	done := make(chan interface{}, 1)
+	go func() {
+		defer release()
+		response, _ := s.handle(ctx, codec, req)
+		done <- response
+	}()
+	select {
+	case response := <-done:
+		return response, nil
+	case <-ctx.Done():
+		glog.V(logger.Debug).Infof("RPC request %s%s%s timed out after %v", req.svcname, serviceMethodSeparator, req.callb.method.Name, s.limits.HandlerTimeout)
+		return codec.CreateErrorResponse(&req.id, &requestTimeoutError{}), nil
+	}
+}
+
+// instrument records the metrics of an executed request and logs it when it
+// took longer than the slow request threshold.
+func (s *Server) instrument(req *serverRequest, response interface{}, elapsed time.Duration) {
+	failed := req.err != nil
+	if res, ok := response.(*JSONResponse); ok && res.Error != nil {
+		failed = true
+	}
+	if req.err != nil || req.isUnsubscribe {
+		// unknown methods aren't tracked individually
+		metrics.RPCCalls.Mark(1)
+		if failed {
+			metrics.RPCErrors.Mark(1)
+		}
+		return
+	}
+	method := req.svcname + serviceMethodSeparator + req.method
+	metrics.MarkRPCCall(method, elapsed, failed)
+
+	if s.slowThreshold > 0 && elapsed >= s.slowThreshold {
+		glog.V(logger.Warn).Infof("Slow RPC request %s (params %d bytes) took %v", method, req.paramsSize, elapsed)
+	}
+}
+
 // exec executes the given request and writes the result back using the codec.
 func (s *Server) exec(ctx context.Context, codec ServerCodec, req *serverRequest) {
 	var response interface{}
 	var callback func()
+	start := time.Now()
 	if req.err != nil {
 		response = codec.CreateErrorResponse(&req.id, req.err)
 	} else {
-		response, callback = s.handle(ctx, codec, req)
+		response, callback = s.handleLimited(ctx, codec, req)
 	}
+	s.instrument(req, response, time.Since(start))
 
 	if err := codec.Write(response); err != nil {
 		glog.V(logger.Error).Infof("%v\n", err)
@@ -353,14 +510,16 @@ func (s *Server) execBatch(ctx context.Context, codec ServerCodec, requests []*s
 	responses := make([]interface{}, len(requests))
 	var callbacks []func()
 	for i, req := range requests {
+		start := time.Now()
 		if req.err != nil {
 			responses[i] = codec.CreateErrorResponse(&req.id, req.err)
 		} else {
 			var callback func()
-			if responses[i], callback = s.handle(ctx, codec, req); callback != nil {
+			if responses[i], callback = s.handleLimited(ctx, codec, req); callback != nil {
 				callbacks = append(callbacks, callback)
 			}
 		}
+		s.instrument(req, responses[i], time.Since(start))
 	}
 
 	if err := codec.Write(responses); err != nil {
@@ -408,7 +567,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, RPCErro
 
 		if r.isPubSub { // eth_subscribe, r.method contains the subscription method name
 			if callb, ok := svc.subscriptions[r.method]; ok {
-				requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+				requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.method, paramsSize: paramsSize(r.params), callb: callb}
 				if r.params != nil && len(callb.argTypes) > 0 {
 					argTypes := []reflect.Type{reflect.TypeOf("")}
 					argTypes = append(argTypes, callb.argTypes...)
@@ -425,7 +584,7 @@ func (s *Server) readRequest(codec ServerCodec) ([]*serverRequest, bool, RPCErro
 		}
 
 		if callb, ok := svc.callbacks[r.method]; ok { // lookup RPC method
-			requests[i] = &serverRequest{id: r.id, svcname: svc.name, callb: callb}
+			requests[i] = &serverRequest{id: r.id, svcname: svc.name, method: r.method, paramsSize: paramsSize(r.params), callb: callb}
 			if r.params != nil && len(callb.argTypes) > 0 {
 				if args, err := codec.ParseRequestArguments(callb.argTypes, r.params); err == nil {
 					requests[i].args = args
@@ -20,8 +20,14 @@ import (
 	"context"
 	"encoding/json"
 	"net"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/metrics"
 )
 
 type Service struct{}
@@ -152,3 +158,283 @@ func TestServerMethodExecution(t *testing.T) {
 func TestServerMethodWithCtx(t *testing.T) {
 	testServerMethodExecution(t, "echoWithCtx")
 }
+
+type SlowService struct {
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *SlowService) Wait(ctx context.Context) error {
+	s.started <- struct{}{}
+	select {
+	case <-s.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func newSlowServer(t *testing.T, limits Limits) (*SlowService, *json.Encoder, *json.Decoder) {
+	server := NewServer()
+	server.SetLimits(limits)
+	service := &SlowService{started: make(chan struct{}, 2), release: make(chan struct{})}
+	if err := server.RegisterName("slow", service); err != nil {
+		t.Fatal(err)
+	}
+
+	clientConn, serverConn := net.Pipe()
+	go server.ServeCodec(NewJSONCodec(serverConn), OptionMethodInvocation)
+
+	return service, json.NewEncoder(clientConn), json.NewDecoder(clientConn)
+}
+
+func TestServerHandlerTimeout(t *testing.T) {
+	service, out, in := newSlowServer(t, Limits{HandlerTimeout: 50 * time.Millisecond})
+	defer close(service.release)
+
+	if err := out.Encode(map[string]interface{}{"id": 1, "method": "slow_wait", "version": "2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	var response JSONResponse
+	if err := in.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error == nil || response.Error.Code != (&requestTimeoutError{}).Code() {
+		t.Fatalf("expected request timeout error, got %+v", response)
+	}
+}
+
+func TestServerMaxInFlight(t *testing.T) {
+	service, out, in := newSlowServer(t, Limits{MaxInFlight: 1})
+
+	if err := out.Encode(map[string]interface{}{"id": 1, "method": "slow_wait", "version": "2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	<-service.started
+
+	// the first call holds the only slot, the second one is rejected
+	if err := out.Encode(map[string]interface{}{"id": 2, "method": "slow_wait", "version": "2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	var response JSONResponse
+	if err := in.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error == nil || response.Error.Code != (&limitExceededError{}).Code() {
+		t.Fatalf("expected limit exceeded error, got %+v", response)
+	}
+
+	close(service.release)
+	response = JSONResponse{}
+	if err := in.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error != nil {
+		t.Fatalf("unexpected error: %v", response.Error)
+	}
+}
+
+func TestHTTPMaxRequestSize(t *testing.T) {
+	server := NewServer()
+	server.SetLimits(Limits{MaxRequestSize: 64})
+	httpsrv := httptest.NewServer(NewHTTPServer("*", nil, server).Handler)
+	defer httpsrv.Close()
+
+	body := `{"id":1,"method":"rpc_modules","version":"2.0","params":["` + strings.Repeat("a", 64) + `"]}`
+	resp, err := http.Post(httpsrv.URL, "application/json", strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected status %d, got %d", http.StatusRequestEntityTooLarge, resp.StatusCode)
+	}
+}
+
+func TestServerAccessPolicy(t *testing.T) {
+	server := NewServer()
+	server.SetAccessPolicy(AccessPolicy{
+		Allow: []string{"debug_traceTransaction", "eth"},
+		Deny:  []string{"eth_sendTransaction"},
+	})
+
+	tests := []struct {
+		service, method string
+		allowed         bool
+	}{
+		{"debug", "traceTransaction", true},
+		{"debug", "setHead", false},
+		{"eth", "getBalance", true},
+		{"eth", "sendTransaction", false},
+		{"personal", "unlockAccount", false},
+		{MetadataApi, "modules", true},
+	}
+	for _, tt := range tests {
+		if allowed := server.allowed(tt.service, tt.method); allowed != tt.allowed {
+			t.Errorf("%s_%s: allowed mismatch: have %v, want %v", tt.service, tt.method, allowed, tt.allowed)
+		}
+	}
+}
+
+func TestServerMethodNotAllowed(t *testing.T) {
+	server := NewServer()
+	server.SetAccessPolicy(AccessPolicy{Deny: []string{"test_echo"}})
+	if err := server.RegisterName("test", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(NewJSONCodec(serverConn), OptionMethodInvocation)
+
+	out, in := json.NewEncoder(clientConn), json.NewDecoder(clientConn)
+	request := map[string]interface{}{"id": 1, "method": "test_echo", "version": "2.0", "params": []interface{}{"s", 1, &Args{"abc"}}}
+	if err := out.Encode(request); err != nil {
+		t.Fatal(err)
+	}
+	var response JSONResponse
+	if err := in.Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	if response.Error == nil || response.Error.Code != (&methodNotAllowedError{}).Code() {
+		t.Fatalf("expected method not allowed error, got %+v", response)
+	}
+}
+
+func TestHTTPBearerToken(t *testing.T) {
+	server := NewServer()
+	server.SetAccessPolicy(AccessPolicy{Tokens: []string{"secret"}})
+	httpsrv := httptest.NewServer(NewHTTPServer("*", nil, server).Handler)
+	defer httpsrv.Close()
+
+	for token, status := range map[string]int{"": http.StatusUnauthorized, "wrong": http.StatusUnauthorized, "secret": http.StatusOK} {
+		req, _ := http.NewRequest("POST", httpsrv.URL, strings.NewReader(`{"id":1,"method":"rpc_modules","version":"2.0"}`))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("token %q: expected status %d, got %d", token, status, resp.StatusCode)
+		}
+	}
+}
+
+func TestHTTPVirtualHosts(t *testing.T) {
+	server := NewServer()
+	handler := NewHTTPServer("*", []string{"localhost", "node.example.org"}, server).Handler
+
+	tests := map[string]int{
+		"localhost:8545":        http.StatusOK,
+		"node.example.org":      http.StatusOK,
+		"NODE.example.org:8545": http.StatusOK,
+		"127.0.0.1:8545":        http.StatusOK,
+		"[::1]:8545":            http.StatusOK,
+		"attacker.example.org":  http.StatusForbidden,
+	}
+	for host, status := range tests {
+		req := httptest.NewRequest("POST", "http://"+host, strings.NewReader(`{"id":1,"method":"rpc_modules","version":"2.0"}`))
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != status {
+			t.Errorf("host %s: expected status %d, got %d", host, status, rec.Code)
+		}
+	}
+}
+
+func TestWrapHTTPHandler(t *testing.T) {
+	server := NewServer()
+	server.SetLimits(Limits{HandlerTimeout: 100 * time.Millisecond, MaxInFlight: 1})
+	server.SetAccessPolicy(AccessPolicy{Deny: []string{"denied"}})
+
+	var (
+		started = make(chan struct{})
+		unblock = make(chan struct{})
+	)
+	blocking := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-unblock
+	})
+	serve := func(handler http.Handler) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest("POST", "http://localhost/", strings.NewReader("{}")))
+		return rec
+	}
+	echo := WrapHTTPHandler("*", nil, server, "echo", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	if rec := serve(echo); rec.Code != http.StatusOK || rec.Body.String() != "ok" {
+		t.Fatalf("expected response ok, got %d %q", rec.Code, rec.Body.String())
+	}
+	if rec := serve(WrapHTTPHandler("*", nil, server, "denied", echo)); rec.Code != http.StatusForbidden {
+		t.Errorf("denied namespace: expected status %d, got %d", http.StatusForbidden, rec.Code)
+	}
+
+	// A timed out request keeps its slot until the handler returns
+	if rec := serve(WrapHTTPHandler("*", nil, server, "blocking", blocking)); rec.Code != http.StatusServiceUnavailable ||
+		!strings.Contains(rec.Body.String(), (&requestTimeoutError{}).Error()) {
+		t.Errorf("expected request timeout, got %d %q", rec.Code, rec.Body.String())
+	}
+	<-started
+	if rec := serve(echo); rec.Code != http.StatusServiceUnavailable ||
+		!strings.Contains(rec.Body.String(), (&limitExceededError{}).Error()) {
+		t.Errorf("expected limit exceeded, got %d %q", rec.Code, rec.Body.String())
+	}
+	if response := testServerRequest(t, server, "rpc_modules"); response.Error == nil || response.Error.Code != (&limitExceededError{}).Code() {
+		t.Errorf("expected method call to exceed the limit, got %+v", response)
+	}
+	close(unblock)
+
+	for i := 0; i < 50; i++ {
+		if rec := serve(echo); rec.Code == http.StatusOK {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Error("slot not released after the handler returned")
+}
+
+func TestServerMetrics(t *testing.T) {
+	server := NewServer()
+	if err := server.RegisterName("metered", new(Service)); err != nil {
+		t.Fatal(err)
+	}
+	testServerRequest(t, server, "metered_rets")
+
+	out, err := metrics.CollectToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var collected map[string]interface{}
+	if err := json.Unmarshal(out, &collected); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"rpc/call/metered_rets", "rpc/duration/metered_rets"} {
+		if _, ok := collected[name]; !ok {
+			t.Errorf("metric %s not registered", name)
+		}
+	}
+	if _, ok := collected["rpc/error/metered_rets"]; ok {
+		t.Errorf("error metric registered for successful call")
+	}
+}
+
+// testServerRequest sends a request without parameters to the server and
+// waits for its response.
+func testServerRequest(t *testing.T, server *Server, method string) *JSONResponse {
+	clientConn, serverConn := net.Pipe()
+	defer clientConn.Close()
+	go server.ServeCodec(NewJSONCodec(serverConn), OptionMethodInvocation)
+
+	if err := json.NewEncoder(clientConn).Encode(map[string]interface{}{"id": 1, "method": method, "version": "2.0"}); err != nil {
+		t.Fatal(err)
+	}
+	var response JSONResponse
+	if err := json.NewDecoder(clientConn).Decode(&response); err != nil {
+		t.Fatal(err)
+	}
+	return &response
+}
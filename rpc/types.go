@@ -23,6 +23,7 @@ import (
 	"reflect"
 	"strings"
 	"sync"
+	"time"
 
 	"gopkg.in/fatih/set.v0"
 )
@@ -57,6 +58,8 @@ type service struct {
 type serverRequest struct {
 	id            interface{}
 	svcname       string
+	method        string
+	paramsSize    int
 	callb         *callback
 	args          []reflect.Value
 	isUnsubscribe bool
@@ -76,6 +79,36 @@ type Server struct {
 	run      int32
 	codecsMu sync.Mutex
 	codecs   *set.Set
+
+	limits   Limits
+	inflight chan struct{} // slots of requests in flight, nil when unlimited
+
+	access        AccessPolicy
+	slowThreshold time.Duration // execution time from which on calls are logged, zero for never
+}
+
+// Limits bounds the resources a server spends on its clients. Zero values
+// disable the respective limit. The timeouts and the request size apply to the
+// HTTP and websocket servers created around the server.
+type Limits struct {
+	ReadTimeout  time.Duration // maximum duration for reading a request, or the websocket handshake
+	WriteTimeout time.Duration // maximum duration for writing a response, or a websocket message
+	IdleTimeout  time.Duration // maximum wait for the next request on a keep-alive HTTP connection
+
+	HandlerTimeout  time.Duration // maximum execution time of a method call
+	MaxRequestSize  int64         // maximum size of a request body or websocket message in bytes
+	MaxInFlight     int           // maximum number of method calls executing over all connections
+	MaxConnInFlight int           // maximum number of requests executing per connection
+}
+
+// AccessPolicy restricts the methods a server executes and the clients it
+// accepts. Methods are given as namespace_method, or as a bare namespace which
+// covers all of its methods and subscriptions. The methods of the rpc
+// namespace and unsubscribing are always allowed.
+type AccessPolicy struct {
+	Allow  []string // methods which can be called, empty allows all registered methods
+	Deny   []string // methods which can't be called, takes precedence over Allow
+	Tokens []string // bearer tokens accepted by the HTTP and websocket servers, empty disables authentication
 }
 
 // rpcRequest represents a raw incoming RPC request
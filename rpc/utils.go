@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
@@ -203,6 +204,15 @@ METHODS:
 	return callbacks, subscriptions
 }
 
+// paramsSize returns the encoded size of the parameters of a request, or 0 if
+// the codec doesn't provide them as raw JSON.
+func paramsSize(params interface{}) int {
+	if raw, ok := params.(json.RawMessage); ok {
+		return len(raw)
+	}
+	return 0
+}
+
 func newSubscriptionID() (string, error) {
 	var subid [16]byte
 	n, _ := rand.Read(subid[:])
@@ -17,11 +17,15 @@
 package rpc
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/ethereumproject/go-ethereum/logger"
 	"github.com/ethereumproject/go-ethereum/logger/glog"
@@ -31,16 +35,29 @@ import (
 
 // wsReaderWriterCloser reads and write payloads from and to a websocket  connection.
 type wsReaderWriterCloser struct {
-	c *websocket.Conn
+	c            *websocket.Conn
+	writeTimeout time.Duration // deadline for writing a single payload, zero for none
+	pending      []byte        // unread remainder of the last received message
 }
 
-// Read will read incoming payload data into p.
+// Read will read incoming payload data into p. Messages are received as a
+// whole, which enforces the maximum payload size of the connection.
 func (rw *wsReaderWriterCloser) Read(p []byte) (int, error) {
-	return rw.c.Read(p)
+	for len(rw.pending) == 0 {
+		if err := websocket.Message.Receive(rw.c, &rw.pending); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, rw.pending)
+	rw.pending = rw.pending[n:]
+	return n, nil
 }
 
 // Write writes p to the websocket.
 func (rw *wsReaderWriterCloser) Write(p []byte) (int, error) {
+	if rw.writeTimeout > 0 {
+		rw.c.SetWriteDeadline(time.Now().Add(rw.writeTimeout))
+	}
 	return rw.c.Write(p)
 }
 
@@ -87,19 +104,50 @@ func wsHandshakeValidator(allowedOrigins []string) func(*websocket.Config, *http
 	return f
 }
 
-// NewWSServer creates a new websocket RPC server around an API provider.
-func NewWSServer(allowedOrigins string, handler *Server) *http.Server {
-	return &http.Server{
-		Handler: websocket.Server{
-			Handshake: wsHandshakeValidator(strings.Split(allowedOrigins, ",")),
-			Handler: func(conn *websocket.Conn) {
-				handler.ServeCodec(NewJSONCodec(&wsReaderWriterCloser{conn}),
-					OptionMethodInvocation|OptionSubscriptions)
-			},
+// NewWSHandler creates a HTTP handler which upgrades requests to websocket
+// connections serving JSON-RPC requests to an API provider. The handshake is
+// rejected unless it carries a bearer token of the access policy of the API
+// provider, if any is configured, and addresses one of the given virtual
+// hosts. The write timeout of the API provider applies to every message and
+// larger messages than the maximum request size are rejected.
+func NewWSHandler(allowedOrigins string, vhosts []string, handler *Server) http.Handler {
+	limits := handler.Limits()
+	validateOrigin := wsHandshakeValidator(strings.Split(allowedOrigins, ","))
+	return newVirtualHostHandler(vhosts, websocket.Server{
+		Handshake: func(cfg *websocket.Config, req *http.Request) error {
+			if !handler.authorized(req) {
+				return fmt.Errorf("missing or invalid bearer token")
+			}
+			return validateOrigin(cfg, req)
+		},
+		Handler: func(conn *websocket.Conn) {
+			// the deadlines of the handshake outlive the hijacked connection
+			conn.SetDeadline(time.Time{})
+			if limits.MaxRequestSize > 0 {
+				conn.MaxPayloadBytes = int(limits.MaxRequestSize)
+			}
+			handler.ServeCodec(NewJSONCodec(&wsReaderWriterCloser{c: conn, writeTimeout: limits.WriteTimeout}),
+				OptionMethodInvocation|OptionSubscriptions)
 		},
+	})
+}
+
+// NewWSServer creates a new websocket RPC server around an API provider, see
+// NewWSHandler. The read timeout of the API provider limits the handshake.
+func NewWSServer(allowedOrigins string, vhosts []string, handler *Server) *http.Server {
+	return &http.Server{
+		ReadTimeout: handler.Limits().ReadTimeout,
+		Handler:     NewWSHandler(allowedOrigins, vhosts, handler),
 	}
 }
 
+// IsWebsocket returns whether the HTTP request asks for an upgrade to the
+// websocket protocol.
+func IsWebsocket(r *http.Request) bool {
+	return strings.ToLower(r.Header.Get("Upgrade")) == "websocket" &&
+		strings.Contains(strings.ToLower(r.Header.Get("Connection")), "upgrade")
+}
+
 // wsClient represents a RPC client that communicates over websockets with a
 // RPC server.
 type wsClient struct {
@@ -108,6 +156,31 @@ type wsClient struct {
 	conn     *websocket.Conn
 }
 
+// DialWebsocket creates a client which connects to the given websocket
+// endpoint. The origin defaults to the local hostname when empty. It
+// reconnects on the next request when the connection is lost.
+func DialWebsocket(ctx context.Context, endpoint, origin string) (*ClientConn, error) {
+	if origin == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, err
+		}
+		origin = "http://" + hostname
+	}
+	config, err := websocket.NewConfig(endpoint, origin)
+	if err != nil {
+		return nil, err
+	}
+	return newStreamClient(ctx, func(ctx context.Context) (io.ReadWriteCloser, error) {
+		dialer := new(net.Dialer)
+		if deadline, ok := ctx.Deadline(); ok {
+			dialer.Deadline = deadline
+		}
+		config.Dialer = dialer
+		return websocket.DialConfig(config)
+	})
+}
+
 // connection will return a websocket connection to the RPC server. It will
 // (re)connect when necessary.
 func (client *wsClient) connection() (*websocket.Conn, error) {
@@ -0,0 +1,151 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+// Package signer implements an external signer, which holds the keys of the
+// accounts of a node in a separate process and approves each signature through
+// a pluggable user interface. It serves the signing protocol of
+// accounts.ExternalSigner.
+package signer
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+	"github.com/ethereumproject/go-ethereum/rlp"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+var (
+	ErrRejected = errors.New("request rejected")
+
+	errUnknownAccount = errors.New("unknown account")
+)
+
+// SignerAPI serves the signing protocol in the accounts.ExternalSignerNamespace
+// namespace. Every signature is approved by the user interface and recorded in
+// the audit log.
+type SignerAPI struct {
+	am      *accounts.Manager
+	ui      UI
+	audit   *AuditLog
+	chainID *big.Int
+}
+
+// NewSignerAPI creates the signing API over the keys of the given manager. If
+// chainID is set, only transactions protected for that chain are signed. The
+// audit log is optional.
+func NewSignerAPI(am *accounts.Manager, ui UI, audit *AuditLog, chainID *big.Int) *SignerAPI {
+	return &SignerAPI{am: am, ui: ui, audit: audit, chainID: chainID}
+}
+
+// NewServer returns a RPC server serving the signing API.
+func NewServer(api *SignerAPI) (*rpc.Server, error) {
+	srv := rpc.NewServer()
+	if err := srv.RegisterName(accounts.ExternalSignerNamespace, api); err != nil {
+		return nil, err
+	}
+	return srv, nil
+}
+
+// List returns the addresses of the accounts.
+func (api *SignerAPI) List() []common.Address {
+	addrs := []common.Address{}
+	for _, acc := range api.am.Accounts() {
+		addrs = append(addrs, acc.Address)
+	}
+	api.audit.Log("list", nil, addrs, nil)
+	return addrs
+}
+
+// SignTransaction signs the given transaction after the user approved it.
+func (api *SignerAPI) SignTransaction(args accounts.SignTxArgs) (*accounts.SignTxResult, error) {
+	res, err := api.signTransaction(args)
+	api.audit.Log("signTransaction", args, res, err)
+	return res, err
+}
+
+func (api *SignerAPI) signTransaction(args accounts.SignTxArgs) (*accounts.SignTxResult, error) {
+	if !api.am.HasAddress(args.From) {
+		return nil, errUnknownAccount
+	}
+	tx, signer, err := args.Transaction()
+	if err != nil {
+		return nil, err
+	}
+	if api.chainID != nil && (args.ChainID == nil || args.ChainID.BigInt().Cmp(api.chainID) != 0) {
+		return nil, fmt.Errorf("transaction isn't protected for chain %v", api.chainID)
+	}
+	resp, err := api.ui.ApproveTx(&TxRequest{Args: args, Transaction: tx})
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Approved {
+		return nil, ErrRejected
+	}
+	var signed *types.Transaction
+	if resp.Password != "" {
+		signed, err = api.am.SignTxWithPassphrase(args.From, resp.Password, tx, signer)
+	} else {
+		signed, err = api.am.SignTx(args.From, tx, signer)
+	}
+	if err != nil {
+		return nil, err
+	}
+	raw, err := rlp.EncodeToBytes(signed)
+	if err != nil {
+		return nil, err
+	}
+	glog.V(logger.Info).Infof("Signed transaction %x from %x", signed.Hash(), args.From)
+	return &accounts.SignTxResult{Raw: common.ToHex(raw), Hash: signed.Hash()}, nil
+}
+
+// SignHash signs the given hash after the user approved it. As the signed data
+// is unknown, the user should only approve hashes of an expected origin.
+func (api *SignerAPI) SignHash(addr common.Address, hash common.Hash) (string, error) {
+	sig, err := api.signHash(addr, hash)
+	api.audit.Log("signHash", &SignHashRequest{Address: addr, Hash: hash}, sig, err)
+	return sig, err
+}
+
+func (api *SignerAPI) signHash(addr common.Address, hash common.Hash) (string, error) {
+	if !api.am.HasAddress(addr) {
+		return "", errUnknownAccount
+	}
+	resp, err := api.ui.ApproveSignHash(&SignHashRequest{Address: addr, Hash: hash})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Approved {
+		return "", ErrRejected
+	}
+	var sig []byte
+	if resp.Password != "" {
+		sig, err = api.am.SignWithPassphrase(addr, resp.Password, hash[:])
+	} else {
+		sig, err = api.am.Sign(addr, hash[:])
+	}
+	if err != nil {
+		return "", err
+	}
+	glog.V(logger.Info).Infof("Signed hash %x with %x", hash, addr)
+	return common.ToHex(sig), nil
+}
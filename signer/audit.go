@@ -0,0 +1,76 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereumproject/go-ethereum/logger"
+	"github.com/ethereumproject/go-ethereum/logger/glog"
+)
+
+// AuditEntry is a request to the signer and its outcome, written to the audit
+// log as a line of JSON.
+type AuditEntry struct {
+	Time    time.Time   `json:"time"`
+	Method  string      `json:"method"`
+	Request interface{} `json:"request,omitempty"`
+	Result  interface{} `json:"result,omitempty"`
+	Error   string      `json:"error,omitempty"`
+}
+
+// AuditLog records all requests to the signer. A nil log records nothing.
+type AuditLog struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewAuditLog creates an audit log appending to the given file.
+func NewAuditLog(file string) (*AuditLog, error) {
+	f, err := os.OpenFile(file, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0600)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditLog{w: f}, nil
+}
+
+// Log records a request with its result or error.
+func (l *AuditLog) Log(method string, request, result interface{}, err error) {
+	if l == nil {
+		return
+	}
+	entry := AuditEntry{Time: time.Now().UTC(), Method: method, Request: request}
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.Result = result
+	}
+	data, jerr := json.Marshal(entry)
+	if jerr != nil {
+		glog.V(logger.Error).Infof("can't encode audit entry: %v", jerr)
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.w.Write(append(data, '\n')); err != nil {
+		glog.V(logger.Error).Infof("can't write audit log: %v", err)
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/core/types"
+	"github.com/ethereumproject/go-ethereum/crypto"
+	"github.com/ethereumproject/go-ethereum/rpc"
+)
+
+var (
+	testKey, _  = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+	testAddress = crypto.PubkeyToAddress(testKey.PublicKey)
+)
+
+// testUI answers all requests with a fixed response.
+type testUI struct {
+	resp     Response
+	requests int
+}
+
+func (ui *testUI) ApproveTx(req *TxRequest) (Response, error) {
+	ui.requests++
+	return ui.resp, nil
+}
+
+func (ui *testUI) ApproveSignHash(req *SignHashRequest) (Response, error) {
+	ui.requests++
+	return ui.resp, nil
+}
+
+// newTestSigner serves a signer holding the test key over IPC and returns a
+// manager using it as external signer.
+func newTestSigner(t *testing.T, ui UI, chainID *big.Int) (*accounts.Manager, string, func()) {
+	dir, err := ioutil.TempDir("", "eth-signer-test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	am, err := accounts.NewManager(filepath.Join(dir, "signer"), accounts.LightScryptN, accounts.LightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := am.ImportECDSA(testKey, "foo"); err != nil {
+		t.Fatal(err)
+	}
+	audit, err := NewAuditLog(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := NewServer(NewSignerAPI(am, ui, audit, chainID))
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := filepath.Join(dir, "signer.ipc")
+	listener, err := rpc.CreateIPCListener(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go srv.ServeCodec(rpc.NewJSONCodec(conn), rpc.OptionMethodInvocation)
+		}
+	}()
+
+	signer, err := accounts.NewExternalSigner(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node, err := accounts.NewManager(filepath.Join(dir, "node"), accounts.LightScryptN, accounts.LightScryptP, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	node.UseExternalSigner(signer)
+
+	return node, dir, func() {
+		signer.Close()
+		listener.Close()
+		os.RemoveAll(dir)
+	}
+}
+
+func TestExternalSigner(t *testing.T) {
+	ui := &testUI{resp: Response{Approved: true, Password: "foo"}}
+	am, dir, cleanup := newTestSigner(t, ui, nil)
+	defer cleanup()
+
+	if accs := am.Accounts(); len(accs) != 1 || accs[0].Address != testAddress {
+		t.Fatalf("accounts mismatch: %v", accs)
+	}
+	if !am.HasAddress(testAddress) {
+		t.Error("signer account missing")
+	}
+
+	signer := types.NewChainIdSigner(big.NewInt(61))
+	tx := types.NewTransaction(3, common.Address{1}, big.NewInt(100), big.NewInt(21000), big.NewInt(1), nil)
+	signed, err := am.SignTx(testAddress, tx, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if from, err := signed.From(); err != nil || from != testAddress {
+		t.Errorf("sender mismatch: have %x (%v), want %x", from, err, testAddress)
+	}
+	if signed.ChainId().Cmp(big.NewInt(61)) != 0 || signed.Nonce() != 3 {
+		t.Errorf("signed transaction mismatch: %v", signed)
+	}
+
+	hash := crypto.Keccak256([]byte("hello"))
+	sig, err := am.Sign(testAddress, hash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pub, err := crypto.SigToPub(hash, sig); err != nil || crypto.PubkeyToAddress(*pub) != testAddress {
+		t.Errorf("hash signer mismatch: %v", err)
+	}
+
+	// Rejected requests fail, keys can't be used by the node
+	ui.resp = Response{}
+	if _, err := am.SignTx(testAddress, tx, signer); err == nil || !strings.Contains(err.Error(), ErrRejected.Error()) {
+		t.Errorf("rejected transaction: have error %v, want %v", err, ErrRejected)
+	}
+	if err := am.Unlock(accounts.Account{Address: testAddress}, "foo"); err != accounts.ErrExternalSigner {
+		t.Errorf("unlock: have error %v, want %v", err, accounts.ErrExternalSigner)
+	}
+	if _, err := am.NewAccount("bar"); err != accounts.ErrExternalSigner {
+		t.Errorf("new account: have error %v, want %v", err, accounts.ErrExternalSigner)
+	}
+	if ui.requests != 3 {
+		t.Errorf("UI got %d requests, want 3", ui.requests)
+	}
+
+	// All requests are in the audit log
+	data, err := ioutil.ReadFile(filepath.Join(dir, "audit.log"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var methods []string
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatal(err)
+		}
+		methods = append(methods, entry.Method)
+		if entry.Method == "signTransaction" && entry.Error != "" && entry.Error != ErrRejected.Error() {
+			t.Errorf("unexpected error in audit log: %s", entry.Error)
+		}
+	}
+	if last := methods[len(methods)-1]; last != "signTransaction" || len(methods) < 4 {
+		t.Errorf("audit log mismatch: %v", methods)
+	}
+}
+
+func TestExternalSignerChainID(t *testing.T) {
+	am, _, cleanup := newTestSigner(t, &testUI{resp: Response{Approved: true, Password: "foo"}}, big.NewInt(61))
+	defer cleanup()
+
+	tx := types.NewTransaction(0, common.Address{1}, big.NewInt(100), big.NewInt(21000), big.NewInt(1), nil)
+	if _, err := am.SignTx(testAddress, tx, types.BasicSigner{}); err == nil {
+		t.Error("signed transaction without replay protection")
+	}
+	if _, err := am.SignTx(testAddress, tx, types.NewChainIdSigner(big.NewInt(62))); err == nil {
+		t.Error("signed transaction for other chain")
+	}
+	if _, err := am.SignTx(testAddress, tx, types.NewChainIdSigner(big.NewInt(61))); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRulesUI(t *testing.T) {
+	to := common.Address{1}
+	ui := NewRulesUI(&accounts.SigningRules{Transactions: []accounts.TxRule{
+		{To: []common.Address{to}, MaxValue: rpc.NewHexNumber(1000), Selectors: []string{"0x"}},
+	}})
+
+	tests := []struct {
+		tx   *types.Transaction
+		want bool
+	}{
+		{types.NewTransaction(0, to, big.NewInt(1000), big.NewInt(21000), big.NewInt(1), nil), true},
+		{types.NewTransaction(0, to, big.NewInt(1001), big.NewInt(21000), big.NewInt(1), nil), false},
+		{types.NewTransaction(0, common.Address{2}, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil), false},
+		{types.NewTransaction(0, to, big.NewInt(1), big.NewInt(21000), big.NewInt(1), []byte{1}), false},
+		{types.NewContractCreation(0, big.NewInt(1), big.NewInt(21000), big.NewInt(1), nil), false},
+	}
+	for i, test := range tests {
+		resp, err := ui.ApproveTx(&TxRequest{Args: accounts.NewSignTxArgs(testAddress, test.tx, types.BasicSigner{}), Transaction: test.tx})
+		if err != nil || resp.Approved != test.want {
+			t.Errorf("test %d: have approval %v (%v), want %v", i, resp.Approved, err, test.want)
+		}
+	}
+	if resp, _ := ui.ApproveSignHash(&SignHashRequest{Address: testAddress}); resp.Approved {
+		t.Error("hash signing approved")
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2015 The go-ethereum Authors
+// This file is part of the go-ethereum library.
+//
+// The go-ethereum library is free software: you can redistribute it and/or modify
+// it under the terms of the GNU Lesser General Public License as published by
+// the Free Software Foundation, either version 3 of the License, or
+// (at your option) any later version.
+//
+// The go-ethereum library is distributed in the hope that it will be useful,
+// but WITHOUT ANY WARRANTY; without even the implied warranty of
+// MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+// GNU Lesser General Public License for more details.
+//
+// You should have received a copy of the GNU Lesser General Public License
+// along with the go-ethereum library. If not, see <http://www.gnu.org/licenses/>.
+
+package signer
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/ethereumproject/go-ethereum/accounts"
+	"github.com/ethereumproject/go-ethereum/common"
+	"github.com/ethereumproject/go-ethereum/console"
+	"github.com/ethereumproject/go-ethereum/core/types"
+)
+
+// TxRequest is a request to sign a transaction.
+type TxRequest struct {
+	Args        accounts.SignTxArgs
+	Transaction *types.Transaction
+}
+
+// SignHashRequest is a request to sign a hash of unknown data.
+type SignHashRequest struct {
+	Address common.Address `json:"address"`
+	Hash    common.Hash    `json:"hash"`
+}
+
+// Response is the decision of the user interface on a request. If the account
+// isn't unlocked, the password decrypts its key for the signing.
+type Response struct {
+	Approved bool
+	Password string
+}
+
+// UI approves the requests to the signer. Requests may arrive concurrently.
+type UI interface {
+	// ApproveTx decides on a request to sign a transaction.
+	ApproveTx(req *TxRequest) (Response, error)
+
+	// ApproveSignHash decides on a request to sign a hash.
+	ApproveSignHash(req *SignHashRequest) (Response, error)
+}
+
+// CommandlineUI shows the requests on the terminal and asks the user to
+// approve them and to enter the passphrase of the account.
+type CommandlineUI struct {
+	mu       sync.Mutex
+	prompter console.UserPrompter
+	out      io.Writer
+}
+
+// NewCommandlineUI creates a user interface prompting on the given prompter,
+// printing the requests to out.
+func NewCommandlineUI(prompter console.UserPrompter, out io.Writer) *CommandlineUI {
+	return &CommandlineUI{prompter: prompter, out: out}
+}
+
+// ApproveTx shows the transaction and asks for approval.
+func (ui *CommandlineUI) ApproveTx(req *TxRequest) (Response, error) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	tx := req.Transaction
+	fmt.Fprintln(ui.out, "-------- Transaction signing request --------")
+	fmt.Fprintf(ui.out, "from:     %s\n", req.Args.From.Hex())
+	if to := tx.To(); to != nil {
+		fmt.Fprintf(ui.out, "to:       %s\n", to.Hex())
+	} else {
+		fmt.Fprintln(ui.out, "to:       <contract creation>")
+	}
+	fmt.Fprintf(ui.out, "value:    %v wei\n", tx.Value())
+	fmt.Fprintf(ui.out, "gas:      %v\n", tx.Gas())
+	fmt.Fprintf(ui.out, "gasPrice: %v wei\n", tx.GasPrice())
+	fmt.Fprintf(ui.out, "nonce:    %d\n", tx.Nonce())
+	if data := tx.Data(); len(data) > 0 {
+		fmt.Fprintf(ui.out, "data:     %s\n", common.ToHex(data))
+	}
+	if req.Args.ChainID != nil {
+		fmt.Fprintf(ui.out, "chainId:  %v\n", req.Args.ChainID.BigInt())
+	} else {
+		fmt.Fprintln(ui.out, "chainId:  <none, valid on all chains>")
+	}
+	return ui.approve()
+}
+
+// ApproveSignHash shows the hash and asks for approval.
+func (ui *CommandlineUI) ApproveSignHash(req *SignHashRequest) (Response, error) {
+	ui.mu.Lock()
+	defer ui.mu.Unlock()
+
+	fmt.Fprintln(ui.out, "-------- Hash signing request --------")
+	fmt.Fprintln(ui.out, "WARNING: the signed data is unknown, it may authorize anything")
+	fmt.Fprintf(ui.out, "account:  %s\n", req.Address.Hex())
+	fmt.Fprintf(ui.out, "hash:     %s\n", req.Hash.Hex())
+	return ui.approve()
+}
+
+func (ui *CommandlineUI) approve() (Response, error) {
+	ok, err := ui.prompter.PromptConfirm("Approve?")
+	if err != nil || !ok {
+		return Response{}, err
+	}
+	password, err := ui.prompter.PromptPassword("Passphrase (empty if unlocked): ")
+	if err != nil {
+		return Response{}, err
+	}
+	return Response{Approved: true, Password: password}, nil
+}
+
+// RulesUI approves the requests allowed by signing rules without asking the
+// user and rejects any other. The accounts must be unlocked in the signer, as
+// no passphrase is given. The rules should be set on the account manager as
+// well, which counts the signed transactions against the daily limits.
+type RulesUI struct {
+	rules *accounts.SigningRules
+}
+
+// NewRulesUI creates a user interface approving what the rules allow.
+func NewRulesUI(rules *accounts.SigningRules) *RulesUI {
+	return &RulesUI{rules: rules}
+}
+
+// ApproveTx approves a transaction a rule allows within its daily limits.
+func (ui *RulesUI) ApproveTx(req *TxRequest) (Response, error) {
+	if err := ui.rules.Allows(req.Args.From, req.Transaction); err != nil {
+		return Response{}, nil
+	}
+	return Response{Approved: true}, nil
+}
+
+// ApproveSignHash approves signing hashes if the rules allow it.
+func (ui *RulesUI) ApproveSignHash(req *SignHashRequest) (Response, error) {
+	return Response{Approved: ui.rules.SignHash}, nil
+}